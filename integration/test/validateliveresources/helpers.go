@@ -103,7 +103,7 @@ func NewVMCapabilitiesFactory(t *testing.T, logger micrologger.Logger) vmcapabil
 
 	var vmCapabilities vmcapabilities.Factory
 	{
-		vmCapabilities, err = vmcapabilities.NewFactory(logger)
+		vmCapabilities, err = vmcapabilities.NewFactory(vmcapabilities.FactoryConfig{Logger: logger})
 		if err != nil {
 			t.Fatal(err)
 		}
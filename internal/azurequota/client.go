@@ -0,0 +1,47 @@
+package azurequota
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/giantswarm/microerror"
+)
+
+type Azure struct {
+	usageClient *compute.UsageClient
+}
+
+type AzureConfig struct {
+	UsageClient *compute.UsageClient
+}
+
+func NewAzureAPI(c AzureConfig) API {
+	return &Azure{usageClient: c.UsageClient}
+}
+
+func (a *Azure) List(ctx context.Context, location string) ([]Usage, error) {
+	iterator, err := a.usageClient.ListComplete(ctx, location)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var usages []Usage
+	for iterator.NotDone() {
+		usage := iterator.Value()
+		if usage.Name != nil {
+			usages = append(usages, Usage{
+				Name:    to.String(usage.Name.Value),
+				Current: int(to.Int32(usage.CurrentValue)),
+				Limit:   int(to.Int64(usage.Limit)),
+			})
+		}
+
+		err := iterator.NextWithContext(ctx)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	return usages, nil
+}
@@ -0,0 +1,31 @@
+package azurequota
+
+import (
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var insufficientQuotaError = &microerror.Error{
+	Kind: "insufficientQuotaError",
+}
+
+// IsInsufficientQuota asserts insufficientQuotaError.
+func IsInsufficientQuota(err error) bool {
+	return microerror.Cause(err) == insufficientQuotaError
+}
+
+func init() {
+	errors.RegisterValidationErrors(
+		insufficientQuotaError,
+	)
+}
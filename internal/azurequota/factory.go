@@ -0,0 +1,101 @@
+package azurequota
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/internal/capzcredentials"
+)
+
+type FactoryConfig struct {
+	// Credentials looks up the Azure credentials of the clusters. It is
+	// optional, when nil they are looked up on every call.
+	Credentials *capzcredentials.Resolver
+	Logger      micrologger.Logger
+}
+
+// FactoryImpl creates and caches a VCPUQuota client per Azure credentials, and
+// drops them with their credentials, like vmcapabilities.FactoryImpl. It is
+// safe for concurrent use.
+type FactoryImpl struct {
+	credentials *capzcredentials.Resolver
+	logger      micrologger.Logger
+
+	mutex sync.RWMutex
+	cache map[string]*VCPUQuota
+}
+
+func NewFactory(config FactoryConfig) (*FactoryImpl, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	f := &FactoryImpl{
+		cache:       make(map[string]*VCPUQuota),
+		credentials: config.Credentials,
+		logger:      config.Logger,
+	}
+	f.credentials.OnInvalidate(f.drop)
+
+	return f, nil
+}
+
+func (f *FactoryImpl) GetClient(ctx context.Context, ctrlClient client.Client, objectMeta v1.ObjectMeta) (*VCPUQuota, error) {
+	azureCredentials, err := f.credentials.GetAzureCredentials(ctx, ctrlClient, objectMeta)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	key := azureCredentials.CacheKey()
+
+	f.mutex.RLock()
+	q, hit := f.cache[key]
+	f.mutex.RUnlock()
+	if hit {
+		f.logger.Debugf(ctx, "VCPUQuota client found in cache for subscription %q", azureCredentials.SubscriptionID)
+		return q, nil
+	}
+
+	f.logger.Debugf(ctx, "Initializing VCPUQuota client for subscription %q", azureCredentials.SubscriptionID)
+
+	var usageClient compute.UsageClient
+	{
+		authorizer, err := azureCredentials.Authorizer()
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		usageClient = compute.NewUsageClient(azureCredentials.SubscriptionID)
+		usageClient.Client.Authorizer = authorizer
+	}
+
+	q, err = New(Config{
+		Azure:  NewAzureAPI(AzureConfig{UsageClient: &usageClient}),
+		Logger: f.logger,
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if cached, hit := f.cache[key]; hit {
+		return cached, nil
+	}
+	f.cache[key] = q
+
+	return q, nil
+}
+
+// drop removes the client of the given credentials from the cache.
+func (f *FactoryImpl) drop(credentials capzcredentials.AzureCredentials) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	delete(f.cache, credentials.CacheKey())
+}
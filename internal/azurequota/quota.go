@@ -0,0 +1,98 @@
+package azurequota
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+)
+
+// UsageRegionalVCPUs is the name of the quota of the total vCPUs of all VM
+// families in a location.
+const UsageRegionalVCPUs = "cores"
+
+type Config struct {
+	Azure  API
+	Logger micrologger.Logger
+}
+
+// VCPUQuota checks requested vCPUs against the regional and VM family vCPU
+// quotas of a subscription. The usages are listed on every check, as they
+// change whenever VMs are created or deleted.
+type VCPUQuota struct {
+	azure  API
+	logger micrologger.Logger
+}
+
+func New(config Config) (*VCPUQuota, error) {
+	if config.Azure == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Azure must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	q := &VCPUQuota{
+		azure:  config.Azure,
+		logger: config.Logger,
+	}
+
+	return q, nil
+}
+
+// CheckVCPUs returns insufficientQuotaError when the given number of vCPUs of
+// the VM family exceeds the remaining regional or family vCPU quota of the
+// location. Quotas which are not listed by the API are not checked.
+//
+// When the usages can't be listed the vCPUs are allowed, as Azure rejects
+// scaling over the quota anyway. The check only gives an earlier and clearer
+// error.
+func (q *VCPUQuota) CheckVCPUs(ctx context.Context, location string, family string, cpus int) error {
+	if cpus <= 0 {
+		return nil
+	}
+
+	usages, err := q.azure.List(ctx, location)
+	if err != nil {
+		q.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("unable to list the vCPU usage in %s, not checking the Azure quota", location), "stack", microerror.JSON(err))
+		return nil
+	}
+
+	for _, name := range []string{UsageRegionalVCPUs, family} {
+		usage, ok := findUsage(usages, name)
+		if !ok {
+			continue
+		}
+
+		remaining := usage.Limit - usage.Current
+		if cpus > remaining {
+			return microerror.Maskf(insufficientQuotaError, "%d vCPUs of VM family %s are requested, but only %d of the %d vCPUs of the Azure quota %#q are left in %s. Please request a quota increase from Azure or use a smaller node pool", cpus, family, max(remaining, 0), usage.Limit, usage.Name, location)
+		}
+	}
+
+	return nil
+}
+
+func findUsage(usages []Usage, name string) (Usage, bool) {
+	if name == "" {
+		return Usage{}, false
+	}
+
+	for _, usage := range usages {
+		if strings.EqualFold(usage.Name, name) {
+			return usage, true
+		}
+	}
+
+	return Usage{}, false
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
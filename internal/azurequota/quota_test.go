@@ -0,0 +1,121 @@
+package azurequota
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/giantswarm/micrologger"
+)
+
+type stubAPI struct {
+	usages []Usage
+	err    error
+}
+
+func (s *stubAPI) List(_ context.Context, _ string) ([]Usage, error) {
+	return s.usages, s.err
+}
+
+func TestCheckVCPUs(t *testing.T) {
+	usages := []Usage{
+		{Name: UsageRegionalVCPUs, Current: 80, Limit: 100},
+		{Name: "standardDSv3Family", Current: 10, Limit: 20},
+		{Name: "standardNCSv3Family", Current: 0, Limit: 0},
+	}
+
+	testCases := []struct {
+		name         string
+		api          API
+		family       string
+		cpus         int
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: within the family and regional quota",
+			api:          &stubAPI{usages: usages},
+			family:       "standardDSv3Family",
+			cpus:         8,
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 1: exactly the remaining family quota",
+			api:          &stubAPI{usages: usages},
+			family:       "standardDSv3Family",
+			cpus:         10,
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 2: exceeding the family quota",
+			api:          &stubAPI{usages: usages},
+			family:       "standardDSv3Family",
+			cpus:         12,
+			errorMatcher: IsInsufficientQuota,
+		},
+		{
+			name:         "case 3: exceeding the regional quota of a family without its own quota",
+			api:          &stubAPI{usages: usages},
+			family:       "standardFSv2Family",
+			cpus:         24,
+			errorMatcher: IsInsufficientQuota,
+		},
+		{
+			name:         "case 4: family without any quota",
+			api:          &stubAPI{usages: usages},
+			family:       "standardNCSv3Family",
+			cpus:         6,
+			errorMatcher: IsInsufficientQuota,
+		},
+		{
+			name:         "case 5: family name in a different case",
+			api:          &stubAPI{usages: usages},
+			family:       "StandardDSv3Family",
+			cpus:         12,
+			errorMatcher: IsInsufficientQuota,
+		},
+		{
+			name:         "case 6: no vCPUs requested",
+			api:          &stubAPI{usages: usages},
+			family:       "standardNCSv3Family",
+			cpus:         0,
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 7: usages can't be listed",
+			api:          &stubAPI{err: errors.New("throttled")},
+			family:       "standardDSv3Family",
+			cpus:         1000,
+			errorMatcher: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, err := micrologger.New(micrologger.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			q, err := New(Config{
+				Azure:  tc.api,
+				Logger: logger,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = q.CheckVCPUs(context.Background(), "westeurope", tc.family, tc.cpus)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package azurequota
+
+import (
+	"context"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// API lists the compute usages of a subscription in a location.
+type API interface {
+	List(ctx context.Context, location string) ([]Usage, error)
+}
+
+type Factory interface {
+	GetClient(context.Context, client.Client, v1.ObjectMeta) (*VCPUQuota, error)
+}
+
+// Usage is the current usage and limit of a compute quota, e.g. the vCPUs of
+// a VM family.
+type Usage struct {
+	// Name is the name of the quota, "cores" for the regional vCPUs and the
+	// family name, e.g. "standardDSv3Family", for the vCPUs of a VM family.
+	Name    string
+	Current int
+	Limit   int
+}
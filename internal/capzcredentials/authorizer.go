@@ -0,0 +1,170 @@
+package capzcredentials
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/adal"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/giantswarm/microerror"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+const (
+	// federatedTokenFileEnv is the environment variable the Azure workload
+	// identity webhook sets to the path of the projected service account
+	// token.
+	federatedTokenFileEnv = "AZURE_FEDERATED_TOKEN_FILE"
+	// defaultFederatedTokenFile is where the Azure workload identity webhook
+	// projects the service account token by default.
+	defaultFederatedTokenFile = "/var/run/secrets/azure/tokens/azure-identity-token"
+)
+
+// Authorizer returns the authorizer of the Azure Resource Manager API for the
+// type of the credentials.
+func (c *AzureCredentials) Authorizer() (autorest.Authorizer, error) {
+	resource := azure.PublicCloud.ResourceManagerEndpoint
+
+	var token *adal.ServicePrincipalToken
+	switch c.Type {
+	// Only the legacy credentials have no type, AzureClusterIdentities without
+	// one are refused when their credentials are looked up.
+	case "", capz.ServicePrincipal, capz.ManualServicePrincipal:
+		oauthConfig, err := adal.NewOAuthConfig(azure.PublicCloud.ActiveDirectoryEndpoint, c.TenantID)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		token, err = adal.NewServicePrincipalToken(*oauthConfig, c.ClientID, c.ClientSecret, resource)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	case capz.ServicePrincipalCertificate:
+		certificate, privateKey, err := decodeCertificate(c.ClientCertificate, c.ClientCertificatePassword)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		oauthConfig, err := adal.NewOAuthConfig(azure.PublicCloud.ActiveDirectoryEndpoint, c.TenantID)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		token, err = adal.NewServicePrincipalTokenFromCertificate(*oauthConfig, c.ClientID, certificate, privateKey, resource)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	case capz.UserAssignedMSI:
+		options := &adal.ManagedIdentityOptions{ClientID: c.ClientID}
+		if c.ClientID == "" {
+			options.IdentityResourceID = c.ResourceID
+		}
+		var err error
+		token, err = adal.NewServicePrincipalTokenFromManagedIdentity(resource, options)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	case IdentityTypeWorkloadIdentity:
+		oauthConfig, err := adal.NewOAuthConfig(azure.PublicCloud.ActiveDirectoryEndpoint, c.TenantID)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		secret := &federatedTokenFileSecret{path: federatedTokenFile()}
+		token, err = adal.NewServicePrincipalTokenWithSecret(*oauthConfig, c.ClientID, resource, secret)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	default:
+		return nil, microerror.Maskf(unsupportedIdentityTypeError, "identity type %#q is not supported, expected one of %v", c.Type, IdentityTypes())
+	}
+
+	return autorest.NewBearerAuthorizer(token), nil
+}
+
+// federatedTokenFileSecret authenticates with the projected service account
+// token of the webhook pod. The kubelet rotates the token, so it is read
+// again on every refresh instead of once like in
+// adal.NewServicePrincipalTokenFromFederatedToken.
+type federatedTokenFileSecret struct {
+	path string
+}
+
+func (s *federatedTokenFileSecret) SetAuthenticationValues(_ *adal.ServicePrincipalToken, values *url.Values) error {
+	jwt, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	values.Set("client_assertion", strings.TrimSpace(string(jwt)))
+	values.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+
+	return nil
+}
+
+func federatedTokenFile() string {
+	path := os.Getenv(federatedTokenFileEnv)
+	if path == "" {
+		return defaultFederatedTokenFile
+	}
+
+	return path
+}
+
+// decodeCertificate returns the certificate and RSA private key of the given
+// PEM data, or of the given PKCS#12 data when it contains no PEM blocks.
+func decodeCertificate(data []byte, password string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	var certificate *x509.Certificate
+	var privateKey *rsa.PrivateKey
+	var found bool
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		found = true
+
+		switch block.Type {
+		case "CERTIFICATE":
+			if certificate != nil {
+				continue
+			}
+			var err error
+			certificate, err = x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, nil, microerror.Maskf(invalidCertificateError, "unable to parse certificate: %s", err.Error())
+			}
+		case "RSA PRIVATE KEY":
+			var err error
+			privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, nil, microerror.Maskf(invalidCertificateError, "unable to parse private key: %s", err.Error())
+			}
+		case "PRIVATE KEY":
+			key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+			if err != nil {
+				return nil, nil, microerror.Maskf(invalidCertificateError, "unable to parse private key: %s", err.Error())
+			}
+			var ok bool
+			privateKey, ok = key.(*rsa.PrivateKey)
+			if !ok {
+				return nil, nil, microerror.Maskf(invalidCertificateError, "private key must be an RSA key, got %T", key)
+			}
+		}
+	}
+
+	if !found {
+		var err error
+		certificate, privateKey, err = adal.DecodePfxCertificateData(data, password)
+		if err != nil {
+			return nil, nil, microerror.Maskf(invalidCertificateError, "unable to decode PKCS#12 data: %s", err.Error())
+		}
+	}
+
+	if certificate == nil {
+		return nil, nil, microerror.Maskf(invalidCertificateError, "certificate must not be empty")
+	}
+	if privateKey == nil {
+		return nil, nil, microerror.Maskf(invalidCertificateError, "private key must not be empty")
+	}
+
+	return certificate, privateKey, nil
+}
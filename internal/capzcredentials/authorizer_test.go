@@ -0,0 +1,190 @@
+package capzcredentials
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
+func TestAuthorizer(t *testing.T) {
+	certificate, pkcs1Key, pkcs8Key := newCertificate(t)
+
+	testCases := []struct {
+		name         string
+		credentials  AzureCredentials
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: legacy credentials",
+			credentials: AzureCredentials{
+				TenantID:     "tenant",
+				ClientID:     "client-id",
+				ClientSecret: "secret",
+			},
+		},
+		{
+			name: "case 1: service principal",
+			credentials: AzureCredentials{
+				Type:         capz.ServicePrincipal,
+				TenantID:     "tenant",
+				ClientID:     "client-id",
+				ClientSecret: "secret",
+			},
+		},
+		{
+			name: "case 2: service principal certificate with PKCS#1 key",
+			credentials: AzureCredentials{
+				Type:              capz.ServicePrincipalCertificate,
+				TenantID:          "tenant",
+				ClientID:          "client-id",
+				ClientCertificate: append(append([]byte{}, certificate...), pkcs1Key...),
+			},
+		},
+		{
+			name: "case 3: service principal certificate with PKCS#8 key",
+			credentials: AzureCredentials{
+				Type:              capz.ServicePrincipalCertificate,
+				TenantID:          "tenant",
+				ClientID:          "client-id",
+				ClientCertificate: append(append([]byte{}, pkcs8Key...), certificate...),
+			},
+		},
+		{
+			name: "case 4: service principal certificate without key",
+			credentials: AzureCredentials{
+				Type:              capz.ServicePrincipalCertificate,
+				TenantID:          "tenant",
+				ClientID:          "client-id",
+				ClientCertificate: certificate,
+			},
+			errorMatcher: IsInvalidCertificate,
+		},
+		{
+			name: "case 5: service principal certificate that is no certificate",
+			credentials: AzureCredentials{
+				Type:              capz.ServicePrincipalCertificate,
+				TenantID:          "tenant",
+				ClientID:          "client-id",
+				ClientCertificate: []byte("secret"),
+			},
+			errorMatcher: IsInvalidCertificate,
+		},
+		{
+			name: "case 6: user assigned MSI with client ID",
+			credentials: AzureCredentials{
+				Type:     capz.UserAssignedMSI,
+				ClientID: "client-id",
+			},
+		},
+		{
+			name: "case 7: user assigned MSI with resource ID",
+			credentials: AzureCredentials{
+				Type:       capz.UserAssignedMSI,
+				ResourceID: "/subscriptions/subscription/resourceGroups/group/providers/Microsoft.ManagedIdentity/userAssignedIdentities/identity",
+			},
+		},
+		{
+			name: "case 8: workload identity",
+			credentials: AzureCredentials{
+				Type:     IdentityTypeWorkloadIdentity,
+				TenantID: "tenant",
+				ClientID: "client-id",
+			},
+		},
+		{
+			name: "case 9: unknown type",
+			credentials: AzureCredentials{
+				Type:     "Unknown",
+				TenantID: "tenant",
+				ClientID: "client-id",
+			},
+			errorMatcher: IsUnsupportedIdentityType,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			authorizer, err := tc.credentials.Authorizer()
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				if authorizer == nil {
+					t.Fatalf("expected an authorizer, got nil")
+				}
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
+
+func TestFederatedTokenFileSecretReadsRotatedToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	t.Setenv(federatedTokenFileEnv, path)
+
+	secret := &federatedTokenFileSecret{path: federatedTokenFile()}
+	for _, jwt := range []string{"first", "second"} {
+		err := os.WriteFile(path, []byte(jwt+"\n"), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		values := url.Values{}
+		err = secret.SetAuthenticationValues(nil, &values)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if values.Get("client_assertion") != jwt {
+			t.Fatalf("expected client assertion %q, got %q", jwt, values.Get("client_assertion"))
+		}
+	}
+}
+
+// newCertificate returns a self-signed PEM certificate, and its private key
+// in PKCS#1 and PKCS#8 PEM encoding.
+func newCertificate(t *testing.T) ([]byte, []byte, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "azure-admission-controller"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	pkcs1Key := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	pkcs8Key := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})
+
+	return certificate, pkcs1Key, pkcs8Key
+}
@@ -2,6 +2,7 @@ package capzcredentials
 
 import (
 	"context"
+	"time"
 
 	"github.com/giantswarm/microerror"
 	v1 "k8s.io/api/core/v1"
@@ -10,25 +11,111 @@ import (
 	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/pkg/metrics"
+)
+
+const (
+	// IdentitySecretKey is the key of the client secret, or of the
+	// certificate, in the Secret referenced by an AzureClusterIdentity.
+	IdentitySecretKey = "clientSecret"
+	// IdentityPasswordKey is the key of the optional password of the
+	// certificate in the Secret referenced by an AzureClusterIdentity.
+	IdentityPasswordKey = "password"
 )
 
+// IdentityTypeWorkloadIdentity is the AzureClusterIdentity type of a
+// federated identity, that authenticates with the projected service account
+// token of the webhook pod. Newer CAPZ versions define it, the vendored one
+// doesn't.
+const IdentityTypeWorkloadIdentity capz.IdentityType = "WorkloadIdentity"
+
+// IdentityTypes returns the AzureClusterIdentity types credentials can be
+// looked up for.
+func IdentityTypes() []capz.IdentityType {
+	return []capz.IdentityType{
+		capz.ServicePrincipal,
+		capz.ManualServicePrincipal,
+		capz.ServicePrincipalCertificate,
+		capz.UserAssignedMSI,
+		IdentityTypeWorkloadIdentity,
+	}
+}
+
+// RequiresSecret returns true for the AzureClusterIdentity types which
+// credentials are read from the Secret referenced by the identity.
+func RequiresSecret(identityType capz.IdentityType) bool {
+	switch identityType {
+	case capz.ServicePrincipal, capz.ManualServicePrincipal, capz.ServicePrincipalCertificate:
+		return true
+	}
+
+	return false
+}
+
+// IdentityKey returns the key of the AzureClusterIdentity referenced by the
+// given AzureCluster. Like in CAPZ, an empty namespace refers to the namespace
+// of the AzureCluster.
+func IdentityKey(azureCluster *capz.AzureCluster) client.ObjectKey {
+	namespace := azureCluster.Spec.IdentityRef.Namespace
+	if namespace == "" {
+		namespace = azureCluster.Namespace
+	}
+
+	return client.ObjectKey{Namespace: namespace, Name: azureCluster.Spec.IdentityRef.Name}
+}
+
+// sources are the objects the credentials of a cluster were read from. The
+// cached credentials are dropped when one of them changes, see Cache.
+type sources struct {
+	azureCluster client.ObjectKey
+	identity     client.ObjectKey
+	secrets      []client.ObjectKey
+	// legacy is true when the credentials were read from a credentiald
+	// Secret. Which one is used depends on the labels of all credentiald
+	// Secrets, not only the one read.
+	legacy bool
+}
+
 func GetAzureCredentialsFromMetadata(ctx context.Context, ctrlClient client.Client, obj metav1.ObjectMeta) (*AzureCredentials, error) {
-	azureCredentials, err := getCapzCredentials(ctx, ctrlClient, obj)
+	azureCredentials, _, err := lookup(ctx, ctrlClient, obj)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return azureCredentials, nil
+}
+
+func lookup(ctx context.Context, ctrlClient client.Client, obj metav1.ObjectMeta) (*AzureCredentials, sources, error) {
+	var s sources
+
+	start := time.Now()
+	azureCredentials, err := getCapzCredentials(ctx, ctrlClient, obj, &s)
 	if IsMissingIdentityRef(err) || errors.IsNotFound(err) {
 		// Unable to find the Identity Ref or one of the related resources.
 		// We need to fall back to the organization logic to retrieve credentials for azure API.
-		azureCredentials, err = getLegacyCredentials(ctx, ctrlClient, obj)
+		s.legacy = true
+		start = time.Now()
+		azureCredentials, err = getLegacyCredentials(ctx, ctrlClient, obj, &s)
+		metrics.ObserveCredentialsLookup(metrics.CredentialsSourceLegacy, start, err)
 		if err != nil {
-			return nil, microerror.Mask(err)
+			return nil, sources{}, microerror.Mask(err)
+		}
+	} else {
+		metrics.ObserveCredentialsLookup(metrics.CredentialsSourceCAPZ, start, err)
+		if err != nil {
+			return nil, sources{}, microerror.Mask(err)
 		}
-	} else if err != nil {
-		return nil, microerror.Mask(err)
 	}
 
-	return azureCredentials, nil
+	return azureCredentials, s, nil
 }
 
-func getCapzCredentials(ctx context.Context, ctrlClient client.Client, obj metav1.ObjectMeta) (*AzureCredentials, error) {
+func getCapzCredentials(ctx context.Context, ctrlClient client.Client, obj metav1.ObjectMeta, s *sources) (*AzureCredentials, error) {
+	// The AzureCluster is a source even when it doesn't exist yet, so that
+	// the legacy credentials are dropped once it is created.
+	s.azureCluster = client.ObjectKey{Namespace: obj.Namespace, Name: obj.Labels[capi.ClusterLabelName]}
+
 	azureCluster, err := getAzureClusterFromMetadata(ctx, ctrlClient, obj)
 	if err != nil {
 		return nil, microerror.Mask(err)
@@ -38,24 +125,56 @@ func getCapzCredentials(ctx context.Context, ctrlClient client.Client, obj metav
 		return nil, microerror.Maskf(missingIdentityRefError, "IdentiyRef was nil in AzureCluster %s/%s", azureCluster.Namespace, azureCluster.Name)
 	}
 
+	s.identity = IdentityKey(azureCluster)
 	identity := capz.AzureClusterIdentity{}
-	err = ctrlClient.Get(ctx, client.ObjectKey{Namespace: azureCluster.Spec.IdentityRef.Namespace, Name: azureCluster.Spec.IdentityRef.Name}, &identity)
+	err = ctrlClient.Get(ctx, s.identity, &identity)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
+	azureCredentials := &AzureCredentials{
+		Type:           identity.Spec.Type,
+		SubscriptionID: azureCluster.Spec.SubscriptionID,
+		TenantID:       identity.Spec.TenantID,
+		ClientID:       identity.Spec.ClientID,
+		ResourceID:     identity.Spec.ResourceID,
+	}
+
+	switch identity.Spec.Type {
+	case capz.UserAssignedMSI, IdentityTypeWorkloadIdentity:
+		// These identities have no secret, the token is requested with the
+		// identity of the webhook pod.
+		return azureCredentials, nil
+	}
+	if !RequiresSecret(identity.Spec.Type) {
+		return nil, microerror.Maskf(unsupportedIdentityTypeError, "AzureClusterIdentity %s/%s has type %#q, expected one of %v", identity.Namespace, identity.Name, identity.Spec.Type, IdentityTypes())
+	}
+
+	secretKey := client.ObjectKey{Namespace: identity.Spec.ClientSecret.Namespace, Name: identity.Spec.ClientSecret.Name}
+	s.secrets = append(s.secrets, secretKey)
 	secret := v1.Secret{}
-	err = ctrlClient.Get(ctx, client.ObjectKey{Namespace: identity.Spec.ClientSecret.Namespace, Name: identity.Spec.ClientSecret.Name}, &secret)
+	err = ctrlClient.Get(ctx, secretKey, &secret)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	return &AzureCredentials{
-		SubscriptionID: azureCluster.Spec.SubscriptionID,
-		TenantID:       identity.Spec.TenantID,
-		ClientID:       identity.Spec.ClientID,
-		ClientSecret:   string(secret.Data["clientSecret"]),
-	}, nil
+	// The AzureClusterIdentity validation only checks the Secret when the
+	// identity is created or changed, the Secret itself can still lose its key
+	// afterwards.
+	if len(secret.Data[IdentitySecretKey]) == 0 {
+		return nil, microerror.Maskf(missingValueError, "Secret %s/%s of AzureClusterIdentity %s/%s must contain the key %#q", secretKey.Namespace, secretKey.Name, identity.Namespace, identity.Name, IdentitySecretKey)
+	}
+
+	// CAPZ stores the certificate of the ServicePrincipalCertificate type in
+	// the same key as the client secret.
+	if identity.Spec.Type == capz.ServicePrincipalCertificate {
+		azureCredentials.ClientCertificate = secret.Data[IdentitySecretKey]
+		azureCredentials.ClientCertificatePassword = string(secret.Data[IdentityPasswordKey])
+	} else {
+		azureCredentials.ClientSecret = string(secret.Data[IdentitySecretKey])
+	}
+
+	return azureCredentials, nil
 }
 
 func getAzureClusterFromMetadata(ctx context.Context, c client.Client, obj metav1.ObjectMeta) (*capz.AzureCluster, error) {
@@ -11,6 +11,15 @@ func IsCredentialsNotFoundError(err error) bool {
 	return microerror.Cause(err) == credentialsNotFoundError
 }
 
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
 var invalidObjectMetaError = &microerror.Error{
 	Kind: "invalidObjectMetaError",
 }
@@ -28,6 +37,11 @@ var missingValueError = &microerror.Error{
 	Kind: "missingValueError",
 }
 
+// IsMissingValue asserts missingValueError.
+func IsMissingValue(err error) bool {
+	return microerror.Cause(err) == missingValueError
+}
+
 var tooManyCredentialsError = &microerror.Error{
 	Kind: "tooManyCredentialsError",
 }
@@ -36,3 +50,21 @@ var tooManyCredentialsError = &microerror.Error{
 func IsTooManyCredentials(err error) bool {
 	return microerror.Cause(err) == tooManyCredentialsError
 }
+
+var unsupportedIdentityTypeError = &microerror.Error{
+	Kind: "unsupportedIdentityTypeError",
+}
+
+// IsUnsupportedIdentityType asserts unsupportedIdentityTypeError.
+func IsUnsupportedIdentityType(err error) bool {
+	return microerror.Cause(err) == unsupportedIdentityTypeError
+}
+
+var invalidCertificateError = &microerror.Error{
+	Kind: "invalidCertificateError",
+}
+
+// IsInvalidCertificate asserts invalidCertificateError.
+func IsInvalidCertificate(err error) bool {
+	return microerror.Cause(err) == invalidCertificateError
+}
@@ -21,14 +21,16 @@ const (
 	tenantIDKey       = "azure.azureoperator.tenantid"
 )
 
-func getLegacyCredentials(ctx context.Context, ctrlClient client.Client, objectMeta metav1.ObjectMeta) (*AzureCredentials, error) {
+func getLegacyCredentials(ctx context.Context, ctrlClient client.Client, objectMeta metav1.ObjectMeta, s *sources) (*AzureCredentials, error) {
 	credentialSecret, err := getCredentialSecret(ctx, ctrlClient, objectMeta)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
+	secretKey := client.ObjectKey{Namespace: credentialSecret.Namespace, Name: credentialSecret.Name}
+	s.secrets = append(s.secrets, secretKey)
 	secret := &corev1.Secret{}
-	err = ctrlClient.Get(ctx, client.ObjectKey{Namespace: credentialSecret.Namespace, Name: credentialSecret.Name}, secret)
+	err = ctrlClient.Get(ctx, secretKey, secret)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
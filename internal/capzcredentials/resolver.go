@@ -0,0 +1,286 @@
+package capzcredentials
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	apiextensionslabels "github.com/giantswarm/apiextensions/v6/pkg/label"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/pkg/metrics"
+)
+
+// Informers gives access to the informers of a cache, e.g. the
+// controller-runtime cache.Cache.
+type Informers interface {
+	GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error)
+}
+
+type ResolverConfig struct {
+	Logger micrologger.Logger
+}
+
+// Resolver looks up the Azure credentials of clusters and caches them per
+// cluster, see GetAzureCredentials. Cached credentials are dropped when one
+// of the Secrets, AzureClusterIdentities or AzureClusters they were read from
+// changes, which Watch learns from informer events. It is safe for
+// concurrent use.
+type Resolver struct {
+	logger micrologger.Logger
+
+	mutex    sync.RWMutex
+	watching bool
+	// generation is increased on every informer event. Credentials looked up
+	// while an event was handled may be outdated already, so they are not
+	// cached.
+	generation uint64
+	entries    map[cacheKey]entry
+	// invalidated are called with the credentials dropped from the cache,
+	// see OnInvalidate.
+	invalidated []func(credentials AzureCredentials)
+}
+
+// cacheKey identifies the credentials of a cluster. The organization is part
+// of it, as the legacy credentials are looked up by organization.
+type cacheKey struct {
+	namespace    string
+	cluster      string
+	organization string
+}
+
+type entry struct {
+	credentials AzureCredentials
+	sources     sources
+}
+
+func NewResolver(config ResolverConfig) (*Resolver, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	r := &Resolver{
+		logger:  config.Logger,
+		entries: map[cacheKey]entry{},
+	}
+
+	return r, nil
+}
+
+// GetAzureCredentials returns the Azure credentials of the cluster of the
+// given object, like GetAzureCredentialsFromMetadata. They are cached once
+// Watch was called. A nil resolver looks them up on every call.
+func (r *Resolver) GetAzureCredentials(ctx context.Context, ctrlClient client.Client, obj metav1.ObjectMeta) (*AzureCredentials, error) {
+	if r == nil {
+		return GetAzureCredentialsFromMetadata(ctx, ctrlClient, obj)
+	}
+
+	key := cacheKey{
+		namespace:    obj.Namespace,
+		cluster:      obj.Labels[capi.ClusterLabelName],
+		organization: organizationID(obj),
+	}
+
+	r.mutex.RLock()
+	e, hit := r.entries[key]
+	watching := r.watching
+	generation := r.generation
+	r.mutex.RUnlock()
+
+	if hit {
+		metrics.CredentialsCacheLookup(true)
+		credentials := e.credentials
+		return &credentials, nil
+	}
+	if watching {
+		metrics.CredentialsCacheLookup(false)
+	}
+
+	credentials, s, err := lookup(ctx, ctrlClient, obj)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if watching {
+		r.mutex.Lock()
+		if r.generation == generation {
+			r.entries[key] = entry{credentials: *credentials, sources: s}
+		}
+		r.mutex.Unlock()
+	}
+
+	return credentials, nil
+}
+
+// OnInvalidate registers a function called with the credentials dropped from
+// the cache, so that the clients created with them can be dropped too. It is
+// called once the cache is unlocked, it may use the resolver. A nil resolver
+// never drops credentials.
+func (r *Resolver) OnInvalidate(f func(credentials AzureCredentials)) {
+	if r == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.invalidated = append(r.invalidated, f)
+}
+
+// Watch registers event handlers on the informers of Secrets,
+// AzureClusterIdentities and AzureClusters, and enables caching. Secrets are
+// watched by their metadata only, so their data is not kept in memory.
+func (r *Resolver) Watch(ctx context.Context, informers Informers) error {
+	watched := []struct {
+		obj         client.Object
+		invalidates func(obj metav1.Object, s sources) bool
+	}{
+		{
+			obj:         &capz.AzureCluster{},
+			invalidates: isAzureClusterSource,
+		},
+		{
+			obj:         &capz.AzureClusterIdentity{},
+			invalidates: isIdentitySource,
+		},
+		{
+			obj: &metav1.PartialObjectMetadata{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			},
+			invalidates: isSecretSource,
+		},
+	}
+
+	for _, w := range watched {
+		informer, err := informers.GetInformer(ctx, w.obj)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		invalidates := w.invalidates
+		informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				r.invalidate(obj, invalidates)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				if isResync(oldObj, newObj) || isStatusUpdate(oldObj, newObj) {
+					return
+				}
+				// Labels matter for the legacy Secrets, so both versions
+				// are checked.
+				r.invalidate(oldObj, invalidates)
+				r.invalidate(newObj, invalidates)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				r.invalidate(obj, invalidates)
+			},
+		})
+	}
+
+	r.mutex.Lock()
+	r.watching = true
+	r.mutex.Unlock()
+
+	return nil
+}
+
+// invalidate drops the cached credentials read from the given object.
+func (r *Resolver) invalidate(obj interface{}, invalidates func(obj metav1.Object, s sources) bool) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		r.logger.Log("level", "warning", "message", "unable to read the metadata of an informer event, dropping all cached credentials", "stack", microerror.JSON(err))
+	}
+
+	var dropped []AzureCredentials
+	r.mutex.Lock()
+	r.generation++
+	for key, e := range r.entries {
+		if accessor == nil || invalidates(accessor, e.sources) {
+			dropped = append(dropped, e.credentials)
+			delete(r.entries, key)
+		}
+	}
+	invalidated := r.invalidated
+	r.mutex.Unlock()
+
+	for _, credentials := range dropped {
+		for _, f := range invalidated {
+			f(credentials)
+		}
+	}
+}
+
+// isResync returns true for the update events sent when the informer resyncs,
+// in which the object didn't change.
+func isResync(oldObj interface{}, newObj interface{}) bool {
+	oldAccessor, err := meta.Accessor(oldObj)
+	if err != nil {
+		return false
+	}
+	newAccessor, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+
+	return oldAccessor.GetResourceVersion() == newAccessor.GetResourceVersion()
+}
+
+// isStatusUpdate returns true for the update events of objects whose spec
+// and labels didn't change, like the status updates of AzureClusters. The
+// credentials are only read from the spec. Secrets have no generation, so all
+// their changes are seen.
+func isStatusUpdate(oldObj interface{}, newObj interface{}) bool {
+	oldAccessor, err := meta.Accessor(oldObj)
+	if err != nil {
+		return false
+	}
+	newAccessor, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+
+	if oldAccessor.GetGeneration() == 0 {
+		return false
+	}
+
+	return oldAccessor.GetGeneration() == newAccessor.GetGeneration() && reflect.DeepEqual(oldAccessor.GetLabels(), newAccessor.GetLabels())
+}
+
+func isAzureClusterSource(obj metav1.Object, s sources) bool {
+	return s.azureCluster == objectKey(obj)
+}
+
+func isIdentitySource(obj metav1.Object, s sources) bool {
+	return s.identity == objectKey(obj)
+}
+
+func isSecretSource(obj metav1.Object, s sources) bool {
+	// A credentiald Secret of the organization can replace the one the
+	// legacy credentials were read from.
+	if s.legacy && obj.GetLabels()[apiextensionslabels.App] == "credentiald" {
+		return true
+	}
+
+	for _, secret := range s.secrets {
+		if secret == objectKey(obj) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func objectKey(obj metav1.Object) client.ObjectKey {
+	return client.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}
@@ -0,0 +1,483 @@
+package capzcredentials
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	apiextensionslabels "github.com/giantswarm/apiextensions/v6/pkg/label"
+	"github.com/giantswarm/micrologger"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	toolscache "k8s.io/client-go/tools/cache"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake" //nolint:staticcheck
+)
+
+// fakeInformer sends informer events to the registered event handlers.
+type fakeInformer struct {
+	handlers []toolscache.ResourceEventHandler
+}
+
+func (f *fakeInformer) AddEventHandler(handler toolscache.ResourceEventHandler) {
+	f.handlers = append(f.handlers, handler)
+}
+
+func (f *fakeInformer) AddEventHandlerWithResyncPeriod(handler toolscache.ResourceEventHandler, _ time.Duration) {
+	f.AddEventHandler(handler)
+}
+
+func (f *fakeInformer) AddIndexers(_ toolscache.Indexers) error {
+	return nil
+}
+
+func (f *fakeInformer) HasSynced() bool {
+	return true
+}
+
+func (f *fakeInformer) Add(obj metav1.Object) {
+	for _, h := range f.handlers {
+		h.OnAdd(obj)
+	}
+}
+
+func (f *fakeInformer) Update(oldObj metav1.Object, newObj metav1.Object) {
+	for _, h := range f.handlers {
+		h.OnUpdate(oldObj, newObj)
+	}
+}
+
+// fakeInformers returns a fake informer per object type.
+type fakeInformers map[string]*fakeInformer
+
+func (f fakeInformers) GetInformer(_ context.Context, obj client.Object) (cache.Informer, error) {
+	key := fmt.Sprintf("%T", obj)
+	if _, ok := f[key]; !ok {
+		f[key] = &fakeInformer{}
+	}
+
+	return f[key], nil
+}
+
+func (f fakeInformers) azureClusters() *fakeInformer {
+	return f[fmt.Sprintf("%T", &capz.AzureCluster{})]
+}
+
+func (f fakeInformers) identities() *fakeInformer {
+	return f[fmt.Sprintf("%T", &capz.AzureClusterIdentity{})]
+}
+
+func (f fakeInformers) secrets() *fakeInformer {
+	return f[fmt.Sprintf("%T", &metav1.PartialObjectMetadata{})]
+}
+
+func TestResolverCachesCAPZCredentials(t *testing.T) {
+	ctrlClient := newFakeClient(t, newAzureCluster(), newIdentity(), newSecret("identity-secret", "org-acme", map[string][]byte{"clientSecret": []byte("first")}, nil))
+	resolver, informers := newWatchingResolver(t)
+
+	assertClientSecret(t, resolver, ctrlClient, "first")
+
+	// Changes are not seen until an informer event drops the cache.
+	updateSecret(t, ctrlClient, "identity-secret", "org-acme", "clientSecret", "second")
+	assertClientSecret(t, resolver, ctrlClient, "first")
+
+	// Events of other Secrets keep the cache.
+	informers.secrets().Update(metadata("other", "org-acme", "1", nil), metadata("other", "org-acme", "2", nil))
+	assertClientSecret(t, resolver, ctrlClient, "first")
+
+	// Resyncs of the Secret keep the cache.
+	informers.secrets().Update(metadata("identity-secret", "org-acme", "1", nil), metadata("identity-secret", "org-acme", "1", nil))
+	assertClientSecret(t, resolver, ctrlClient, "first")
+
+	informers.secrets().Update(metadata("identity-secret", "org-acme", "1", nil), metadata("identity-secret", "org-acme", "2", nil))
+	assertClientSecret(t, resolver, ctrlClient, "second")
+}
+
+func TestResolverDropsCredentialsOfChangedIdentity(t *testing.T) {
+	ctx := context.Background()
+	ctrlClient := newFakeClient(t, newAzureCluster(), newIdentity(), newSecret("identity-secret", "org-acme", map[string][]byte{"clientSecret": []byte("first")}, nil))
+	resolver, informers := newWatchingResolver(t)
+
+	credentials, err := resolver.GetAzureCredentials(ctx, ctrlClient, nodePoolMeta())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.ClientID != "client-id" {
+		t.Fatalf("expected client ID %q, got %q", "client-id", credentials.ClientID)
+	}
+
+	identity := &capz.AzureClusterIdentity{}
+	err = ctrlClient.Get(ctx, client.ObjectKey{Namespace: "org-acme", Name: "identity"}, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	identity.Spec.ClientID = "rotated-client-id"
+	err = ctrlClient.Update(ctx, identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	informers.identities().Update(metadata("identity", "org-acme", "1", nil), metadata("identity", "org-acme", "2", nil))
+
+	credentials, err = resolver.GetAzureCredentials(ctx, ctrlClient, nodePoolMeta())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.ClientID != "rotated-client-id" {
+		t.Fatalf("expected client ID %q, got %q", "rotated-client-id", credentials.ClientID)
+	}
+}
+
+func TestResolverSwitchesFromLegacyToCAPZCredentials(t *testing.T) {
+	ctx := context.Background()
+	legacySecret := newSecret("credential-acme", "org-acme", map[string][]byte{
+		clientIDKey:       []byte("legacy-client-id"),
+		clientSecretKey:   []byte("legacy"),
+		subscriptionIDKey: []byte("subscription"),
+		tenantIDKey:       []byte("tenant"),
+	}, map[string]string{
+		apiextensionslabels.App:          "credentiald",
+		apiextensionslabels.Organization: "acme",
+	})
+	ctrlClient := newFakeClient(t, newIdentity(), newSecret("identity-secret", "org-acme", map[string][]byte{"clientSecret": []byte("capz")}, nil), legacySecret)
+	resolver, informers := newWatchingResolver(t)
+
+	// There is no AzureCluster yet, so the legacy credentials are used.
+	assertClientSecret(t, resolver, ctrlClient, "legacy")
+
+	// Any credentiald Secret of the organization may replace the legacy one,
+	// so their events drop the cache.
+	updateSecret(t, ctrlClient, "credential-acme", "org-acme", clientSecretKey, "legacy-rotated")
+	informers.secrets().Add(metadata("credential-other", "org-acme", "1", legacySecret.Labels))
+	assertClientSecret(t, resolver, ctrlClient, "legacy-rotated")
+
+	err := ctrlClient.Create(ctx, newAzureCluster())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertClientSecret(t, resolver, ctrlClient, "legacy-rotated")
+
+	informers.azureClusters().Add(metadata("ab123", "org-acme", "1", nil))
+	assertClientSecret(t, resolver, ctrlClient, "capz")
+}
+
+func TestResolverNotifiesDroppedCredentials(t *testing.T) {
+	ctrlClient := newFakeClient(t, newAzureCluster(), newIdentity(), newSecret("identity-secret", "org-acme", map[string][]byte{"clientSecret": []byte("first")}, nil))
+	resolver, informers := newWatchingResolver(t)
+
+	var dropped []string
+	resolver.OnInvalidate(func(credentials AzureCredentials) {
+		dropped = append(dropped, credentials.ClientSecret)
+	})
+
+	assertClientSecret(t, resolver, ctrlClient, "first")
+
+	// Status updates of the AzureCluster don't change the credentials.
+	azureCluster := metadata("ab123", "org-acme", "1", nil)
+	azureCluster.Generation = 1
+	updatedAzureCluster := metadata("ab123", "org-acme", "2", nil)
+	updatedAzureCluster.Generation = 1
+	informers.azureClusters().Update(azureCluster, updatedAzureCluster)
+	if len(dropped) != 0 {
+		t.Fatalf("expected no dropped credentials, got %v", dropped)
+	}
+
+	updateSecret(t, ctrlClient, "identity-secret", "org-acme", "clientSecret", "second")
+	informers.secrets().Update(metadata("identity-secret", "org-acme", "1", nil), metadata("identity-secret", "org-acme", "2", nil))
+	if !reflect.DeepEqual(dropped, []string{"first"}) {
+		t.Fatalf("expected dropped credentials %v, got %v", []string{"first"}, dropped)
+	}
+	assertClientSecret(t, resolver, ctrlClient, "second")
+}
+
+func TestAzureCredentialsCacheKey(t *testing.T) {
+	credentials := AzureCredentials{
+		Type:           capz.ServicePrincipal,
+		SubscriptionID: "subscription",
+		TenantID:       "tenant",
+		ClientID:       "client-id",
+		ClientSecret:   "first",
+	}
+	rotated := credentials
+	rotated.ClientSecret = "second"
+
+	if credentials.CacheKey() != credentials.CacheKey() {
+		t.Fatalf("expected the same credentials to have the same cache key")
+	}
+	if credentials.CacheKey() == rotated.CacheKey() {
+		t.Fatalf("expected rotated credentials to have another cache key")
+	}
+}
+
+func TestResolverWithoutWatch(t *testing.T) {
+	ctrlClient := newFakeClient(t, newAzureCluster(), newIdentity(), newSecret("identity-secret", "org-acme", map[string][]byte{"clientSecret": []byte("first")}, nil))
+
+	resolver, err := NewResolver(ResolverConfig{Logger: microloggerOrFail(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range []*Resolver{resolver, nil} {
+		updateSecret(t, ctrlClient, "identity-secret", "org-acme", "clientSecret", "first")
+		assertClientSecret(t, r, ctrlClient, "first")
+
+		// Nothing is cached, as there are no events to drop the cache.
+		updateSecret(t, ctrlClient, "identity-secret", "org-acme", "clientSecret", "second")
+		assertClientSecret(t, r, ctrlClient, "second")
+	}
+}
+
+func newWatchingResolver(t *testing.T) (*Resolver, fakeInformers) {
+	resolver, err := NewResolver(ResolverConfig{Logger: microloggerOrFail(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	informers := fakeInformers{}
+	err = resolver.Watch(context.Background(), informers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resolver, informers
+}
+
+func microloggerOrFail(t *testing.T) micrologger.Logger {
+	logger, err := micrologger.New(micrologger.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return logger
+}
+
+func newFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	err := corev1.AddToScheme(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = capz.AddToScheme(scheme)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func nodePoolMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      "np001",
+		Namespace: "org-acme",
+		Labels: map[string]string{
+			capi.ClusterLabelName:            "ab123",
+			apiextensionslabels.Organization: "acme",
+		},
+	}
+}
+
+func newAzureCluster() *capz.AzureCluster {
+	return &capz.AzureCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ab123",
+			Namespace: "org-acme",
+		},
+		Spec: capz.AzureClusterSpec{
+			AzureClusterClassSpec: capz.AzureClusterClassSpec{
+				SubscriptionID: "subscription",
+				IdentityRef: &corev1.ObjectReference{
+					Name:      "identity",
+					Namespace: "org-acme",
+				},
+			},
+		},
+	}
+}
+
+func newIdentity() *capz.AzureClusterIdentity {
+	return &capz.AzureClusterIdentity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "identity",
+			Namespace: "org-acme",
+		},
+		Spec: capz.AzureClusterIdentitySpec{
+			Type:     capz.ServicePrincipal,
+			ClientID: "client-id",
+			TenantID: "tenant",
+			ClientSecret: corev1.SecretReference{
+				Name:      "identity-secret",
+				Namespace: "org-acme",
+			},
+		},
+	}
+}
+
+func newSecret(name string, namespace string, data map[string][]byte, labels map[string]string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: data,
+	}
+}
+
+func metadata(name string, namespace string, resourceVersion string, labels map[string]string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: resourceVersion,
+			Labels:          labels,
+		},
+	}
+}
+
+func updateSecret(t *testing.T, ctrlClient client.Client, name string, namespace string, key string, value string) {
+	secret := &corev1.Secret{}
+	err := ctrlClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret.Data[key] = []byte(value)
+	err = ctrlClient.Update(context.Background(), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertClientSecret(t *testing.T, resolver *Resolver, ctrlClient client.Client, expected string) {
+	t.Helper()
+
+	credentials, err := resolver.GetAzureCredentials(context.Background(), ctrlClient, nodePoolMeta())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if credentials.ClientSecret != expected {
+		t.Fatalf("expected client secret %q, got %q", expected, credentials.ClientSecret)
+	}
+}
+
+func TestGetAzureCredentialsOfIdentityTypes(t *testing.T) {
+	testCases := []struct {
+		name         string
+		identityType capz.IdentityType
+		secretData   map[string][]byte
+		expected     AzureCredentials
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: service principal reads the client secret",
+			identityType: capz.ServicePrincipal,
+			secretData:   map[string][]byte{"clientSecret": []byte("secret")},
+			expected: AzureCredentials{
+				Type:           capz.ServicePrincipal,
+				SubscriptionID: "subscription",
+				TenantID:       "tenant",
+				ClientID:       "client-id",
+				ClientSecret:   "secret",
+				ResourceID:     "resource-id",
+			},
+		},
+		{
+			name:         "case 1: service principal certificate reads the certificate and password",
+			identityType: capz.ServicePrincipalCertificate,
+			secretData:   map[string][]byte{"clientSecret": []byte("certificate"), "password": []byte("password")},
+			expected: AzureCredentials{
+				Type:                      capz.ServicePrincipalCertificate,
+				SubscriptionID:            "subscription",
+				TenantID:                  "tenant",
+				ClientID:                  "client-id",
+				ClientCertificate:         []byte("certificate"),
+				ClientCertificatePassword: "password",
+				ResourceID:                "resource-id",
+			},
+		},
+		{
+			name:         "case 2: user assigned MSI needs no Secret",
+			identityType: capz.UserAssignedMSI,
+			expected: AzureCredentials{
+				Type:           capz.UserAssignedMSI,
+				SubscriptionID: "subscription",
+				TenantID:       "tenant",
+				ClientID:       "client-id",
+				ResourceID:     "resource-id",
+			},
+		},
+		{
+			name:         "case 3: workload identity needs no Secret",
+			identityType: IdentityTypeWorkloadIdentity,
+			expected: AzureCredentials{
+				Type:           IdentityTypeWorkloadIdentity,
+				SubscriptionID: "subscription",
+				TenantID:       "tenant",
+				ClientID:       "client-id",
+				ResourceID:     "resource-id",
+			},
+		},
+		{
+			name:         "case 4: unknown type",
+			identityType: "Unknown",
+			errorMatcher: IsUnsupportedIdentityType,
+		},
+		{
+			name:         "case 5: empty type",
+			identityType: "",
+			secretData:   map[string][]byte{"clientSecret": []byte("secret")},
+			errorMatcher: IsUnsupportedIdentityType,
+		},
+		{
+			name:         "case 6: Secret lost the client secret key",
+			identityType: capz.ServicePrincipal,
+			secretData:   map[string][]byte{"password": []byte("password")},
+			errorMatcher: IsMissingValue,
+		},
+		{
+			name:         "case 7: Secret was deleted",
+			identityType: capz.ServicePrincipal,
+			errorMatcher: apierrors.IsNotFound,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			identity := newIdentity()
+			identity.Spec.Type = tc.identityType
+			identity.Spec.ResourceID = "resource-id"
+			objects := []client.Object{newAzureCluster(), identity}
+			if tc.secretData != nil {
+				objects = append(objects, newSecret("identity-secret", "org-acme", tc.secretData, nil))
+			}
+
+			credentials, err := GetAzureCredentialsFromMetadata(context.Background(), newFakeClient(t, objects...), nodePoolMeta())
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			if tc.errorMatcher == nil && !reflect.DeepEqual(*credentials, tc.expected) {
+				t.Fatalf("expected credentials %#v, got %#v", tc.expected, *credentials)
+			}
+		})
+	}
+}
@@ -1,8 +1,50 @@
 package capzcredentials
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+)
+
 type AzureCredentials struct {
+	// Type is the type of the AzureClusterIdentity the credentials were read
+	// from. It is empty for the legacy credentials, which are client secrets
+	// like the ServicePrincipal type.
+	Type capz.IdentityType
+
 	SubscriptionID string
 	TenantID       string
 	ClientID       string
 	ClientSecret   string
+
+	// ClientCertificate is the PEM or PKCS#12 encoded certificate and private
+	// key of the ServicePrincipalCertificate type, and
+	// ClientCertificatePassword the password of the PKCS#12 data.
+	ClientCertificate         []byte
+	ClientCertificatePassword string
+
+	// ResourceID is the resource ID of the managed identity of the
+	// UserAssignedMSI type. It is only used when there is no ClientID.
+	ResourceID string
+}
+
+// CacheKey identifies the credentials, so that the clients created with them
+// can be cached and shared by all clusters using the same credentials. It is a
+// hash, the secrets can't be read from it.
+func (c AzureCredentials) CacheKey() string {
+	fields := []string{
+		string(c.Type),
+		c.SubscriptionID,
+		c.TenantID,
+		c.ClientID,
+		c.ClientSecret,
+		string(c.ClientCertificate),
+		c.ClientCertificatePassword,
+		c.ResourceID,
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return hex.EncodeToString(sum[:])
 }
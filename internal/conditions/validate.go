@@ -68,3 +68,49 @@ func validateRemovingConditionIsNotAllowed(oldCondition, newCondition *capi.Cond
 
 	return nil
 }
+
+func validateNewStatusMustBeKnownValue(_, newCondition *capi.Condition) error {
+	if newCondition == nil {
+		// Condition is not set at all, so nothing to check.
+		return nil
+	}
+
+	// Rule: We only allow True, False and Unknown as condition status values.
+	// Why: Unlike Creating and Upgrading, Ready-like conditions are set to
+	//      Unknown by Cluster API controllers while they are reconciling,
+	//      but other values can only be set by mistake.
+	conditionStatusNotAllowed :=
+		newCondition.Status != corev1.ConditionTrue &&
+			newCondition.Status != corev1.ConditionFalse &&
+			newCondition.Status != corev1.ConditionUnknown
+	if conditionStatusNotAllowed {
+		errorMessageFormat := "Allowed values for %s condition status are True, False and Unknown, got %s."
+		return microerror.Maskf(errors.InvalidConditionStatusError, errorMessageFormat, newCondition.Type, newCondition.Status)
+	}
+
+	return nil
+}
+
+func validateNewStatusFalseRequiresReason(_, newCondition *capi.Condition) error {
+	// Rule: Setting False condition status requires a reason.
+	// Why: Cluster API expects a reason for every False condition, and
+	//      without it we can't tell why the object is not ready.
+	if newCondition != nil && newCondition.Status == corev1.ConditionFalse && newCondition.Reason == "" {
+		errorMessageFormat := "Setting False status to %s condition without a reason is not allowed."
+		return microerror.Maskf(errors.InvalidConditionModificationError, errorMessageFormat, newCondition.Type)
+	}
+
+	return nil
+}
+
+// conditionChanged returns whether the status, reason or message of the
+// condition changed.
+func conditionChanged(oldCondition, newCondition *capi.Condition) bool {
+	if oldCondition == nil || newCondition == nil {
+		return oldCondition != newCondition
+	}
+
+	return oldCondition.Status != newCondition.Status ||
+		oldCondition.Reason != newCondition.Reason ||
+		oldCondition.Message != newCondition.Message
+}
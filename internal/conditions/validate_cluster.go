@@ -1,16 +1,21 @@
 package conditions
 
 import (
+	"context"
+
+	"github.com/blang/semver"
 	aeconditions "github.com/giantswarm/apiextensions/v6/pkg/conditions"
 	"github.com/giantswarm/microerror"
 	corev1 "k8s.io/api/core/v1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/release"
 )
 
-func ValidateClusterConditions(oldClusterCR *capi.Cluster, newClusterCR *capi.Cluster) error {
+func ValidateClusterConditions(ctx context.Context, ctrlReader client.Reader, oldClusterCR *capi.Cluster, newClusterCR *capi.Cluster) error {
 	var err error
 
 	err = ValidateCreatingCondition(oldClusterCR, newClusterCR)
@@ -18,7 +23,12 @@ func ValidateClusterConditions(oldClusterCR *capi.Cluster, newClusterCR *capi.Cl
 		return microerror.Mask(err)
 	}
 
-	err = ValidateUpgradingCondition(oldClusterCR, newClusterCR)
+	err = ValidateUpgradingCondition(ctx, ctrlReader, oldClusterCR, newClusterCR)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = ValidateReadyCondition(oldClusterCR, newClusterCR)
 	if err != nil {
 		return microerror.Mask(err)
 	}
@@ -58,7 +68,7 @@ func ValidateCreatingCondition(oldClusterCR *capi.Cluster, newClusterCR *capi.Cl
 	return nil
 }
 
-func ValidateUpgradingCondition(oldClusterCR *capi.Cluster, newClusterCR *capi.Cluster) error {
+func ValidateUpgradingCondition(ctx context.Context, ctrlReader client.Reader, oldClusterCR *capi.Cluster, newClusterCR *capi.Cluster) error {
 	var err error
 	oldUpgradingCondition := capiconditions.Get(oldClusterCR, aeconditions.UpgradingCondition)
 	newUpgradingCondition := capiconditions.Get(newClusterCR, aeconditions.UpgradingCondition)
@@ -75,5 +85,83 @@ func ValidateUpgradingCondition(oldClusterCR *capi.Cluster, newClusterCR *capi.C
 		return microerror.Mask(err)
 	}
 
+	// The reason and message are only checked when they are changed, so that
+	// clusters with conditions set before these rules existed can still be
+	// updated otherwise.
+	if !conditionChanged(oldUpgradingCondition, newUpgradingCondition) {
+		return nil
+	}
+
+	err = validateUpgradingConditionReason(newUpgradingCondition)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = validateUpgradingConditionMessage(ctx, ctrlReader, newUpgradingCondition)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+func validateUpgradingConditionReason(upgradingCondition *capi.Condition) error {
+	// Rule: Upgrading condition with False status must have either the
+	//       UpgradeCompleted or the UpgradeNotStarted reason.
+	// Why: The reason tells if the cluster has ever been upgraded, which is
+	//      used to decide if an upgrade is completed.
+	if upgradingCondition == nil || upgradingCondition.Status != corev1.ConditionFalse {
+		return nil
+	}
+
+	allowedReasons := []string{aeconditions.UpgradeCompletedReason, aeconditions.UpgradeNotStartedReason}
+	for _, reason := range allowedReasons {
+		if upgradingCondition.Reason == reason {
+			return nil
+		}
+	}
+
+	errorMessageFormat := "Allowed reasons for %s condition with False status are %v, got %#q."
+	return microerror.Maskf(errors.InvalidConditionModificationError, errorMessageFormat, aeconditions.UpgradingCondition, allowedReasons, upgradingCondition.Reason)
+}
+
+func validateUpgradingConditionMessage(ctx context.Context, ctrlReader client.Reader, upgradingCondition *capi.Condition) error {
+	if upgradingCondition == nil || upgradingCondition.Message == "" {
+		return nil
+	}
+
+	// Rule: Upgrading condition message must be a serialized
+	//       UpgradingConditionMessage.
+	// Why: The release version in the message tells which release is being
+	//      rolled out, and it can't be read from a message in another format.
+	message, err := aeconditions.DeserializeUpgradingConditionMessage(upgradingCondition.Message)
+	if err != nil {
+		errorMessageFormat := "%s condition message must be a JSON object like %#q, got %#q."
+		example := `{"message":"Upgrade has been started","release_version":"14.1.0"}`
+		return microerror.Maskf(errors.InvalidUpgradingConditionMessageFormatError, errorMessageFormat, aeconditions.UpgradingCondition, example, upgradingCondition.Message)
+	}
+
+	if message.ReleaseVersion == "" {
+		return nil
+	}
+
+	// Rule: Release version in the Upgrading condition message must be an
+	//       existing release.
+	// Why: The release being rolled out must exist, otherwise the message
+	//      has been set by mistake.
+	_, err = semver.ParseTolerant(message.ReleaseVersion)
+	if err != nil {
+		errorMessageFormat := "Release version %#q in %s condition message is not a valid release version."
+		return microerror.Maskf(errors.InvalidReleaseVersionInUpgradingConditionMessageError, errorMessageFormat, message.ReleaseVersion, aeconditions.UpgradingCondition)
+	}
+
+	_, err = release.FindRelease(ctx, ctrlReader, message.ReleaseVersion)
+	if release.IsReleaseNotFoundError(err) {
+		errorMessageFormat := "Release version %#q in %s condition message is not valid, the release was not found in this installation."
+		return microerror.Maskf(errors.InvalidReleaseVersionInUpgradingConditionMessageError, errorMessageFormat, message.ReleaseVersion, aeconditions.UpgradingCondition)
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
 	return nil
 }
@@ -0,0 +1,26 @@
+package conditions
+
+import (
+	aeconditions "github.com/giantswarm/apiextensions/v6/pkg/conditions"
+	"github.com/giantswarm/microerror"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+)
+
+// ValidateClusterDeletion checks that the cluster can be deleted.
+func ValidateClusterDeletion(clusterCR *capi.Cluster) error {
+	// Rule: Cluster cannot be deleted while it is being created or upgraded.
+	// Why: azure-operator is creating or updating Azure resources at that
+	//      time, and deleting the cluster in the middle of it can leave
+	//      resources behind.
+	for _, conditionType := range []capi.ConditionType{aeconditions.CreatingCondition, aeconditions.UpgradingCondition} {
+		if capiconditions.IsTrue(clusterCR, conditionType) {
+			errorMessageFormat := "Deleting cluster %#q is not allowed while its %s condition is True."
+			return microerror.Maskf(errors.InvalidOperationError, errorMessageFormat, clusterCR.Name, conditionType)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package conditions
+
+import (
+	"github.com/giantswarm/microerror"
+	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+)
+
+// ValidateMachinePoolConditions checks the changes of the conditions Cluster
+// API sets on a MachinePool.
+func ValidateMachinePoolConditions(oldMachinePoolCR *capiexp.MachinePool, newMachinePoolCR *capiexp.MachinePool) error {
+	err := validateReadyConditions(oldMachinePoolCR, newMachinePoolCR,
+		capi.ReadyCondition,
+		capi.BootstrapReadyCondition,
+		capi.InfrastructureReadyCondition,
+		capiexp.ReplicasReadyCondition,
+	)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// ValidateAzureMachinePoolConditions checks the changes of the conditions of
+// an AzureMachinePool.
+func ValidateAzureMachinePoolConditions(oldAzureMachinePoolCR *capzexp.AzureMachinePool, newAzureMachinePoolCR *capzexp.AzureMachinePool) error {
+	err := ValidateReadyCondition(oldAzureMachinePoolCR, newAzureMachinePoolCR)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
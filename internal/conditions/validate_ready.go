@@ -0,0 +1,43 @@
+package conditions
+
+import (
+	"github.com/giantswarm/microerror"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// readyConditionValidations are the validations of Ready and the other
+// conditions Cluster API controllers set while reconciling an object.
+var readyConditionValidations = []conditionChangeValidation{
+	validateNewStatusMustBeKnownValue,
+	validateRemovingConditionIsNotAllowed,
+	validateNewStatusFalseRequiresReason,
+}
+
+// ValidateReadyCondition checks the changes of the Ready condition of the
+// object.
+func ValidateReadyCondition(oldObject capiconditions.Getter, newObject capiconditions.Getter) error {
+	return validateReadyConditions(oldObject, newObject, capi.ReadyCondition)
+}
+
+func validateReadyConditions(oldObject capiconditions.Getter, newObject capiconditions.Getter, conditionTypes ...capi.ConditionType) error {
+	for _, conditionType := range conditionTypes {
+		oldCondition := capiconditions.Get(oldObject, conditionType)
+		newCondition := capiconditions.Get(newObject, conditionType)
+
+		// Conditions are only checked when they are changed, so that objects
+		// with conditions set before these rules existed can still be
+		// updated otherwise.
+		if !conditionChanged(oldCondition, newCondition) {
+			continue
+		}
+
+		// See functions for details about validations.
+		err := validateAll(oldCondition, newCondition, readyConditionValidations)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package conditions
+
+import (
+	"github.com/blang/semver"
+	aeconditions "github.com/giantswarm/apiextensions/v6/pkg/conditions"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+)
+
+// ValidateUpgradeCanBeInitiated checks that the release of the cluster can be
+// changed now.
+func ValidateUpgradeCanBeInitiated(clusterCR *capi.Cluster) error {
+	// Rule: Upgrade cannot be initiated while the cluster is being created
+	//       or upgraded.
+	// Why: azure-operator has to finish the rollout of the current release
+	//      before it can start rolling out another one.
+	if capiconditions.IsTrue(clusterCR, aeconditions.CreatingCondition) {
+		return microerror.Maskf(errors.InvalidOperationError, "upgrade cannot be initiated now, Cluster condition %s is set to True, cluster is currently being created", aeconditions.CreatingCondition)
+	} else if capiconditions.IsTrue(clusterCR, aeconditions.UpgradingCondition) {
+		return microerror.Maskf(errors.InvalidOperationError, "upgrade cannot be initiated now, Cluster condition %s is set to True, cluster is already being upgraded", aeconditions.UpgradingCondition)
+	}
+
+	return nil
+}
+
+// ValidateUpgradeCanBeScheduled checks that an upgrade of the cluster to the
+// target release can be scheduled now.
+func ValidateUpgradeCanBeScheduled(clusterCR *capi.Cluster, targetVersion semver.Version) error {
+	// Rule: Upgrade cannot be scheduled while the cluster is being created.
+	// Why: The cluster is not running its release yet, so it is not known
+	//      whether the target release is a valid upgrade.
+	if capiconditions.IsTrue(clusterCR, aeconditions.CreatingCondition) {
+		return microerror.Maskf(errors.InvalidOperationError, "upgrade cannot be scheduled now, Cluster condition %s is set to True, cluster is currently being created", aeconditions.CreatingCondition)
+	}
+
+	// Rule: The target release must be newer than the release being rolled
+	//       out.
+	// Why: The cluster would otherwise be downgraded, or upgraded to the
+	//      release it is already being upgraded to, when the scheduled time
+	//      comes.
+	upgradingVersion, ok := UpgradingReleaseVersion(clusterCR)
+	if ok && targetVersion.LTE(upgradingVersion) {
+		return microerror.Maskf(errors.InvalidOperationError, "upgrade to release %s cannot be scheduled, Cluster condition %s is set to True, cluster is already being upgraded to release %s", targetVersion, aeconditions.UpgradingCondition, upgradingVersion)
+	}
+
+	return nil
+}
+
+// UpgradingReleaseVersion returns the release the cluster is being upgraded to,
+// as set in the message of its Upgrading condition. It returns false when the
+// cluster is not being upgraded, or the condition message does not contain a
+// valid release version.
+func UpgradingReleaseVersion(clusterCR *capi.Cluster) (semver.Version, bool) {
+	upgradingCondition := capiconditions.Get(clusterCR, aeconditions.UpgradingCondition)
+	if upgradingCondition == nil || upgradingCondition.Status != corev1.ConditionTrue {
+		return semver.Version{}, false
+	}
+
+	message, err := aeconditions.DeserializeUpgradingConditionMessage(upgradingCondition.Message)
+	if err != nil {
+		return semver.Version{}, false
+	}
+
+	version, err := semver.ParseTolerant(message.ReleaseVersion)
+	if err != nil {
+		return semver.Version{}, false
+	}
+
+	return version, true
+}
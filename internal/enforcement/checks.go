@@ -0,0 +1,105 @@
+package enforcement
+
+// Names of the checks which enforcement mode can be configured. A check name
+// is made of the package implementing the check and a short name of the rule.
+const (
+	AzureClusterControlPlaneEndpoint = "azurecluster.controlPlaneEndpoint"
+	AzureClusterLocation             = "azurecluster.location"
+
+	AzureClusterIdentityAllowedNamespaces = "azureclusteridentity.allowedNamespaces"
+	AzureClusterIdentityIDs               = "azureclusteridentity.ids"
+	AzureClusterIdentityInUse             = "azureclusteridentity.inUse"
+	AzureClusterIdentitySecret            = "azureclusteridentity.secret"
+
+	AzureMachineFailureDomain = "azuremachine.failureDomain"
+	AzureMachineSSHKey        = "azuremachine.sshKey"
+
+	AzureMachinePoolAcceleratedNetworking = "azuremachinepool.acceleratedNetworking"
+	AzureMachinePoolAzureQuota            = "azuremachinepool.azureQuota"
+	AzureMachinePoolConditions            = "azuremachinepool.conditions"
+	AzureMachinePoolDataDisks             = "azuremachinepool.datadisks"
+	AzureMachinePoolEphemeralOSDisk       = "azuremachinepool.ephemeralOSDisk"
+	AzureMachinePoolGPU                   = "azuremachinepool.gpu"
+	AzureMachinePoolInstanceType          = "azuremachinepool.instanceType"
+	AzureMachinePoolLocation              = "azuremachinepool.location"
+	AzureMachinePoolQuota                 = "azuremachinepool.quota"
+	AzureMachinePoolSpotVMOptions         = "azuremachinepool.spotVMOptions"
+	AzureMachinePoolSSHKey                = "azuremachinepool.sshKey"
+	AzureMachinePoolStorageAccountType    = "azuremachinepool.storageAccountType"
+
+	ClusterChangeFreeze         = "cluster.changeFreeze"
+	ClusterClusterNetwork       = "cluster.clusterNetwork"
+	ClusterConditions           = "cluster.conditions"
+	ClusterControlPlaneEndpoint = "cluster.controlPlaneEndpoint"
+	ClusterMaintenanceWindow    = "cluster.maintenanceWindow"
+	ClusterUpgradeRelease       = "cluster.upgradeRelease"
+	ClusterUpgradeTime          = "cluster.upgradeTime"
+
+	MachinePoolAzureQuota     = "machinepool.azureQuota"
+	MachinePoolConditions     = "machinepool.conditions"
+	MachinePoolFailureDomains = "machinepool.failureDomains"
+	MachinePoolGPU            = "machinepool.gpu"
+	MachinePoolQuota          = "machinepool.quota"
+
+	ReleaseVersionAlpha      = "releaseversion.alpha"
+	ReleaseVersionDeprecated = "releaseversion.deprecated"
+	ReleaseVersionDowngrade  = "releaseversion.downgrade"
+	ReleaseVersionMinimum    = "releaseversion.minimum"
+	ReleaseVersionPatch      = "releaseversion.patch"
+	ReleaseVersionSkip       = "releaseversion.skip"
+)
+
+// Checks returns the names of all checks which enforcement mode can be
+// configured.
+func Checks() []string {
+	return []string{
+		AzureClusterControlPlaneEndpoint,
+		AzureClusterLocation,
+		AzureClusterIdentityAllowedNamespaces,
+		AzureClusterIdentityIDs,
+		AzureClusterIdentityInUse,
+		AzureClusterIdentitySecret,
+		AzureMachineFailureDomain,
+		AzureMachineSSHKey,
+		AzureMachinePoolAcceleratedNetworking,
+		AzureMachinePoolAzureQuota,
+		AzureMachinePoolConditions,
+		AzureMachinePoolDataDisks,
+		AzureMachinePoolEphemeralOSDisk,
+		AzureMachinePoolGPU,
+		AzureMachinePoolInstanceType,
+		AzureMachinePoolLocation,
+		AzureMachinePoolQuota,
+		AzureMachinePoolSpotVMOptions,
+		AzureMachinePoolSSHKey,
+		AzureMachinePoolStorageAccountType,
+		ClusterChangeFreeze,
+		ClusterClusterNetwork,
+		ClusterConditions,
+		ClusterControlPlaneEndpoint,
+		ClusterMaintenanceWindow,
+		ClusterUpgradeRelease,
+		ClusterUpgradeTime,
+		MachinePoolAzureQuota,
+		MachinePoolConditions,
+		MachinePoolFailureDomains,
+		MachinePoolGPU,
+		MachinePoolQuota,
+		ReleaseVersionAlpha,
+		ReleaseVersionDeprecated,
+		ReleaseVersionDowngrade,
+		ReleaseVersionMinimum,
+		ReleaseVersionPatch,
+		ReleaseVersionSkip,
+	}
+}
+
+func isKnownCheck(check string) bool {
+	for _, c := range Checks() {
+		if c == check {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package enforcement
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/giantswarm/micrologger"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/metrics"
+)
+
+type contextKey struct{}
+
+// request keeps the state of a single admission request.
+type request struct {
+	registry *Registry
+	logger   micrologger.Logger
+
+	mutex    sync.Mutex
+	warnings []string
+}
+
+// NewContext returns a context for handling an admission request with the
+// modes of the given registry. Checks applied with Apply using this context
+// collect their warnings in it, see Warnings.
+func NewContext(ctx context.Context, registry *Registry, logger micrologger.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, &request{
+		registry: registry,
+		logger:   logger,
+	})
+}
+
+// Apply decides what to do with the result of the given check, depending on
+// its enforcement mode:
+//
+//   - enforce: err is returned, so the request is denied.
+//   - warn: err is turned into an admission warning and nil is returned.
+//   - audit: err is only logged and nil is returned.
+//
+// Every violation is counted in the check violations metric. Checks applied
+// with a context that was not created by NewContext are always enforced.
+// Errors that are not violations (see errors.IsValidation), like failing
+// lookups of the data the check needs, are always returned as they are.
+func Apply(ctx context.Context, check string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if !errors.IsValidation(err) {
+		return err
+	}
+
+	r, ok := ctx.Value(contextKey{}).(*request)
+	if !ok {
+		return err
+	}
+
+	mode := r.registry.Mode(check)
+	metrics.CheckViolation(check, string(mode))
+
+	switch mode {
+	case ModeWarn:
+		r.mutex.Lock()
+		r.warnings = append(r.warnings, fmt.Sprintf("%s (check %s is in %s mode)", err.Error(), check, mode))
+		r.mutex.Unlock()
+
+		return nil
+	case ModeAudit:
+		r.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("check %s failed in %s mode, request allowed: %s", check, mode, err.Error()))
+
+		return nil
+	}
+
+	return err
+}
+
+// Warnings returns the warnings collected by Apply for checks in warn mode.
+func Warnings(ctx context.Context) []string {
+	r, ok := ctx.Value(contextKey{}).(*request)
+	if !ok {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]string(nil), r.warnings...)
+}
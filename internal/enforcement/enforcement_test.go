@@ -0,0 +1,178 @@
+package enforcement
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+
+	internalerrors "github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/filewatch"
+)
+
+var testCheckError = &microerror.Error{
+	Kind: "testCheckError",
+}
+
+var testLookupError = &microerror.Error{
+	Kind: "testLookupError",
+}
+
+func init() {
+	internalerrors.RegisterValidationErrors(testCheckError)
+}
+
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		name             string
+		config           string
+		check            string
+		err              error
+		expectedError    bool
+		expectedWarnings int
+	}{
+		{
+			name:          "case 0: passing check",
+			config:        "checks: {}",
+			check:         AzureMachinePoolDataDisks,
+			err:           nil,
+			expectedError: false,
+		},
+		{
+			name:          "case 1: checks are enforced by default",
+			config:        "checks: {}",
+			check:         AzureMachinePoolDataDisks,
+			err:           microerror.Mask(testCheckError),
+			expectedError: true,
+		},
+		{
+			name:             "case 2: check in warn mode",
+			config:           "checks:\n  azuremachinepool.datadisks: warn\n",
+			check:            AzureMachinePoolDataDisks,
+			err:              microerror.Mask(testCheckError),
+			expectedError:    false,
+			expectedWarnings: 1,
+		},
+		{
+			name:             "case 3: check in audit mode",
+			config:           "checks:\n  azuremachinepool.datadisks: audit\n",
+			check:            AzureMachinePoolDataDisks,
+			err:              microerror.Mask(testCheckError),
+			expectedError:    false,
+			expectedWarnings: 0,
+		},
+		{
+			name:          "case 4: other checks are still enforced",
+			config:        "checks:\n  azuremachinepool.datadisks: warn\n",
+			check:         ReleaseVersionSkip,
+			err:           microerror.Mask(testCheckError),
+			expectedError: true,
+		},
+		{
+			name:          "case 5: errors other than violations are not relaxed",
+			config:        "checks:\n  azuremachinepool.datadisks: warn\n",
+			check:         AzureMachinePoolDataDisks,
+			err:           microerror.Mask(testLookupError),
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := newTestRegistry(t, tc.config)
+			logger, _ := micrologger.New(micrologger.Config{})
+			ctx := NewContext(context.Background(), registry, logger)
+
+			err := Apply(ctx, tc.check, tc.err)
+			if tc.expectedError && !errors.Is(err, microerror.Cause(tc.err)) {
+				t.Fatalf("expected %#v, got %#v", microerror.Cause(tc.err), err)
+			}
+			if !tc.expectedError && err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+
+			warnings := Warnings(ctx)
+			if len(warnings) != tc.expectedWarnings {
+				t.Fatalf("expected %d warnings, got %d: %v", tc.expectedWarnings, len(warnings), warnings)
+			}
+		})
+	}
+}
+
+func TestApplyWithoutContext(t *testing.T) {
+	err := Apply(context.Background(), AzureMachinePoolDataDisks, microerror.Mask(testCheckError))
+	if !errors.Is(err, testCheckError) {
+		t.Fatalf("expected %#v, got %#v", testCheckError, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       string
+		check        string
+		expectedMode Mode
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: empty config",
+			config:       "",
+			check:        ClusterConditions,
+			expectedMode: ModeEnforce,
+		},
+		{
+			name:         "case 1: configured mode",
+			config:       "checks:\n  cluster.controlPlaneEndpoint: audit\n",
+			check:        ClusterControlPlaneEndpoint,
+			expectedMode: ModeAudit,
+		},
+		{
+			name:         "case 2: unknown check",
+			config:       "checks:\n  cluster.unknown: warn\n",
+			errorMatcher: IsUnknownCheck,
+		},
+		{
+			name:         "case 3: unknown mode",
+			config:       "checks:\n  cluster.controlPlaneEndpoint: ignore\n",
+			errorMatcher: IsUnknownMode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modes, err := parse([]byte(tc.config))
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			registry := &Registry{file: filewatch.Static(modes)}
+			mode := registry.Mode(tc.check)
+			if mode != tc.expectedMode {
+				t.Fatalf("expected mode %q, got %q", tc.expectedMode, mode)
+			}
+		})
+	}
+}
+
+func newTestRegistry(t *testing.T, config string) *Registry {
+	modes, err := parse([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Registry{file: filewatch.Static(modes)}
+}
@@ -0,0 +1,32 @@
+package enforcement
+
+import (
+	"github.com/giantswarm/microerror"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var unknownCheckError = &microerror.Error{
+	Kind: "unknownCheckError",
+}
+
+// IsUnknownCheck asserts unknownCheckError.
+func IsUnknownCheck(err error) bool {
+	return microerror.Cause(err) == unknownCheckError
+}
+
+var unknownModeError = &microerror.Error{
+	Kind: "unknownModeError",
+}
+
+// IsUnknownMode asserts unknownModeError.
+func IsUnknownMode(err error) bool {
+	return microerror.Cause(err) == unknownModeError
+}
@@ -0,0 +1,124 @@
+package enforcement
+
+import (
+	"context"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/azure-admission-controller/internal/filewatch"
+)
+
+// Mode defines what happens when a check fails.
+type Mode string
+
+const (
+	// ModeEnforce denies the request. This is the default mode of all checks.
+	ModeEnforce Mode = "enforce"
+	// ModeWarn allows the request and returns an admission warning.
+	ModeWarn Mode = "warn"
+	// ModeAudit allows the request, and only logs and counts the violation.
+	ModeAudit Mode = "audit"
+)
+
+// FileConfig is the content of the enforcement config file.
+//
+// Example:
+//
+//	checks:
+//	  azuremachinepool.datadisks: warn
+//	  releaseversion.skip: audit
+type FileConfig struct {
+	Checks map[string]Mode `json:"checks"`
+}
+
+type RegistryConfig struct {
+	Logger micrologger.Logger
+
+	// File is the path of the enforcement config file. It is optional, when
+	// empty all checks are enforced.
+	File string
+}
+
+// Registry holds the enforcement mode of every check. Modes are read from the
+// config file, which is reloaded by Watch, so that they can be changed
+// without restarting the webhook.
+type Registry struct {
+	file *filewatch.File[map[string]Mode]
+}
+
+func NewRegistry(config RegistryConfig) (*Registry, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	file, err := filewatch.New(filewatch.Config[map[string]Mode]{
+		Logger:  config.Logger,
+		File:    config.File,
+		Name:    "enforcement modes",
+		Default: map[string]Mode{},
+		Parse:   parse,
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r := &Registry{
+		file: file,
+	}
+
+	return r, nil
+}
+
+// Mode returns the enforcement mode of the given check.
+func (r *Registry) Mode(check string) Mode {
+	if r == nil {
+		return ModeEnforce
+	}
+
+	mode, ok := r.file.Value()[check]
+	if !ok {
+		return ModeEnforce
+	}
+
+	return mode
+}
+
+// Reload reads the config file again. An invalid config file is rejected as a
+// whole, and the previously loaded modes are kept.
+func (r *Registry) Reload() error {
+	return microerror.Mask(r.file.Reload())
+}
+
+// Watch reloads the config file at the given interval until the context is
+// done. Files mounted from a ConfigMap are updated by the kubelet, so this
+// picks up changes without restarting the webhook.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	r.file.Watch(ctx, interval)
+}
+
+func parse(content []byte) (map[string]Mode, error) {
+	var fileConfig FileConfig
+	err := yaml.Unmarshal(content, &fileConfig)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	modes := map[string]Mode{}
+	for check, mode := range fileConfig.Checks {
+		if !isKnownCheck(check) {
+			return nil, microerror.Maskf(unknownCheckError, "check %#q is not known, expected one of %v", check, Checks())
+		}
+
+		switch mode {
+		case ModeEnforce, ModeWarn, ModeAudit:
+			modes[check] = mode
+		default:
+			return nil, microerror.Maskf(unknownModeError, "mode %#q of check %#q is not known, expected one of %#q, %#q or %#q", mode, check, ModeEnforce, ModeWarn, ModeAudit)
+		}
+	}
+
+	return modes, nil
+}
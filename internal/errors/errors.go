@@ -157,3 +157,14 @@ var WrongTypeError = &microerror.Error{
 func IsWrongTypeError(err error) bool {
 	return microerror.Cause(err) == WrongTypeError
 }
+
+func init() {
+	RegisterValidationErrors(
+		InvalidUpgradingConditionMessageFormatError,
+		InvalidReleaseVersionInUpgradingConditionMessageError,
+		InvalidConditionStatusError,
+		InvalidConditionModificationError,
+		InvalidOperationError,
+		UnknownReleaseError,
+	)
+}
@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"errors"
+
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// validationKinds holds the microerror kinds registered with
+// RegisterValidationErrors. It is only written by init functions, so it is
+// safe to read it concurrently.
+var validationKinds = map[*microerror.Error]bool{}
+
+// RegisterValidationErrors marks the given microerror kinds as describing an
+// invalid object, i.e. a request the user has to fix. It is meant to be called
+// from the init function of the package declaring them. Errors of any other
+// kind are failures to handle the request, like failing API calls, broken
+// configuration or lookups of missing data.
+func RegisterValidationErrors(kinds ...*microerror.Error) {
+	for _, kind := range kinds {
+		validationKinds[kind] = true
+	}
+}
+
+// IsValidation asserts errors describing an invalid object: errors of the kinds
+// registered with RegisterValidationErrors, and Kubernetes Invalid errors like
+// the ones returned by the CAPI webhooks or by ValidationErrors.
+func IsValidation(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if apierrors.IsInvalid(err) {
+		return true
+	}
+
+	var kind *microerror.Error
+	if errors.As(err, &kind) {
+		return validationKinds[kind]
+	}
+
+	return false
+}
@@ -0,0 +1,151 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidationErrors collects the errors returned by independent validation
+// checks, so that all violations can be reported to the user at once instead
+// of only the first one.
+//
+// It implements the error interface itself. errors.Is and errors.As are
+// checked against every collected error, in the order in which they were
+// added, so microerror.Cause and the Is* matchers see the first microerror.
+type ValidationErrors struct {
+	errs   []error
+	causes []metav1.StatusCause
+
+	// requestErr is the first added error that is not a validation error.
+	requestErr error
+}
+
+// Add records err as a violation of the field at the given path. The path may
+// be nil when the error is not related to a specific field. Kubernetes Invalid
+// errors, like the ones returned by the CAPI webhooks, are expanded into their
+// causes. Nil errors are ignored.
+//
+// Errors that do not describe an invalid object (see IsValidation), like
+// failing API calls or lookups, are not violations. The first of them is
+// returned by Err instead, so that the request fails with it.
+func (v *ValidationErrors) Add(path *field.Path, err error) {
+	if err == nil {
+		return
+	}
+
+	if !IsValidation(err) {
+		if v.requestErr == nil {
+			v.requestErr = err
+		}
+		return
+	}
+
+	v.errs = append(v.errs, err)
+
+	if status := apierrors.APIStatus(nil); errors.As(err, &status) {
+		errStatus := status.Status()
+		if errStatus.Reason == metav1.StatusReasonInvalid && errStatus.Details != nil && len(errStatus.Details.Causes) > 0 {
+			v.causes = append(v.causes, errStatus.Details.Causes...)
+			return
+		}
+	}
+
+	cause := metav1.StatusCause{
+		Type:    metav1.CauseTypeFieldValueInvalid,
+		Message: err.Error(),
+	}
+	if path != nil {
+		cause.Field = path.String()
+	}
+
+	v.causes = append(v.causes, cause)
+}
+
+// Err returns the first error added that is not a validation error, if any.
+// Otherwise it returns nil when no violations were added, and the
+// ValidationErrors when there are some.
+func (v *ValidationErrors) Err() error {
+	if v.requestErr != nil {
+		return v.requestErr
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return v
+}
+
+// Causes returns the collected violations.
+func (v *ValidationErrors) Causes() []metav1.StatusCause {
+	return v.causes
+}
+
+func (v *ValidationErrors) Error() string {
+	if len(v.errs) == 1 {
+		return v.errs[0].Error()
+	}
+
+	var messageBuilder strings.Builder
+	{
+		messageBuilder.WriteString(fmt.Sprintf("found %d validation errors: [", len(v.causes)))
+
+		for i, cause := range v.causes {
+			if cause.Field != "" {
+				messageBuilder.WriteString(cause.Field)
+				messageBuilder.WriteString(": ")
+			}
+			messageBuilder.WriteString(cause.Message)
+
+			if len(v.causes)-i > 1 {
+				messageBuilder.WriteString(", ")
+			}
+		}
+
+		messageBuilder.WriteString("]")
+	}
+
+	return messageBuilder.String()
+}
+
+// Is reports whether any of the collected errors matches target.
+func (v *ValidationErrors) Is(target error) bool {
+	for _, err := range v.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first collected error that matches target.
+func (v *ValidationErrors) As(target interface{}) bool {
+	for _, err := range v.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Status returns the collected violations as an Invalid API status, so they
+// can be sent back to the user as part of the admission response.
+func (v *ValidationErrors) Status() metav1.Status {
+	return metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusUnprocessableEntity,
+		Reason:  metav1.StatusReasonInvalid,
+		Message: v.Error(),
+		Details: &metav1.StatusDetails{
+			Causes: v.causes,
+		},
+	}
+}
@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidationErrors(t *testing.T) {
+	testCases := []struct {
+		name            string
+		add             func(v *ValidationErrors)
+		expectedError   bool
+		expectedInvalid bool
+		expectedMessage string
+		expectedFields  []string
+		errorMatcher    func(err error) bool
+	}{
+		{
+			name:          "case 0: no errors",
+			add:           func(v *ValidationErrors) {},
+			expectedError: false,
+		},
+		{
+			name: "case 1: nil errors are ignored",
+			add: func(v *ValidationErrors) {
+				v.Add(field.NewPath("spec", "location"), nil)
+				v.Add(nil, nil)
+			},
+			expectedError: false,
+		},
+		{
+			name: "case 2: a single error keeps its message",
+			add: func(v *ValidationErrors) {
+				v.Add(field.NewPath("spec", "location"), microerror.Maskf(InvalidOperationError, "wrong location"))
+			},
+			expectedError:   true,
+			expectedInvalid: true,
+			expectedMessage: "invalid operation error: wrong location",
+			expectedFields:  []string{"spec.location"},
+			errorMatcher:    IsInvalidOperationError,
+		},
+		{
+			name: "case 3: multiple errors are all reported and matched",
+			add: func(v *ValidationErrors) {
+				v.Add(field.NewPath("spec", "location"), microerror.Maskf(UnknownReleaseError, "wrong location"))
+				v.Add(field.NewPath("spec", "template", "vmSize"), microerror.Maskf(InvalidOperationError, "wrong vm size"))
+			},
+			expectedError:   true,
+			expectedInvalid: true,
+			expectedMessage: "found 2 validation errors: [spec.location: unknown release error: wrong location, spec.template.vmSize: invalid operation error: wrong vm size]",
+			expectedFields:  []string{"spec.location", "spec.template.vmSize"},
+			errorMatcher: func(err error) bool {
+				return IsUnknownReleaseError(err) && errors.Is(err, InvalidOperationError)
+			},
+		},
+		{
+			name: "case 4: CAPI errors are expanded into their causes",
+			add: func(v *ValidationErrors) {
+				v.Add(nil, apierrors.NewInvalid(
+					schema.GroupKind{Group: "testing.x-k8s.io", Kind: "Test"},
+					"test", field.ErrorList{
+						field.Invalid(field.NewPath("metadata").Child("Name"), "testing", "Resource name is wrong"),
+						field.Invalid(field.NewPath("spec").Child("Something"), "testing", "Something is wrong"),
+					}))
+				v.Add(field.NewPath("spec", "location"), microerror.Maskf(InvalidOperationError, "wrong location"))
+			},
+			expectedError:   true,
+			expectedInvalid: true,
+			expectedFields:  []string{"metadata.Name", "spec.Something", "spec.location"},
+			errorMatcher:    IsInvalidOperationError,
+		},
+		{
+			name: "case 5: other errors are returned as they are",
+			add: func(v *ValidationErrors) {
+				v.Add(field.NewPath("spec", "location"), microerror.Maskf(InvalidOperationError, "wrong location"))
+				v.Add(field.NewPath("spec", "template", "vmSize"), microerror.Maskf(NotFoundError, "vm size lookup failed"))
+				v.Add(nil, errors.New("list failed"))
+			},
+			expectedError:   true,
+			expectedInvalid: false,
+			expectedMessage: "not found error: vm size lookup failed",
+			errorMatcher:    IsNotFoundError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var validationErrors ValidationErrors
+			tc.add(&validationErrors)
+
+			err := validationErrors.Err()
+			if !tc.expectedError {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+
+			err = microerror.Mask(err)
+
+			if tc.expectedMessage != "" && err.Error() != tc.expectedMessage {
+				t.Fatalf("expected message %q, got %q", tc.expectedMessage, err.Error())
+			}
+
+			if tc.errorMatcher != nil && !tc.errorMatcher(err) {
+				t.Fatalf("error %v did not match", err)
+			}
+
+			if !tc.expectedInvalid {
+				if apierrors.IsInvalid(err) {
+					t.Fatalf("expected an error that is not an Invalid API status, got %v", err)
+				}
+				return
+			}
+
+			if !apierrors.IsInvalid(err) {
+				t.Fatalf("expected an Invalid API status, got %v", err)
+			}
+
+			status := validationErrors.Status()
+			if status.Reason != metav1.StatusReasonInvalid {
+				t.Fatalf("expected reason %q, got %q", metav1.StatusReasonInvalid, status.Reason)
+			}
+			if len(status.Details.Causes) != len(tc.expectedFields) {
+				t.Fatalf("expected %d causes, got %d", len(tc.expectedFields), len(status.Details.Causes))
+			}
+			for i, cause := range status.Details.Causes {
+				if cause.Field != tc.expectedFields[i] {
+					t.Fatalf("expected cause %d to have field %q, got %q", i, tc.expectedFields[i], cause.Field)
+				}
+			}
+		})
+	}
+}
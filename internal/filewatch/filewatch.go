@@ -0,0 +1,145 @@
+// Package filewatch loads config files which can be changed without
+// restarting the webhook, e.g. files mounted from a ConfigMap, which are
+// updated by the kubelet.
+package filewatch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+)
+
+type Config[T any] struct {
+	Logger micrologger.Logger
+
+	// File is the path of the config file. It is optional, when empty the
+	// default value is used.
+	File string
+	// Name describes the content of the file in log messages, e.g. "quotas".
+	Name string
+	// Default is the value used until the file is loaded.
+	Default T
+	// Parse parses the content of the file into its value.
+	Parse func(content []byte) (T, error)
+}
+
+// File holds the value parsed from a config file. It is reloaded by Watch,
+// so that the config can be changed without restarting the webhook.
+type File[T any] struct {
+	logger micrologger.Logger
+	file   string
+	name   string
+	parse  func(content []byte) (T, error)
+
+	mutex   sync.RWMutex
+	content []byte
+	value   T
+}
+
+// New loads the config file. It fails when the file can't be read or parsed.
+func New[T any](config Config[T]) (*File[T], error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+	if config.Name == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Name must not be empty", config)
+	}
+	if config.Parse == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Parse must not be empty", config)
+	}
+
+	f := &File[T]{
+		logger: config.Logger,
+		file:   config.File,
+		name:   config.Name,
+		parse:  config.Parse,
+		value:  config.Default,
+	}
+
+	err := f.Reload()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return f, nil
+}
+
+// Static returns a File holding the given value, which is not read from any
+// file. It is meant for tests of the packages parsing config files.
+func Static[T any](value T) *File[T] {
+	return &File[T]{
+		value: value,
+	}
+}
+
+// Value returns the value parsed from the config file.
+func (f *File[T]) Value() T {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.value
+}
+
+// Reload reads the config file again. It is only parsed when its content
+// changed. An invalid config file is rejected as a whole, and the previously
+// loaded value is kept.
+func (f *File[T]) Reload() error {
+	if f.file == "" {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(f.file)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	f.mutex.RLock()
+	unchanged := bytes.Equal(content, f.content)
+	f.mutex.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	value, err := f.parse(content)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	f.mutex.Lock()
+	f.content = content
+	f.value = value
+	f.mutex.Unlock()
+
+	f.logger.Log("level", "info", "message", fmt.Sprintf("loaded %s from %s", f.name, f.file))
+
+	return nil
+}
+
+// Watch reloads the config file at the given interval until the context is
+// done.
+func (f *File[T]) Watch(ctx context.Context, interval time.Duration) {
+	if f.file == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := f.Reload()
+			if err != nil {
+				f.logger.Log("level", "error", "message", fmt.Sprintf("unable to reload %s from %s", f.name, f.file), "stack", microerror.JSON(err))
+			}
+		}
+	}
+}
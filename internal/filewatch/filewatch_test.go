@@ -0,0 +1,137 @@
+package filewatch
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+)
+
+var testParseError = &microerror.Error{
+	Kind: "testParseError",
+}
+
+func TestFileReload(t *testing.T) {
+	logger, _ := micrologger.New(micrologger.Config{})
+	file := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(file, []byte("1"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := 0
+	f, err := New(Config[int]{
+		Logger: logger,
+		File:   file,
+		Name:   "numbers",
+		Parse: func(content []byte) (int, error) {
+			parsed++
+			n, err := strconv.Atoi(string(content))
+			if err != nil {
+				return 0, microerror.Maskf(testParseError, "%s", err)
+			}
+
+			return n, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Value() != 1 {
+		t.Fatalf("expected 1, got %d", f.Value())
+	}
+
+	// Unchanged content is not parsed again.
+	err = f.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != 1 {
+		t.Fatalf("expected the file to be parsed once, got %d times", parsed)
+	}
+
+	err = os.WriteFile(file, []byte("2"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Value() != 2 {
+		t.Fatalf("expected 2, got %d", f.Value())
+	}
+
+	// An invalid file keeps the previously loaded value.
+	err = os.WriteFile(file, []byte("two"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.Reload()
+	if microerror.Cause(err) != testParseError {
+		t.Fatalf("expected parse error, got %#v", err)
+	}
+	if f.Value() != 2 {
+		t.Fatalf("expected the previously loaded value 2 to be kept, got %d", f.Value())
+	}
+}
+
+func TestFileDefault(t *testing.T) {
+	logger, _ := micrologger.New(micrologger.Config{})
+	f, err := New(Config[int]{
+		Logger:  logger,
+		Name:    "numbers",
+		Default: 7,
+		Parse: func(content []byte) (int, error) {
+			t.Fatal("expected no file to be parsed")
+			return 0, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = f.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Value() != 7 {
+		t.Fatalf("expected the default value 7, got %d", f.Value())
+	}
+}
+
+func TestFileInvalid(t *testing.T) {
+	logger, _ := micrologger.New(micrologger.Config{})
+	file := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(file, []byte("one"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = New(Config[int]{
+		Logger: logger,
+		File:   file,
+		Name:   "numbers",
+		Parse: func(content []byte) (int, error) {
+			return 0, microerror.Mask(testParseError)
+		},
+	})
+	if microerror.Cause(err) != testParseError {
+		t.Fatalf("expected parse error, got %#v", err)
+	}
+}
+
+func TestFileStatic(t *testing.T) {
+	f := Static(3)
+
+	err := f.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Value() != 3 {
+		t.Fatalf("expected the static value 3, got %d", f.Value())
+	}
+}
@@ -0,0 +1,50 @@
+package maintenance
+
+import (
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var invalidScheduleError = &microerror.Error{
+	Kind: "invalidScheduleError",
+}
+
+// IsInvalidSchedule asserts invalidScheduleError.
+func IsInvalidSchedule(err error) bool {
+	return microerror.Cause(err) == invalidScheduleError
+}
+
+var changeFreezeError = &microerror.Error{
+	Kind: "changeFreezeError",
+}
+
+// IsChangeFreeze asserts changeFreezeError.
+func IsChangeFreeze(err error) bool {
+	return microerror.Cause(err) == changeFreezeError
+}
+
+var outsideMaintenanceWindowError = &microerror.Error{
+	Kind: "outsideMaintenanceWindowError",
+}
+
+// IsOutsideMaintenanceWindow asserts outsideMaintenanceWindowError.
+func IsOutsideMaintenanceWindow(err error) bool {
+	return microerror.Cause(err) == outsideMaintenanceWindowError
+}
+
+func init() {
+	errors.RegisterValidationErrors(
+		changeFreezeError,
+		outsideMaintenanceWindowError,
+	)
+}
@@ -0,0 +1,166 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/azure-admission-controller/internal/filewatch"
+)
+
+const (
+	// PolicyInstallation is the name of the policy of the installation.
+	PolicyInstallation = "installation"
+)
+
+// FileConfig is the content of the maintenance file.
+//
+// Example:
+//
+//	installation:
+//	  windows:
+//	    - days: [Monday, Tuesday, Wednesday, Thursday]
+//	      start: "08:00"
+//	      end: "16:00"
+//	  freezes:
+//	    - name: holidays
+//	      start: "2022-12-20"
+//	      end: "2023-01-06"
+//	organizations:
+//	  acme:
+//	    windows:
+//	      - days: [Saturday]
+//	        start: "02:00"
+//	        end: "06:00"
+type FileConfig struct {
+	// Installation is the policy of all organizations of the installation.
+	Installation Policy `json:"installation"`
+	// Organizations overrides the maintenance windows of the installation
+	// policy per organization, and adds change freezes to the ones of the
+	// installation.
+	Organizations map[string]Policy `json:"organizations,omitempty"`
+}
+
+type RegistryConfig struct {
+	Logger micrologger.Logger
+
+	// File is the path of the maintenance file. It is optional, when empty
+	// upgrades can happen at any time.
+	File string
+}
+
+// Registry holds the maintenance windows and change freezes of the
+// installation. They are read from the maintenance file, which is reloaded by
+// Watch, so that they can be changed without restarting the webhook.
+type Registry struct {
+	file *filewatch.File[schedules]
+}
+
+// schedules are the parsed maintenance schedules of the installation and its
+// organizations.
+type schedules struct {
+	installation  Schedule
+	organizations map[string]Schedule
+}
+
+func NewRegistry(config RegistryConfig) (*Registry, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	file, err := filewatch.New(filewatch.Config[schedules]{
+		Logger: config.Logger,
+		File:   config.File,
+		Name:   "maintenance schedules",
+		Parse:  parse,
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r := &Registry{
+		file: file,
+	}
+
+	return r, nil
+}
+
+// Schedule returns the maintenance schedule of the given organization. The
+// maintenance windows of the organization replace the ones of the
+// installation, and the change freezes of both apply.
+func (r *Registry) Schedule(organization string) Schedule {
+	if r == nil {
+		return Schedule{}
+	}
+
+	loaded := r.file.Value()
+
+	s := loaded.installation
+	if o, ok := loaded.organizations[organization]; ok {
+		if len(o.windows) > 0 {
+			s.windows = o.windows
+		}
+		s.freezes = append(append([]freeze{}, s.freezes...), o.freezes...)
+	}
+
+	return s
+}
+
+// Reload reads the maintenance file again. An invalid maintenance file is
+// rejected as a whole, and the previously loaded schedules are kept.
+func (r *Registry) Reload() error {
+	return microerror.Mask(r.file.Reload())
+}
+
+// Watch reloads the maintenance file at the given interval until the context
+// is done.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	r.file.Watch(ctx, interval)
+}
+
+func parse(content []byte) (schedules, error) {
+	var fileConfig FileConfig
+	err := yaml.UnmarshalStrict(content, &fileConfig)
+	if err != nil {
+		return schedules{}, microerror.Maskf(invalidScheduleError, "%s", err)
+	}
+
+	installation, err := parsePolicy(PolicyInstallation, fileConfig.Installation)
+	if err != nil {
+		return schedules{}, microerror.Mask(err)
+	}
+
+	organizations := map[string]Schedule{}
+	for organization, p := range fileConfig.Organizations {
+		organizations[organization], err = parsePolicy(fmt.Sprintf("organization %s", organization), p)
+		if err != nil {
+			return schedules{}, microerror.Mask(err)
+		}
+	}
+
+	return schedules{installation: installation, organizations: organizations}, nil
+}
+
+func parsePolicy(name string, p Policy) (Schedule, error) {
+	var s Schedule
+	for _, w := range p.Windows {
+		parsed, err := parseWindow(name, w)
+		if err != nil {
+			return Schedule{}, microerror.Mask(err)
+		}
+		s.windows = append(s.windows, parsed)
+	}
+	for _, f := range p.Freezes {
+		parsed, err := parseFreeze(name, f)
+		if err != nil {
+			return Schedule{}, microerror.Mask(err)
+		}
+		s.freezes = append(s.freezes, parsed)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,217 @@
+package maintenance
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/azure-admission-controller/internal/filewatch"
+)
+
+const testConfig = `
+installation:
+  windows:
+    - days: [Monday, Tuesday, Wednesday, Thursday]
+      start: "08:00"
+      end: "16:00"
+  freezes:
+    - name: holidays
+      start: 2022-12-20
+      end: 2023-01-06
+organizations:
+  acme:
+    windows:
+      - days: [saturday]
+        start: "02:00"
+        end: "24:00"
+    freezes:
+      - name: acme launch
+        start: 2022-11-01T12:00:00Z
+        end: 2022-11-02T12:00:00Z
+`
+
+func TestScheduleCheckScheduledTime(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       string
+		organization string
+		time         string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: no maintenance file",
+			config:       "",
+			organization: "giantswarm",
+			time:         "2022-12-24T23:00:00Z",
+		},
+		{
+			name:         "case 1: within an installation window",
+			config:       testConfig,
+			organization: "giantswarm",
+			time:         "2022-11-01T08:00:00Z",
+		},
+		{
+			name:         "case 2: end of an installation window is exclusive",
+			config:       testConfig,
+			organization: "giantswarm",
+			time:         "2022-11-01T16:00:00Z",
+			errorMatcher: IsOutsideMaintenanceWindow,
+		},
+		{
+			name:         "case 3: weekday without window",
+			config:       testConfig,
+			organization: "giantswarm",
+			time:         "2022-11-04T10:00:00Z",
+			errorMatcher: IsOutsideMaintenanceWindow,
+		},
+		{
+			name:         "case 4: installation freeze lasts until the end of its last day",
+			config:       testConfig,
+			organization: "giantswarm",
+			time:         "2023-01-05T10:00:00Z",
+			errorMatcher: IsChangeFreeze,
+		},
+		{
+			name:         "case 5: after the installation freeze",
+			config:       testConfig,
+			organization: "giantswarm",
+			time:         "2023-01-09T10:00:00Z",
+		},
+		{
+			name:         "case 6: organization windows replace the installation ones",
+			config:       testConfig,
+			organization: "acme",
+			time:         "2022-11-08T10:00:00Z",
+			errorMatcher: IsOutsideMaintenanceWindow,
+		},
+		{
+			name:         "case 7: within an organization window",
+			config:       testConfig,
+			organization: "acme",
+			time:         "2022-11-05T23:59:00Z",
+		},
+		{
+			name:         "case 8: installation freezes apply to organizations",
+			config:       testConfig,
+			organization: "acme",
+			time:         "2022-12-24T10:00:00Z",
+			errorMatcher: IsChangeFreeze,
+		},
+		{
+			name:         "case 9: organization freeze",
+			config:       "organizations:\n  acme:\n    freezes:\n      - start: 2022-11-01T12:00:00Z\n        end: 2022-11-02T12:00:00Z\n",
+			organization: "acme",
+			time:         "2022-11-02T11:59:00Z",
+			errorMatcher: IsChangeFreeze,
+		},
+		{
+			name:         "case 10: organization freezes don't apply to other organizations",
+			config:       "organizations:\n  acme:\n    freezes:\n      - start: 2022-11-01T12:00:00Z\n        end: 2022-11-02T12:00:00Z\n",
+			organization: "giantswarm",
+			time:         "2022-11-02T11:59:00Z",
+		},
+		{
+			name:         "case 11: windows are in UTC",
+			config:       testConfig,
+			organization: "giantswarm",
+			time:         "2022-11-01T17:30:00+02:00",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			scheduledTime, err := time.Parse(time.RFC3339, tc.time)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = newTestRegistry(t, tc.config).Schedule(tc.organization).CheckScheduledTime(scheduledTime)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
+
+func TestScheduleCheckChange(t *testing.T) {
+	registry := newTestRegistry(t, testConfig)
+
+	err := registry.Schedule("acme").CheckChange(time.Date(2022, 12, 20, 0, 0, 0, 0, time.UTC))
+	if !IsChangeFreeze(err) {
+		t.Fatalf("expected change freeze error, got %#v", err)
+	}
+
+	// Changes are not bound to maintenance windows.
+	err = registry.Schedule("acme").CheckChange(time.Date(2022, 11, 8, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	var nilRegistry *Registry
+	err = nilRegistry.Schedule("acme").CheckChange(time.Date(2022, 12, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error without registry: %#v", err)
+	}
+}
+
+func TestParseInvalidSchedule(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "case 0: unknown field",
+			config: "installation:\n  blackouts: []\n",
+		},
+		{
+			name:   "case 1: unknown weekday",
+			config: "installation:\n  windows:\n    - days: [Someday]\n      start: \"08:00\"\n      end: \"16:00\"\n",
+		},
+		{
+			name:   "case 2: invalid start",
+			config: "installation:\n  windows:\n    - start: \"8am\"\n      end: \"16:00\"\n",
+		},
+		{
+			name:   "case 3: window ending before it starts",
+			config: "installation:\n  windows:\n    - start: \"16:00\"\n      end: \"08:00\"\n",
+		},
+		{
+			name:   "case 4: invalid freeze date",
+			config: "organizations:\n  acme:\n    freezes:\n      - start: 2022-12-32\n        end: 2023-01-06\n",
+		},
+		{
+			name:   "case 5: freeze ending before it starts",
+			config: "installation:\n  freezes:\n    - start: 2023-01-06\n      end: 2022-12-20\n",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			_, err := parse([]byte(tc.config))
+			if !IsInvalidSchedule(err) {
+				t.Fatalf("expected invalid schedule error, got %#v", err)
+			}
+		})
+	}
+}
+
+func newTestRegistry(t *testing.T, config string) *Registry {
+	schedules, err := parse([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Registry{file: filewatch.Static(schedules)}
+}
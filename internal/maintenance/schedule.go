@@ -0,0 +1,251 @@
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// FreezeOverrideAnnotation allows changing the release of a cluster
+	// during a change freeze when it is set to "true".
+	FreezeOverrideAnnotation = "alpha.giantswarm.io/change-freeze-override"
+
+	dateFormat  = "2006-01-02"
+	clockFormat = "15:04"
+)
+
+// Policy defines when clusters of an organization can be upgraded.
+type Policy struct {
+	// Windows are the recurring maintenance windows in which upgrades can be
+	// scheduled. Upgrades can be scheduled at any time when there are none.
+	Windows []Window `json:"windows,omitempty"`
+	// Freezes are the periods in which clusters can't be upgraded at all.
+	Freezes []Freeze `json:"freezes,omitempty"`
+}
+
+// Window is a recurring maintenance window.
+type Window struct {
+	// Days are the weekdays of the window, e.g. Monday. The window is open
+	// every day when empty.
+	Days []string `json:"days,omitempty"`
+	// Start and End are the times of day (15:04, UTC) the window opens and
+	// closes. End is exclusive and can be 24:00.
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Freeze is a change freeze, e.g. over the holidays.
+type Freeze struct {
+	// Name describes the freeze in error messages.
+	Name string `json:"name,omitempty"`
+	// Start and End are the dates (2006-01-02, UTC) or RFC3339 times the
+	// freeze starts and ends. An End date is inclusive, the freeze lasts
+	// until the end of that day.
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Schedule is the maintenance schedule of an organization, see
+// Registry.Schedule.
+type Schedule struct {
+	windows []window
+	freezes []freeze
+}
+
+type window struct {
+	days  map[time.Weekday]bool
+	start time.Duration
+	end   time.Duration
+}
+
+type freeze struct {
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+// CheckScheduledTime returns an error when an upgrade can't be scheduled at the
+// given time, as it is during a change freeze or outside of every maintenance
+// window.
+func (s Schedule) CheckScheduledTime(t time.Time) error {
+	if f := s.activeFreeze(t); f != nil {
+		return microerror.Maskf(changeFreezeError, "%s is during the change freeze %s", t.UTC().Format(time.RFC822), f)
+	}
+
+	if len(s.windows) == 0 {
+		return nil
+	}
+	for _, w := range s.windows {
+		if w.contains(t) {
+			return nil
+		}
+	}
+
+	var windows []string
+	for _, w := range s.windows {
+		windows = append(windows, w.String())
+	}
+
+	return microerror.Maskf(outsideMaintenanceWindowError, "%s is outside of the maintenance windows %s", t.UTC().Format(time.RFC822), strings.Join(windows, ", "))
+}
+
+// HasWindows returns whether the schedule has maintenance windows, i.e.
+// whether upgrades can only be scheduled at some times.
+func (s Schedule) HasWindows() bool {
+	return len(s.windows) > 0
+}
+
+// CheckChange returns an error when clusters can't be changed at the given
+// time, as it is during a change freeze.
+func (s Schedule) CheckChange(t time.Time) error {
+	if f := s.activeFreeze(t); f != nil {
+		return microerror.Maskf(changeFreezeError, "clusters can't be upgraded during the change freeze %s", f)
+	}
+
+	return nil
+}
+
+func (s Schedule) activeFreeze(t time.Time) *freeze {
+	for i, f := range s.freezes {
+		if !t.Before(f.start) && t.Before(f.end) {
+			return &s.freezes[i]
+		}
+	}
+
+	return nil
+}
+
+func (w window) contains(t time.Time) bool {
+	t = t.UTC()
+	if len(w.days) > 0 && !w.days[t.Weekday()] {
+		return false
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := t.Sub(midnight)
+
+	return offset >= w.start && offset < w.end
+}
+
+// String returns the window in the form "Monday, Tuesday 08:00-18:00 UTC".
+func (w window) String() string {
+	days := "every day"
+	if len(w.days) > 0 {
+		var names []string
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if w.days[d] {
+				names = append(names, d.String())
+			}
+		}
+		days = strings.Join(names, ", ")
+	}
+
+	return fmt.Sprintf("%s %s-%s UTC", days, formatClock(w.start), formatClock(w.end))
+}
+
+// String returns the freeze in the form "holidays (2022-12-20T00:00:00Z to
+// 2023-01-07T00:00:00Z)".
+func (f freeze) String() string {
+	period := fmt.Sprintf("(%s to %s)", f.start.Format(time.RFC3339), f.end.Format(time.RFC3339))
+	if f.name == "" {
+		return period
+	}
+
+	return fmt.Sprintf("%s %s", f.name, period)
+}
+
+func parseWindow(name string, w Window) (window, error) {
+	result := window{}
+
+	if len(w.Days) > 0 {
+		result.days = map[time.Weekday]bool{}
+		for _, day := range w.Days {
+			weekday, ok := parseWeekday(day)
+			if !ok {
+				return window{}, microerror.Maskf(invalidScheduleError, "day %#q of a maintenance window of the %s policy is not a weekday", day, name)
+			}
+			result.days[weekday] = true
+		}
+	}
+
+	var err error
+	result.start, err = parseClock(w.Start)
+	if err != nil {
+		return window{}, microerror.Maskf(invalidScheduleError, "start %#q of a maintenance window of the %s policy must be a time like 08:00", w.Start, name)
+	}
+	result.end, err = parseClock(w.End)
+	if err != nil {
+		return window{}, microerror.Maskf(invalidScheduleError, "end %#q of a maintenance window of the %s policy must be a time like 18:00", w.End, name)
+	}
+	if result.end <= result.start {
+		return window{}, microerror.Maskf(invalidScheduleError, "end %#q of a maintenance window of the %s policy must be after its start %#q", w.End, name, w.Start)
+	}
+
+	return result, nil
+}
+
+func parseFreeze(name string, f Freeze) (freeze, error) {
+	start, _, err := parseTime(f.Start)
+	if err != nil {
+		return freeze{}, microerror.Maskf(invalidScheduleError, "start %#q of a change freeze of the %s policy must be a date like 2006-01-02 or an RFC3339 time", f.Start, name)
+	}
+	end, isDate, err := parseTime(f.End)
+	if err != nil {
+		return freeze{}, microerror.Maskf(invalidScheduleError, "end %#q of a change freeze of the %s policy must be a date like 2006-01-02 or an RFC3339 time", f.End, name)
+	}
+	if isDate {
+		end = end.Add(24 * time.Hour)
+	}
+	if !end.After(start) {
+		return freeze{}, microerror.Maskf(invalidScheduleError, "end %#q of a change freeze of the %s policy must be after its start %#q", f.End, name, f.Start)
+	}
+
+	return freeze{name: f.Name, start: start, end: end}, nil
+}
+
+func parseWeekday(day string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), day) {
+			return d, true
+		}
+	}
+
+	return 0, false
+}
+
+// parseClock returns the offset from midnight of the given time of day.
+func parseClock(value string) (time.Duration, error) {
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+
+	t, err := time.Parse(clockFormat, value)
+	if err != nil {
+		return 0, microerror.Mask(err)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func formatClock(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60)
+}
+
+// parseTime parses a date or an RFC3339 time, and returns whether it was a
+// date.
+func parseTime(value string) (time.Time, bool, error) {
+	t, err := time.Parse(dateFormat, value)
+	if err == nil {
+		return t, true, nil
+	}
+
+	t, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, microerror.Mask(err)
+	}
+
+	return t.UTC(), false, nil
+}
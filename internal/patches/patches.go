@@ -28,7 +28,11 @@ func GenerateFrom(originalJSON []byte, current runtime.Object) ([]mutator.PatchO
 
 		patches = make([]mutator.PatchOperation, 0, len(jsonPatches))
 		for _, patch := range jsonPatches {
-			patches = append(patches, mutator.PatchOperation(patch))
+			patches = append(patches, mutator.PatchOperation{
+				Operation: patch.Operation,
+				Path:      patch.Path,
+				Value:     patch.Value,
+			})
 		}
 
 		sort.SliceStable(patches, func(i, j int) bool {
@@ -59,7 +63,11 @@ func GenerateFromObjectDiff(original runtime.Object, current runtime.Object) ([]
 
 		patches = make([]mutator.PatchOperation, 0, len(jsonPatches))
 		for _, patch := range jsonPatches {
-			patches = append(patches, mutator.PatchOperation(patch))
+			patches = append(patches, mutator.PatchOperation{
+				Operation: patch.Operation,
+				Path:      patch.Path,
+				Value:     patch.Value,
+			})
 		}
 
 		sort.SliceStable(patches, func(i, j int) bool {
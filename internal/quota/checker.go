@@ -0,0 +1,249 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/giantswarm/apiextensions/v6/pkg/annotation"
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
+)
+
+type CheckerConfig struct {
+	CtrlClient    client.Client
+	CtrlReader    client.Reader
+	Logger        micrologger.Logger
+	Registry      *Registry
+	VMcapsFactory vmcapabilities.Factory
+}
+
+// Checker computes the worst-case usage of the node pools of an organization
+// and checks it against the organization's quota. The node pools are read
+// through the controller-runtime cache, as every node pool request lists all
+// node pools of the organization.
+type Checker struct {
+	ctrlClient    client.Client
+	ctrlReader    client.Reader
+	logger        micrologger.Logger
+	registry      *Registry
+	vmcapsFactory vmcapabilities.Factory
+}
+
+// Usage is the worst-case size of the node pools of an organization, i.e. the
+// size they have when all of them are scaled up to their maximum.
+type Usage struct {
+	Nodes int
+	CPUs  int
+}
+
+// Pending holds the objects of the request being validated. They replace the
+// stored objects with the same namespace and name when computing the usage,
+// as they are not stored yet.
+type Pending struct {
+	MachinePool      *capiexp.MachinePool
+	AzureMachinePool *capzexp.AzureMachinePool
+}
+
+func NewChecker(config CheckerConfig) (*Checker, error) {
+	if config.CtrlClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CtrlClient must not be empty", config)
+	}
+	if config.CtrlReader == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CtrlReader must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+	if config.VMcapsFactory == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.VMcapsFactory must not be empty", config)
+	}
+
+	c := &Checker{
+		ctrlClient:    config.CtrlClient,
+		ctrlReader:    config.CtrlReader,
+		logger:        config.Logger,
+		registry:      config.Registry,
+		vmcapsFactory: config.VMcapsFactory,
+	}
+
+	return c, nil
+}
+
+// Check returns quotaExceededError when the node pools of the organization
+// would exceed its quota once the pending objects are stored. Changes that
+// don't increase the usage are allowed, so that organizations already over a
+// lowered quota can still scale down. A nil checker and organizations without
+// quota are never checked.
+func (c *Checker) Check(ctx context.Context, organization string, pending Pending) error {
+	if c == nil {
+		return nil
+	}
+
+	q := c.registry.Quota(organization)
+	if q.MaxNodes == nil && q.MaxCPUs == nil {
+		return nil
+	}
+
+	current, desired, err := c.usage(ctx, organization, pending)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if q.MaxNodes != nil && desired.Nodes > *q.MaxNodes && desired.Nodes > current.Nodes {
+		return microerror.Maskf(quotaExceededError, "The node pools of organization %#q would scale up to %d nodes, which exceeds its quota of %d nodes. They currently scale up to %d nodes", organization, desired.Nodes, *q.MaxNodes, current.Nodes)
+	}
+	if q.MaxCPUs != nil && desired.CPUs > *q.MaxCPUs && desired.CPUs > current.CPUs {
+		return microerror.Maskf(quotaExceededError, "The node pools of organization %#q would scale up to %d vCPUs, which exceeds its quota of %d vCPUs. They currently scale up to %d vCPUs", organization, desired.CPUs, *q.MaxCPUs, current.CPUs)
+	}
+
+	return nil
+}
+
+// usage returns the current worst-case usage of the node pools of the
+// organization, and the desired one, with the pending objects replacing the
+// stored ones. The node pools are listed once for both.
+func (c *Checker) usage(ctx context.Context, organization string, pending Pending) (Usage, Usage, error) {
+	var machinePools capiexp.MachinePoolList
+	err := c.ctrlReader.List(ctx, &machinePools, client.MatchingLabels{label.Organization: organization})
+	if err != nil {
+		return Usage{}, Usage{}, microerror.Mask(err)
+	}
+
+	var current, desired Usage
+	var pendingStored bool
+	for i := range machinePools.Items {
+		mp := &machinePools.Items[i]
+
+		cpus, err := c.nodeCPUs(ctx, mp, nil)
+		if err != nil {
+			return Usage{}, Usage{}, microerror.Mask(err)
+		}
+		current.add(mp, cpus)
+
+		desiredMP := mp
+		if pending.MachinePool != nil && isSameObject(mp, pending.MachinePool) {
+			desiredMP = pending.MachinePool
+			pendingStored = true
+		}
+
+		// Only look up the vCPUs again when the pending objects change them.
+		if desiredMP != mp || references(mp, pending.AzureMachinePool) {
+			cpus, err = c.nodeCPUs(ctx, desiredMP, pending.AzureMachinePool)
+			if err != nil {
+				return Usage{}, Usage{}, microerror.Mask(err)
+			}
+		}
+		desired.add(desiredMP, cpus)
+	}
+
+	if pending.MachinePool != nil && !pendingStored {
+		cpus, err := c.nodeCPUs(ctx, pending.MachinePool, pending.AzureMachinePool)
+		if err != nil {
+			return Usage{}, Usage{}, microerror.Mask(err)
+		}
+		desired.add(pending.MachinePool, cpus)
+	}
+
+	return current, desired, nil
+}
+
+// add counts the nodes and vCPUs the MachinePool can scale up to. Node pools
+// being deleted are not counted.
+func (u *Usage) add(mp *capiexp.MachinePool, cpus int) {
+	if !mp.GetDeletionTimestamp().IsZero() {
+		return
+	}
+
+	nodes := MaxNodes(mp)
+	u.Nodes += nodes
+	u.CPUs += nodes * cpus
+}
+
+// nodeCPUs returns the vCPUs of the VM size of the AzureMachinePool of the
+// MachinePool. Node pools which AzureMachinePool or VM size can't be found
+// don't count any vCPUs, the AzureMachinePool validation rejects them anyway.
+func (c *Checker) nodeCPUs(ctx context.Context, mp *capiexp.MachinePool, pendingAMP *capzexp.AzureMachinePool) (int, error) {
+	var amp *capzexp.AzureMachinePool
+	if references(mp, pendingAMP) {
+		amp = pendingAMP
+	} else {
+		amp = &capzexp.AzureMachinePool{}
+		err := c.ctrlReader.Get(ctx, infrastructureRefKey(mp), amp)
+		if apierrors.IsNotFound(err) {
+			return 0, nil
+		} else if err != nil {
+			return 0, microerror.Mask(err)
+		}
+	}
+
+	vmcaps, err := c.vmcapsFactory.GetClient(ctx, c.ctrlClient, amp.ObjectMeta)
+	if err != nil {
+		return 0, microerror.Mask(err)
+	}
+
+	cpus, err := vmcaps.CPUs(ctx, amp.Spec.Location, amp.Spec.Template.VMSize)
+	if vmcapabilities.IsSkuNotFoundError(err) {
+		c.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("VM size %s of AzureMachinePool %s/%s not found, not counting its vCPUs", amp.Spec.Template.VMSize, amp.Namespace, amp.Name))
+		return 0, nil
+	} else if err != nil {
+		return 0, microerror.Mask(err)
+	}
+
+	return cpus, nil
+}
+
+// MaxNodes returns the number of nodes the MachinePool can scale up to, the
+// maximum of its replicas and the node pool max size annotation used by the
+// cluster autoscaler.
+func MaxNodes(mp *capiexp.MachinePool) int {
+	nodes := 1
+	if mp.Spec.Replicas != nil {
+		nodes = int(*mp.Spec.Replicas)
+	}
+
+	max, err := strconv.Atoi(mp.Annotations[annotation.NodePoolMaxSize])
+	if err == nil && max > nodes {
+		nodes = max
+	}
+
+	return nodes
+}
+
+// MaxNodesIncreased returns true when the MachinePool can scale up to more
+// nodes than before.
+func MaxNodesIncreased(oldMP *capiexp.MachinePool, newMP *capiexp.MachinePool) bool {
+	return MaxNodes(newMP) > MaxNodes(oldMP)
+}
+
+func isSameObject(a client.Object, b client.Object) bool {
+	return a.GetNamespace() == b.GetNamespace() && a.GetName() == b.GetName()
+}
+
+// references returns true when the AzureMachinePool is the infrastructure of
+// the MachinePool.
+func references(mp *capiexp.MachinePool, amp *capzexp.AzureMachinePool) bool {
+	if amp == nil {
+		return false
+	}
+
+	key := infrastructureRefKey(mp)
+	return amp.Namespace == key.Namespace && amp.Name == key.Name
+}
+
+func infrastructureRefKey(mp *capiexp.MachinePool) client.ObjectKey {
+	ref := mp.Spec.Template.Spec.InfrastructureRef
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = mp.Namespace
+	}
+
+	return client.ObjectKey{Namespace: namespace, Name: ref.Name}
+}
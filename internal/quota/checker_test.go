@@ -0,0 +1,219 @@
+package quota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/giantswarm/apiextensions/v6/pkg/annotation"
+	"github.com/giantswarm/micrologger"
+	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/internal/filewatch"
+	ampbuilder "github.com/giantswarm/azure-admission-controller/internal/test/azuremachinepool"
+	mpbuilder "github.com/giantswarm/azure-admission-controller/internal/test/machinepool"
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+const testQuota = `
+default:
+  maxNodes: 10
+organizations:
+  acme:
+    maxCPUs: 40
+`
+
+func TestCheck(t *testing.T) {
+	testCases := []struct {
+		name         string
+		quota        string
+		organization string
+		pending      func(mp *capiexp.MachinePool, amp *capzexp.AzureMachinePool) Pending
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: no quota file",
+			quota:        "",
+			organization: "giantswarm",
+			pending: func(mp *capiexp.MachinePool, _ *capzexp.AzureMachinePool) Pending {
+				return Pending{MachinePool: scaled(mp, 3, "100")}
+			},
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 1: scaling within the default node quota",
+			quota:        testQuota,
+			organization: "giantswarm",
+			pending: func(mp *capiexp.MachinePool, _ *capzexp.AzureMachinePool) Pending {
+				return Pending{MachinePool: scaled(mp, 3, "7")}
+			},
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 2: autoscaler max size exceeding the default node quota",
+			quota:        testQuota,
+			organization: "giantswarm",
+			pending: func(mp *capiexp.MachinePool, _ *capzexp.AzureMachinePool) Pending {
+				return Pending{MachinePool: scaled(mp, 3, "8")}
+			},
+			errorMatcher: IsQuotaExceeded,
+		},
+		{
+			name:         "case 3: replicas exceeding the quota of an organization with its own limits",
+			quota:        testQuota,
+			organization: "acme",
+			pending: func(mp *capiexp.MachinePool, _ *capzexp.AzureMachinePool) Pending {
+				return Pending{MachinePool: scaled(mp, 8, "8")}
+			},
+			errorMatcher: IsQuotaExceeded,
+		},
+		{
+			name:         "case 4: bigger VM size exceeding the organization vCPU quota",
+			quota:        testQuota,
+			organization: "acme",
+			pending: func(_ *capiexp.MachinePool, amp *capzexp.AzureMachinePool) Pending {
+				amp = amp.DeepCopy()
+				amp.Spec.Template.VMSize = "Standard_D16s_v3"
+				return Pending{AzureMachinePool: amp}
+			},
+			errorMatcher: IsQuotaExceeded,
+		},
+		{
+			name:         "case 5: scaling down while over a lowered quota",
+			quota:        "default:\n  maxNodes: 2\n",
+			organization: "giantswarm",
+			pending: func(mp *capiexp.MachinePool, _ *capzexp.AzureMachinePool) Pending {
+				return Pending{MachinePool: scaled(mp, 2, "2")}
+			},
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 6: new node pool exceeding the default node quota",
+			quota:        testQuota,
+			organization: "giantswarm",
+			pending: func(mp *capiexp.MachinePool, _ *capzexp.AzureMachinePool) Pending {
+				mp = scaled(mp, 5, "5")
+				mp.Name = "np003"
+				return Pending{MachinePool: mp}
+			},
+			errorMatcher: IsQuotaExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := micrologger.New(micrologger.Config{})
+			ctx := context.Background()
+			ctrlClient := unittest.FakeK8sClient().CtrlClient()
+
+			// Two node pools of 3 nodes with 4 vCPUs each, i.e. 6 nodes and
+			// 24 vCPUs in total.
+			var mp *capiexp.MachinePool
+			var amp *capzexp.AzureMachinePool
+			for _, name := range []string{"np001", "np002"} {
+				amp = ampbuilder.BuildAzureMachinePool(ampbuilder.Name(name), ampbuilder.Organization(tc.organization), ampbuilder.VMSize("Standard_D4s_v3"))
+				err := ctrlClient.Create(ctx, amp)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				mp = mpbuilder.BuildMachinePool(mpbuilder.Name(name), mpbuilder.Organization(tc.organization), mpbuilder.AzureMachinePool(name), mpbuilder.Replicas(3))
+				err = ctrlClient.Create(ctx, mp)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			registry := newTestRegistry(t, tc.quota)
+
+			stubbedSKUs := map[string]compute.ResourceSku{
+				"Standard_D4s_v3":  testSKU("Standard_D4s_v3", "4"),
+				"Standard_D16s_v3": testSKU("Standard_D16s_v3", "16"),
+			}
+			reader := &countingReader{Reader: ctrlClient}
+			checker, err := NewChecker(CheckerConfig{
+				CtrlClient:    ctrlClient,
+				CtrlReader:    reader,
+				Logger:        logger,
+				Registry:      registry,
+				VMcapsFactory: unittest.NewVMCapsStubFactory(stubbedSKUs, logger),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = checker.Check(ctx, tc.organization, tc.pending(mp, amp))
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			// The node pools are listed once for the current and the
+			// desired usage, and only when there is a quota.
+			expectedLists := 1
+			if tc.quota == "" {
+				expectedLists = 0
+			}
+			if reader.lists != expectedLists {
+				t.Fatalf("expected %d node pool lists, got %d", expectedLists, reader.lists)
+			}
+		})
+	}
+}
+
+func TestParseInvalidQuota(t *testing.T) {
+	_, err := parse([]byte("organizations:\n  acme:\n    maxNodes: -1\n"))
+	if !IsInvalidQuota(err) {
+		t.Fatalf("expected invalid quota error, got %#v", err)
+	}
+}
+
+func newTestRegistry(t *testing.T, quota string) *Registry {
+	fileConfig, err := parse([]byte(quota))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Registry{file: filewatch.Static(fileConfig)}
+}
+
+func scaled(mp *capiexp.MachinePool, replicas int32, maxSize string) *capiexp.MachinePool {
+	mp = mp.DeepCopy()
+	mp.Spec.Replicas = to.Int32Ptr(replicas)
+	mp.Annotations[annotation.NodePoolMaxSize] = maxSize
+	return mp
+}
+
+func testSKU(name string, cpus string) compute.ResourceSku {
+	return compute.ResourceSku{
+		Name: to.StringPtr(name),
+		Capabilities: &[]compute.ResourceSkuCapabilities{
+			{
+				Name:  to.StringPtr("vCPUs"),
+				Value: to.StringPtr(cpus),
+			},
+		},
+	}
+}
+
+// countingReader counts the List calls, to check that the node pools are only
+// listed once per request.
+type countingReader struct {
+	client.Reader
+	lists int
+}
+
+func (r *countingReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	r.lists++
+	return r.Reader.List(ctx, list, opts...)
+}
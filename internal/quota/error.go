@@ -0,0 +1,40 @@
+package quota
+
+import (
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+)
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var invalidQuotaError = &microerror.Error{
+	Kind: "invalidQuotaError",
+}
+
+// IsInvalidQuota asserts invalidQuotaError.
+func IsInvalidQuota(err error) bool {
+	return microerror.Cause(err) == invalidQuotaError
+}
+
+var quotaExceededError = &microerror.Error{
+	Kind: "quotaExceededError",
+}
+
+// IsQuotaExceeded asserts quotaExceededError.
+func IsQuotaExceeded(err error) bool {
+	return microerror.Cause(err) == quotaExceededError
+}
+
+func init() {
+	errors.RegisterValidationErrors(
+		quotaExceededError,
+	)
+}
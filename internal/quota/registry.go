@@ -0,0 +1,143 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/azure-admission-controller/internal/filewatch"
+)
+
+// Quota limits the worst-case size of the node pools of an organization.
+// Unset fields don't limit anything.
+type Quota struct {
+	// MaxNodes is the maximum number of nodes of all node pools.
+	MaxNodes *int `json:"maxNodes,omitempty"`
+	// MaxCPUs is the maximum number of vCPUs of all nodes.
+	MaxCPUs *int `json:"maxCPUs,omitempty"`
+}
+
+// FileConfig is the content of the quota file.
+//
+// Example:
+//
+//	default:
+//	  maxNodes: 50
+//	organizations:
+//	  acme:
+//	    maxNodes: 100
+//	    maxCPUs: 800
+type FileConfig struct {
+	// Default is the quota of organizations without their own quota.
+	Default Quota `json:"default"`
+	// Organizations overrides the limits of the default quota per
+	// organization.
+	Organizations map[string]Quota `json:"organizations,omitempty"`
+}
+
+type RegistryConfig struct {
+	Logger micrologger.Logger
+
+	// File is the path of the quota file. It is optional, when empty no
+	// quota is enforced.
+	File string
+}
+
+// Registry holds the quotas of the organizations. They are read from the quota
+// file, which is reloaded by Watch, so that they can be changed without
+// restarting the webhook.
+type Registry struct {
+	file *filewatch.File[FileConfig]
+}
+
+func NewRegistry(config RegistryConfig) (*Registry, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	file, err := filewatch.New(filewatch.Config[FileConfig]{
+		Logger: config.Logger,
+		File:   config.File,
+		Name:   "quotas",
+		Parse:  parse,
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r := &Registry{
+		file: file,
+	}
+
+	return r, nil
+}
+
+// Quota returns the quota of the given organization. Limits set for the
+// organization override the default ones.
+func (r *Registry) Quota(organization string) Quota {
+	if r == nil {
+		return Quota{}
+	}
+
+	config := r.file.Value()
+
+	q := config.Default
+	if o, ok := config.Organizations[organization]; ok {
+		if o.MaxNodes != nil {
+			q.MaxNodes = o.MaxNodes
+		}
+		if o.MaxCPUs != nil {
+			q.MaxCPUs = o.MaxCPUs
+		}
+	}
+
+	return q
+}
+
+// Reload reads the quota file again. An invalid quota file is rejected as a
+// whole, and the previously loaded quotas are kept.
+func (r *Registry) Reload() error {
+	return microerror.Mask(r.file.Reload())
+}
+
+// Watch reloads the quota file at the given interval until the context is
+// done.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	r.file.Watch(ctx, interval)
+}
+
+func parse(content []byte) (FileConfig, error) {
+	var fileConfig FileConfig
+	err := yaml.UnmarshalStrict(content, &fileConfig)
+	if err != nil {
+		return FileConfig{}, microerror.Maskf(invalidQuotaError, "%s", err)
+	}
+
+	err = validate("default", fileConfig.Default)
+	if err != nil {
+		return FileConfig{}, microerror.Mask(err)
+	}
+	for organization, q := range fileConfig.Organizations {
+		err = validate(fmt.Sprintf("organization %s", organization), q)
+		if err != nil {
+			return FileConfig{}, microerror.Mask(err)
+		}
+	}
+
+	return fileConfig, nil
+}
+
+func validate(name string, q Quota) error {
+	if q.MaxNodes != nil && *q.MaxNodes < 0 {
+		return microerror.Maskf(invalidQuotaError, "maxNodes of the %s quota must not be negative", name)
+	}
+	if q.MaxCPUs != nil && *q.MaxCPUs < 0 {
+		return microerror.Maskf(invalidQuotaError, "maxCPUs of the %s quota must not be negative", name)
+	}
+
+	return nil
+}
@@ -2,10 +2,12 @@ package releaseversion
 
 import (
 	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
 var releaseNotFoundError = &microerror.Error{
-	Kind: "downgradingIsNotAllowedError",
+	Kind: "releaseNotFoundError",
 }
 
 // IsReleaseNotFoundError asserts releaseNotFoundError.
@@ -39,3 +41,60 @@ var skippingReleaseError = &microerror.Error{
 func IsSkippingReleaseError(err error) bool {
 	return microerror.Cause(err) == skippingReleaseError
 }
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var invalidPolicyError = &microerror.Error{
+	Kind: "invalidPolicyError",
+}
+
+// IsInvalidPolicy asserts invalidPolicyError.
+func IsInvalidPolicy(err error) bool {
+	return microerror.Cause(err) == invalidPolicyError
+}
+
+var belowMinimumReleaseError = &microerror.Error{
+	Kind: "belowMinimumReleaseError",
+}
+
+// IsBelowMinimumReleaseError asserts belowMinimumReleaseError.
+func IsBelowMinimumReleaseError(err error) bool {
+	return microerror.Cause(err) == belowMinimumReleaseError
+}
+
+var deprecatedReleaseError = &microerror.Error{
+	Kind: "deprecatedReleaseError",
+}
+
+// IsDeprecatedReleaseError asserts deprecatedReleaseError.
+func IsDeprecatedReleaseError(err error) bool {
+	return microerror.Cause(err) == deprecatedReleaseError
+}
+
+var patchSkippedError = &microerror.Error{
+	Kind: "patchSkippedError",
+}
+
+// IsPatchSkippedError asserts patchSkippedError.
+func IsPatchSkippedError(err error) bool {
+	return microerror.Cause(err) == patchSkippedError
+}
+
+func init() {
+	errors.RegisterValidationErrors(
+		releaseNotFoundError,
+		downgradingIsNotAllowedError,
+		upgradingToOrFromAlphaReleaseError,
+		skippingReleaseError,
+		belowMinimumReleaseError,
+		deprecatedReleaseError,
+		patchSkippedError,
+	)
+}
@@ -0,0 +1,120 @@
+package releaseversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/blang/semver"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpgradePathPattern is the path the UpgradePathHandler is served at.
+const UpgradePathPattern = "/releases/upgrade-path"
+
+type UpgradePathHandlerConfig struct {
+	CtrlClient client.Client
+	Logger     micrologger.Logger
+
+	// Policy is the release policy of the installation. It is optional, when
+	// nil any patch release can be upgraded.
+	Policy *PolicyRegistry
+}
+
+// UpgradePathHandler serves the upgrade path between the releases given in
+// the from and to query parameters as JSON, see PlanUpgradePath.
+//
+// Example:
+//
+//	GET /releases/upgrade-path?from=14.0.0&to=15.0.0
+//
+//	{"from":"14.0.0","to":"15.0.0","steps":["14.1.4","15.0.0"]}
+type UpgradePathHandler struct {
+	ctrlClient client.Client
+	logger     micrologger.Logger
+	policy     *PolicyRegistry
+}
+
+type upgradePathError struct {
+	Error string `json:"error"`
+}
+
+func NewUpgradePathHandler(config UpgradePathHandlerConfig) (*UpgradePathHandler, error) {
+	if config.CtrlClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CtrlClient must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	h := &UpgradePathHandler{
+		ctrlClient: config.CtrlClient,
+		logger:     config.Logger,
+		policy:     config.Policy,
+	}
+
+	return h, nil
+}
+
+func (h *UpgradePathHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		h.writeError(writer, http.StatusMethodNotAllowed, fmt.Sprintf("method %s is not allowed", request.Method))
+		return
+	}
+
+	from, err := parseVersionParameter(request, "from")
+	if err != nil {
+		h.writeError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+	to, err := parseVersionParameter(request, "to")
+	if err != nil {
+		h.writeError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	path, err := PlanUpgradePath(request.Context(), h.ctrlClient, h.policy.Policy(), from, to)
+	if IsReleaseNotFoundError(err) {
+		h.writeError(writer, http.StatusNotFound, err.Error())
+		return
+	} else if IsDowngradingIsNotAllowedError(err) || IsUpgradingToOrFromAlphaReleaseError(err) {
+		h.writeError(writer, http.StatusUnprocessableEntity, err.Error())
+		return
+	} else if err != nil {
+		h.logger.Log("level", "error", "message", "unable to plan the upgrade path", "stack", microerror.JSON(err))
+		h.writeError(writer, http.StatusInternalServerError, "unable to plan the upgrade path")
+		return
+	}
+
+	h.write(writer, http.StatusOK, path)
+}
+
+func (h *UpgradePathHandler) writeError(writer http.ResponseWriter, status int, message string) {
+	h.write(writer, status, upgradePathError{Error: message})
+}
+
+func (h *UpgradePathHandler) write(writer http.ResponseWriter, status int, body interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	err := json.NewEncoder(writer).Encode(body)
+	if err != nil {
+		h.logger.Log("level", "error", "message", "unable to write the upgrade path response", "stack", microerror.JSON(err))
+	}
+}
+
+func parseVersionParameter(request *http.Request, name string) (semver.Version, error) {
+	value := request.URL.Query().Get(name)
+	if value == "" {
+		return semver.Version{}, fmt.Errorf("query parameter %q must not be empty", name)
+	}
+
+	version, err := semver.ParseTolerant(value)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("query parameter %q is not a valid release version: %s", name, err.Error())
+	}
+
+	return version, nil
+}
@@ -0,0 +1,99 @@
+package releaseversion
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/micrologger"
+)
+
+func TestUpgradePathHandler(t *testing.T) {
+	testCases := []struct {
+		name           string
+		policy         string
+		method         string
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "case 0: upgrade path",
+			method:         http.MethodGet,
+			query:          "from=13.1.0&to=v15.0.0",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"from":"13.1.0","to":"15.0.0","steps":["14.0.1","14.1.4","15.0.0"]}`,
+		},
+		{
+			name:           "case 1: upgrade path with the latest patch policy",
+			policy:         "patchPolicy: latest",
+			method:         http.MethodGet,
+			query:          "from=14.0.0&to=15.0.0",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"from":"14.0.0","to":"15.0.0","steps":["14.0.1","14.1.4","15.0.0"]}`,
+		},
+		{
+			name:           "case 2: missing parameter",
+			method:         http.MethodGet,
+			query:          "from=13.1.0",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"query parameter \"to\" must not be empty"}`,
+		},
+		{
+			name:           "case 3: invalid version",
+			method:         http.MethodGet,
+			query:          "from=latest&to=15.0.0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "case 4: unknown release",
+			method:         http.MethodGet,
+			query:          "from=13.1.0&to=16.0.0",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "case 5: downgrade",
+			method:         http.MethodGet,
+			query:          "from=15.0.0&to=14.0.0",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "case 6: not a GET request",
+			method:         http.MethodPost,
+			query:          "from=13.1.0&to=15.0.0",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	logger, err := micrologger.New(micrologger.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			handler, err := NewUpgradePathHandler(UpgradePathHandlerConfig{
+				CtrlClient: newFakeClient(t, testReleases),
+				Logger:     logger,
+				Policy:     newTestPolicyRegistry(t, tc.policy),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(tc.method, UpgradePathPattern+"?"+tc.query, nil))
+
+			if recorder.Code != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tc.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			if tc.expectedBody != "" && strings.TrimSpace(recorder.Body.String()) != tc.expectedBody {
+				t.Fatalf("expected body %s, got %s", tc.expectedBody, recorder.Body.String())
+			}
+		})
+	}
+}
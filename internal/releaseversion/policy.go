@@ -0,0 +1,133 @@
+package releaseversion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blang/semver"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+)
+
+// PatchPolicy is the rule for the patch release clusters must be on before
+// they are upgraded to another major or minor release.
+type PatchPolicy string
+
+const (
+	// PatchPolicyAny allows upgrading from any patch release. It is the
+	// default.
+	PatchPolicyAny PatchPolicy = "any"
+	// PatchPolicyLatest only allows upgrading to another major or minor
+	// release from the latest patch release of the current one.
+	PatchPolicyLatest PatchPolicy = "latest"
+)
+
+// Policy is the release policy of the installation, see PolicyFileConfig.
+type Policy struct {
+	// MinimumRelease is the oldest supported major and minor release. Its
+	// patch is always zero. It is nil when every release is supported.
+	MinimumRelease *semver.Version
+	PatchPolicy    PatchPolicy
+}
+
+// ValidatePolicyOnCreate checks the release of a new cluster. The release
+// must not be below the minimum supported release, and must not be
+// deprecated. Ignored releases are not checked against the minimum.
+func ValidatePolicyOnCreate(ctx context.Context, ctrlClient client.Client, policy Policy, version semver.Version) error {
+	availableReleases, err := availableReleases(ctx, ctrlClient)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, release := range availableReleases {
+		if release.Version.EQ(version) && isDeprecatedRelease(release.CR) {
+			err = microerror.Maskf(deprecatedReleaseError, "Release %s is deprecated, new clusters must use an active release", version)
+			err = enforcement.Apply(ctx, enforcement.ReleaseVersionDeprecated, err)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !isOldOrNewReleaseIgnored(availableReleases, version, version) && policy.isBelowMinimum(version) {
+		err = microerror.Maskf(belowMinimumReleaseError, "Release %s is below the minimum supported release %d.%d", version, policy.MinimumRelease.Major, policy.MinimumRelease.Minor)
+		err = enforcement.Apply(ctx, enforcement.ReleaseVersionMinimum, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidatePolicyOnUpgrade checks an upgrade from one release to another. The
+// new release must not be below the minimum supported release, and with
+// PatchPolicyLatest, upgrades to another major or minor release must start
+// from the latest patch release. Like in Validate, alpha, ignored and
+// deprecated releases don't count as latest patch release, and upgrades from
+// or to ignored releases are not checked.
+func ValidatePolicyOnUpgrade(ctx context.Context, ctrlClient client.Client, policy Policy, oldVersion semver.Version, newVersion semver.Version) error {
+	if oldVersion.Equals(newVersion) {
+		return nil
+	}
+
+	availableReleases, err := availableReleases(ctx, ctrlClient)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if isOldOrNewReleaseIgnored(availableReleases, oldVersion, newVersion) {
+		return nil
+	}
+
+	if policy.isBelowMinimum(newVersion) {
+		err = microerror.Maskf(belowMinimumReleaseError, "Upgrading from %s to %s is not allowed, the minimum supported release is %d.%d", oldVersion, newVersion, policy.MinimumRelease.Major, policy.MinimumRelease.Minor)
+		err = enforcement.Apply(ctx, enforcement.ReleaseVersionMinimum, err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if policy.PatchPolicy == PatchPolicyLatest && !sameMinor(oldVersion, newVersion) && newVersion.GT(oldVersion) {
+		latest := latestPatch(filterOutAlphaAndIgnoredAndDeprecatedReleases(availableReleases), oldVersion)
+		if latest != nil && latest.GT(oldVersion) {
+			err = microerror.Maskf(patchSkippedError, "Upgrading from %s to %s is not allowed, upgrade to the latest patch release %s of %d.%d first", oldVersion, newVersion, latest, oldVersion.Major, oldVersion.Minor)
+			err = enforcement.Apply(ctx, enforcement.ReleaseVersionPatch, err)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// MinimumReleaseWarnings returns a warning when the given release is below the
+// minimum supported release. Existing clusters on such a release are not
+// denied, so that they can still be changed and upgraded.
+func MinimumReleaseWarnings(policy Policy, version semver.Version) []string {
+	if !policy.isBelowMinimum(version) {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("Release %s is below the minimum supported release %d.%d, please upgrade.", version, policy.MinimumRelease.Major, policy.MinimumRelease.Minor)}
+}
+
+func (p Policy) isBelowMinimum(version semver.Version) bool {
+	return p.MinimumRelease != nil && minorOf(version).LT(*p.MinimumRelease)
+}
+
+// latestPatch returns the latest of the given releases with the same major
+// and minor as the given version, or nil when there is none.
+func latestPatch(releases []*release, version semver.Version) *semver.Version {
+	var latest *semver.Version
+	for _, r := range releases {
+		if sameMinor(*r.Version, version) && (latest == nil || r.Version.GT(*latest)) {
+			latest = r.Version
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,222 @@
+package releaseversion
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/blang/semver"
+
+	"github.com/giantswarm/azure-admission-controller/internal/filewatch"
+)
+
+func TestValidatePolicyOnCreate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		policy       string
+		version      string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:    "case 0: active release without policy",
+			version: "14.1.4",
+		},
+		{
+			name:         "case 1: deprecated release without policy",
+			version:      "14.2.0",
+			errorMatcher: IsDeprecatedReleaseError,
+		},
+		{
+			name:    "case 2: unknown releases are left to other checks",
+			version: "16.0.0",
+		},
+		{
+			name:         "case 3: release below the minimum minor",
+			policy:       "minimumRelease: \"14.1\"\n",
+			version:      "14.0.1",
+			errorMatcher: IsBelowMinimumReleaseError,
+		},
+		{
+			name:    "case 4: any patch of the minimum minor",
+			policy:  "minimumRelease: \"14.1\"\n",
+			version: "14.1.0",
+		},
+		{
+			name:         "case 5: release below the minimum major",
+			policy:       "minimumRelease: \"15\"\n",
+			version:      "14.1.4",
+			errorMatcher: IsBelowMinimumReleaseError,
+		},
+		{
+			name:    "case 6: ignored release below the minimum",
+			policy:  "minimumRelease: \"15.2\"\n",
+			version: "15.1.0",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			policy := newTestPolicyRegistry(t, tc.policy).Policy()
+			err := ValidatePolicyOnCreate(context.Background(), newFakeClient(t, testReleases), policy, semver.MustParse(tc.version))
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
+
+func TestValidatePolicyOnUpgrade(t *testing.T) {
+	testCases := []struct {
+		name         string
+		policy       string
+		from         string
+		to           string
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: next minor from an older patch without policy",
+			from: "14.0.0",
+			to:   "14.1.4",
+		},
+		{
+			name:         "case 1: next minor from an older patch with the latest patch policy",
+			policy:       "patchPolicy: latest\n",
+			from:         "14.0.0",
+			to:           "14.1.4",
+			errorMatcher: IsPatchSkippedError,
+		},
+		{
+			name:   "case 2: next minor from the latest patch",
+			policy: "patchPolicy: latest\n",
+			from:   "14.0.1",
+			to:     "14.1.0",
+		},
+		{
+			name:   "case 3: patch upgrades may skip patch releases",
+			policy: "patchPolicy: latest\n",
+			from:   "14.1.0",
+			to:     "14.1.4",
+		},
+		{
+			name:   "case 4: deprecated releases don't count as latest patch",
+			policy: "patchPolicy: latest\n",
+			from:   "14.1.4",
+			to:     "15.0.0",
+		},
+		{
+			name:   "case 5: upgrades to ignored releases are not checked",
+			policy: "patchPolicy: latest\n",
+			from:   "14.0.0",
+			to:     "15.1.0",
+		},
+		{
+			name:         "case 6: upgrade to a release below the minimum",
+			policy:       "minimumRelease: \"14.1\"\n",
+			from:         "13.1.0",
+			to:           "14.0.1",
+			errorMatcher: IsBelowMinimumReleaseError,
+		},
+		{
+			name:   "case 7: upgrade from a release below the minimum",
+			policy: "minimumRelease: \"14.1\"\n",
+			from:   "14.0.1",
+			to:     "14.1.0",
+		},
+		{
+			name:   "case 8: unchanged release below the minimum",
+			policy: "minimumRelease: \"14.1\"\npatchPolicy: latest\n",
+			from:   "13.0.0",
+			to:     "13.0.0",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			policy := newTestPolicyRegistry(t, tc.policy).Policy()
+			err := ValidatePolicyOnUpgrade(context.Background(), newFakeClient(t, testReleases), policy, semver.MustParse(tc.from), semver.MustParse(tc.to))
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
+
+func TestMinimumReleaseWarnings(t *testing.T) {
+	policy := newTestPolicyRegistry(t, "minimumRelease: \"14.1\"\n").Policy()
+
+	if warnings := MinimumReleaseWarnings(policy, semver.MustParse("14.0.1")); len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %v", warnings)
+	}
+	if warnings := MinimumReleaseWarnings(policy, semver.MustParse("14.1.0")); len(warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v", warnings)
+	}
+
+	var registry *PolicyRegistry
+	if warnings := MinimumReleaseWarnings(registry.Policy(), semver.MustParse("1.0.0")); len(warnings) != 0 {
+		t.Fatalf("expected no warnings without registry, got %v", warnings)
+	}
+}
+
+func TestParsePolicyInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy string
+	}{
+		{
+			name:   "case 0: unknown rule",
+			policy: "maximumRelease: \"15\"\n",
+		},
+		{
+			name:   "case 1: invalid minimum release",
+			policy: "minimumRelease: fourteen\n",
+		},
+		{
+			name:   "case 2: minimum patch release",
+			policy: "minimumRelease: 14.1.2\n",
+		},
+		{
+			name:   "case 3: unknown patch policy",
+			policy: "patchPolicy: sequential\n",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			_, err := parsePolicy([]byte(tc.policy))
+			if !IsInvalidPolicy(err) {
+				t.Fatalf("expected invalid policy error, got %#v", err)
+			}
+		})
+	}
+}
+
+func newTestPolicyRegistry(t *testing.T, policy string) *PolicyRegistry {
+	parsed, err := parsePolicy([]byte(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &PolicyRegistry{file: filewatch.Static(parsed)}
+}
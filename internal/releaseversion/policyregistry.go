@@ -0,0 +1,118 @@
+package releaseversion
+
+import (
+	"context"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/azure-admission-controller/internal/filewatch"
+)
+
+// PolicyFileConfig is the content of the release policy file.
+//
+// Example:
+//
+//	minimumRelease: "14.1"
+//	patchPolicy: latest
+type PolicyFileConfig struct {
+	// MinimumRelease is the oldest supported major or major.minor release,
+	// e.g. "14" or "14.1".
+	MinimumRelease string `json:"minimumRelease,omitempty"`
+	// PatchPolicy is either "any" or "latest", see PatchPolicy.
+	PatchPolicy PatchPolicy `json:"patchPolicy,omitempty"`
+}
+
+type PolicyRegistryConfig struct {
+	Logger micrologger.Logger
+
+	// File is the path of the release policy file. It is optional, when empty
+	// every release is supported and any patch release can be upgraded.
+	File string
+}
+
+// PolicyRegistry holds the release policy of the installation. It is read
+// from the policy file, which is reloaded by Watch, so that it can be changed
+// without restarting the webhook.
+type PolicyRegistry struct {
+	file *filewatch.File[Policy]
+}
+
+func NewPolicyRegistry(config PolicyRegistryConfig) (*PolicyRegistry, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	file, err := filewatch.New(filewatch.Config[Policy]{
+		Logger:  config.Logger,
+		File:    config.File,
+		Name:    "release policy",
+		Default: Policy{PatchPolicy: PatchPolicyAny},
+		Parse:   parsePolicy,
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r := &PolicyRegistry{
+		file: file,
+	}
+
+	return r, nil
+}
+
+// Policy returns the current release policy. Without registry, every release
+// is supported and any patch release can be upgraded.
+func (r *PolicyRegistry) Policy() Policy {
+	if r == nil {
+		return Policy{PatchPolicy: PatchPolicyAny}
+	}
+
+	return r.file.Value()
+}
+
+// Reload reads the policy file again. An invalid policy file is rejected, and
+// the previously loaded policy is kept.
+func (r *PolicyRegistry) Reload() error {
+	return microerror.Mask(r.file.Reload())
+}
+
+// Watch reloads the policy file at the given interval until the context is
+// done.
+func (r *PolicyRegistry) Watch(ctx context.Context, interval time.Duration) {
+	r.file.Watch(ctx, interval)
+}
+
+func parsePolicy(content []byte) (Policy, error) {
+	var fileConfig PolicyFileConfig
+	err := yaml.UnmarshalStrict(content, &fileConfig)
+	if err != nil {
+		return Policy{}, microerror.Maskf(invalidPolicyError, "%s", err)
+	}
+
+	policy := Policy{PatchPolicy: PatchPolicyAny}
+
+	if fileConfig.MinimumRelease != "" {
+		minimum, err := semver.ParseTolerant(fileConfig.MinimumRelease)
+		if err != nil {
+			return Policy{}, microerror.Maskf(invalidPolicyError, "minimumRelease %#q is not a valid release: %s", fileConfig.MinimumRelease, err.Error())
+		}
+		if minimum.Patch != 0 || len(minimum.Pre) > 0 || len(minimum.Build) > 0 {
+			return Policy{}, microerror.Maskf(invalidPolicyError, "minimumRelease %#q must be a major or major.minor release", fileConfig.MinimumRelease)
+		}
+		policy.MinimumRelease = &minimum
+	}
+
+	switch fileConfig.PatchPolicy {
+	case "":
+	case PatchPolicyAny, PatchPolicyLatest:
+		policy.PatchPolicy = fileConfig.PatchPolicy
+	default:
+		return Policy{}, microerror.Maskf(invalidPolicyError, "patchPolicy %#q is not supported, expected one of %v", fileConfig.PatchPolicy, []PatchPolicy{PatchPolicyAny, PatchPolicyLatest})
+	}
+
+	return policy, nil
+}
@@ -2,6 +2,7 @@ package releaseversion
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/blang/semver"
@@ -9,6 +10,7 @@ import (
 	"github.com/giantswarm/release-operator/v3/api/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
@@ -16,7 +18,7 @@ const (
 	ignoreReleaseAnnotation = "release.giantswarm.io/ignore"
 )
 
-func Validate(ctx context.Context, ctrlCLient client.Client, oldVersion semver.Version, newVersion semver.Version) error {
+func Validate(ctx context.Context, ctrlCLient client.Client, policy Policy, oldVersion semver.Version, newVersion semver.Version) error {
 	if oldVersion.Equals(newVersion) {
 		return nil
 	}
@@ -38,12 +40,20 @@ func Validate(ctx context.Context, ctrlCLient client.Client, oldVersion semver.V
 
 	// Downgrades are not allowed.
 	if newVersion.LT(oldVersion) {
-		return microerror.Maskf(downgradingIsNotAllowedError, "downgrading is not allowed (attempted to downgrade from %s to %s)", oldVersion, newVersion)
+		err = microerror.Maskf(downgradingIsNotAllowedError, "downgrading is not allowed (attempted to downgrade from %s to %s)", oldVersion, newVersion)
+		err = enforcement.Apply(ctx, enforcement.ReleaseVersionDowngrade, err)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Check if either version is an alpha one.
 	if isAlphaRelease(oldVersion.String()) || isAlphaRelease(newVersion.String()) {
-		return microerror.Maskf(upgradingToOrFromAlphaReleaseError, "It is not possible to upgrade to or from an alpha release")
+		err = microerror.Maskf(upgradingToOrFromAlphaReleaseError, "It is not possible to upgrade to or from an alpha release")
+		err = enforcement.Apply(ctx, enforcement.ReleaseVersionAlpha, err)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Remove alpha and ignored releases from remaining validations logic.
@@ -60,7 +70,9 @@ func Validate(ctx context.Context, ctrlCLient client.Client, oldVersion semver.V
 				(oldVersion.Major != release.Version.Major || oldVersion.Minor != release.Version.Minor) &&
 				(newVersion.Major != release.Version.Major || newVersion.Minor != release.Version.Minor) {
 				// Skipped one major or minor release.
-				return microerror.Maskf(skippingReleaseError, "Upgrading from %s to %s is not allowed (skipped %s)", oldVersion, newVersion, release.Version)
+				path := planUpgradePath(availableReleases, policy, oldVersion, newVersion)
+				err = microerror.Maskf(skippingReleaseError, "Upgrading from %s to %s is not allowed (skipped %s), the upgrade path is %s", oldVersion, newVersion, release.Version, path)
+				return enforcement.Apply(ctx, enforcement.ReleaseVersionSkip, err)
 			}
 		}
 	}
@@ -68,6 +80,24 @@ func Validate(ctx context.Context, ctrlCLient client.Client, oldVersion semver.V
 	return nil
 }
 
+// DeprecationWarnings returns a warning when the given release exists in the
+// installation and is deprecated. Using a deprecated release is allowed, but
+// the user should upgrade to a supported release soon.
+func DeprecationWarnings(ctx context.Context, ctrlClient client.Client, version semver.Version) ([]string, error) {
+	availableReleases, err := availableReleases(ctx, ctrlClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	for _, release := range availableReleases {
+		if release.Version.EQ(version) && isDeprecatedRelease(release.CR) {
+			return []string{fmt.Sprintf("Release %s is deprecated, please use a newer release.", version)}, nil
+		}
+	}
+
+	return nil, nil
+}
+
 func availableReleases(ctx context.Context, ctrlClient client.Client) ([]*release, error) {
 	var releases []*release
 	releaseList := &v1alpha1.ReleaseList{}
@@ -0,0 +1,122 @@
+package releaseversion
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UpgradePath is the sequence of releases a cluster has to be upgraded
+// through to get from one release to another, upgrading to one major or minor
+// release at a time, see Validate.
+type UpgradePath struct {
+	From semver.Version `json:"from"`
+	To   semver.Version `json:"to"`
+	// Steps are the releases to upgrade to one after the other. The last one
+	// is To. It is empty when From and To are the same.
+	Steps []semver.Version `json:"steps"`
+}
+
+// String returns the path in the form "14.0.0 -> 14.1.4 -> 15.0.0".
+func (p UpgradePath) String() string {
+	versions := []string{p.From.String()}
+	for _, step := range p.Steps {
+		versions = append(versions, step.String())
+	}
+
+	return strings.Join(versions, " -> ")
+}
+
+// PlanUpgradePath returns the upgrade path from one release to another. Like
+// in Validate, alpha, ignored and deprecated releases are not part of the
+// path, and upgrades from or to ignored releases are done in a single step.
+// Each intermediate step is the latest release of the next major or minor
+// release. With PatchPolicyLatest, the path starts with the latest patch
+// release of the current major and minor release, see ValidatePolicyOnUpgrade.
+func PlanUpgradePath(ctx context.Context, ctrlClient client.Client, policy Policy, from semver.Version, to semver.Version) (*UpgradePath, error) {
+	if from.Equals(to) {
+		return &UpgradePath{From: from, To: to, Steps: []semver.Version{}}, nil
+	}
+
+	availableReleases, err := availableReleases(ctx, ctrlClient)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if !included(availableReleases, to) {
+		return nil, microerror.Maskf(releaseNotFoundError, "release %s was not found in this installation", to)
+	}
+
+	if isOldOrNewReleaseIgnored(availableReleases, from, to) {
+		return &UpgradePath{From: from, To: to, Steps: []semver.Version{to}}, nil
+	}
+
+	if to.LT(from) {
+		return nil, microerror.Maskf(downgradingIsNotAllowedError, "downgrading is not allowed (attempted to downgrade from %s to %s)", from, to)
+	}
+
+	if isAlphaRelease(from.String()) || isAlphaRelease(to.String()) {
+		return nil, microerror.Maskf(upgradingToOrFromAlphaReleaseError, "It is not possible to upgrade to or from an alpha release")
+	}
+
+	return planUpgradePath(filterOutAlphaAndIgnoredAndDeprecatedReleases(availableReleases), policy, from, to), nil
+}
+
+// planUpgradePath returns the upgrade path through the given releases, which
+// must be filtered already. It goes through the latest release of every major
+// or minor release between from and to, and with PatchPolicyLatest through
+// the latest patch release of from before leaving its major or minor release.
+func planUpgradePath(releases []*release, policy Policy, from semver.Version, to semver.Version) *UpgradePath {
+	path := &UpgradePath{From: from, To: to, Steps: []semver.Version{}}
+
+	current := from
+	if policy.PatchPolicy == PatchPolicyLatest && !sameMinor(from, to) {
+		latest := latestPatch(releases, from)
+		if latest != nil && latest.GT(from) {
+			path.Steps = append(path.Steps, *latest)
+			current = *latest
+		}
+	}
+
+	for {
+		// The next step is the latest release of the lowest major or minor
+		// release between the current step and the target.
+		var next *semver.Version
+		for _, r := range releases {
+			if !r.Version.GT(current) || !r.Version.LT(to) || sameMinor(*r.Version, current) || sameMinor(*r.Version, to) {
+				continue
+			}
+
+			switch {
+			case next == nil:
+				next = r.Version
+			case minorOf(*r.Version).LT(minorOf(*next)):
+				next = r.Version
+			case sameMinor(*r.Version, *next) && r.Version.GT(*next):
+				next = r.Version
+			}
+		}
+
+		if next == nil {
+			break
+		}
+
+		path.Steps = append(path.Steps, *next)
+		current = *next
+	}
+
+	path.Steps = append(path.Steps, to)
+
+	return path
+}
+
+func minorOf(v semver.Version) semver.Version {
+	return semver.Version{Major: v.Major, Minor: v.Minor}
+}
+
+func sameMinor(a semver.Version, b semver.Version) bool {
+	return a.Major == b.Major && a.Minor == b.Minor
+}
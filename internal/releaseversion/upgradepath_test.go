@@ -0,0 +1,210 @@
+package releaseversion
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/giantswarm/release-operator/v3/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+type testRelease struct {
+	version string
+	ignored bool
+	state   v1alpha1.ReleaseState
+}
+
+var testReleases = []testRelease{
+	{version: "13.0.0", state: v1alpha1.StateActive},
+	{version: "13.1.0", state: v1alpha1.StateActive},
+	{version: "14.0.0", state: v1alpha1.StateActive},
+	{version: "14.0.1", state: v1alpha1.StateActive},
+	{version: "14.1.0", state: v1alpha1.StateActive},
+	{version: "14.1.4", state: v1alpha1.StateActive},
+	{version: "14.2.0", state: v1alpha1.StateDeprecated},
+	{version: "15.0.0-alpha1", state: v1alpha1.StateActive},
+	{version: "15.0.0", state: v1alpha1.StateActive},
+	{version: "15.1.0", ignored: true, state: v1alpha1.StateActive},
+	{version: "15.2.0", state: v1alpha1.StateActive},
+}
+
+func TestPlanUpgradePath(t *testing.T) {
+	testCases := []struct {
+		name         string
+		policy       Policy
+		from         string
+		to           string
+		expected     string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:     "case 0: same release",
+			from:     "14.0.0",
+			to:       "14.0.0",
+			expected: "14.0.0",
+		},
+		{
+			name:     "case 1: patch upgrade",
+			from:     "14.0.0",
+			to:       "14.0.1",
+			expected: "14.0.0 -> 14.0.1",
+		},
+		{
+			name:     "case 2: next minor release",
+			from:     "14.0.1",
+			to:       "14.1.0",
+			expected: "14.0.1 -> 14.1.0",
+		},
+		{
+			name:     "case 3: skipped minor releases go through their latest patch, deprecated ones are left out",
+			from:     "13.0.0",
+			to:       "15.0.0",
+			expected: "13.0.0 -> 13.1.0 -> 14.0.1 -> 14.1.4 -> 15.0.0",
+		},
+		{
+			name:     "case 4: ignored releases are left out",
+			from:     "15.0.0",
+			to:       "15.2.0",
+			expected: "15.0.0 -> 15.2.0",
+		},
+		{
+			name:     "case 5: upgrades to ignored releases are done in one step",
+			from:     "13.0.0",
+			to:       "15.1.0",
+			expected: "13.0.0 -> 15.1.0",
+		},
+		{
+			name:     "case 6: latest patch policy goes through the latest patch release of the current minor release",
+			policy:   Policy{PatchPolicy: PatchPolicyLatest},
+			from:     "14.0.0",
+			to:       "15.0.0",
+			expected: "14.0.0 -> 14.0.1 -> 14.1.4 -> 15.0.0",
+		},
+		{
+			name:     "case 7: latest patch policy on the latest patch release",
+			policy:   Policy{PatchPolicy: PatchPolicyLatest},
+			from:     "14.0.1",
+			to:       "14.1.0",
+			expected: "14.0.1 -> 14.1.0",
+		},
+		{
+			name:     "case 8: latest patch policy for patch upgrades",
+			policy:   Policy{PatchPolicy: PatchPolicyLatest},
+			from:     "14.1.0",
+			to:       "14.1.4",
+			expected: "14.1.0 -> 14.1.4",
+		},
+		{
+			name:         "case 9: unknown target release",
+			from:         "14.0.0",
+			to:           "16.0.0",
+			errorMatcher: IsReleaseNotFoundError,
+		},
+		{
+			name:         "case 10: downgrade",
+			from:         "14.1.0",
+			to:           "14.0.0",
+			errorMatcher: IsDowngradingIsNotAllowedError,
+		},
+		{
+			name:         "case 11: alpha release",
+			from:         "14.1.4",
+			to:           "15.0.0-alpha1",
+			errorMatcher: IsUpgradingToOrFromAlphaReleaseError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			path, err := PlanUpgradePath(context.Background(), newFakeClient(t, testReleases), tc.policy, semver.MustParse(tc.from), semver.MustParse(tc.to))
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			if tc.errorMatcher == nil && path.String() != tc.expected {
+				t.Fatalf("expected upgrade path %q, got %q", tc.expected, path.String())
+			}
+		})
+	}
+}
+
+func TestValidateExplainsUpgradePath(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   Policy
+		from     string
+		to       string
+		expected string
+	}{
+		{
+			name:     "case 0: any patch policy",
+			from:     "13.0.0",
+			to:       "14.1.0",
+			expected: "Upgrading from 13.0.0 to 14.1.0 is not allowed (skipped 13.1.0), the upgrade path is 13.0.0 -> 13.1.0 -> 14.0.1 -> 14.1.0",
+		},
+		{
+			name:     "case 1: latest patch policy",
+			policy:   Policy{PatchPolicy: PatchPolicyLatest},
+			from:     "14.0.0",
+			to:       "15.0.0",
+			expected: "Upgrading from 14.0.0 to 15.0.0 is not allowed (skipped 14.1.0), the upgrade path is 14.0.0 -> 14.0.1 -> 14.1.4 -> 15.0.0",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := Validate(context.Background(), newFakeClient(t, testReleases), tc.policy, semver.MustParse(tc.from), semver.MustParse(tc.to))
+			if !IsSkippingReleaseError(err) {
+				t.Fatalf("expected skipping release error, got %#v", err)
+			}
+
+			if !strings.HasSuffix(err.Error(), tc.expected) {
+				t.Fatalf("expected error ending with %q, got %q", tc.expected, err.Error())
+			}
+		})
+	}
+}
+
+func newFakeClient(t *testing.T, releases []testRelease) client.Client {
+	ctrlClient := unittest.FakeK8sClient().CtrlClient()
+
+	for _, r := range releases {
+		release := &v1alpha1.Release{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("v%s", r.version),
+				Annotations: map[string]string{
+					ignoreReleaseAnnotation: strconv.FormatBool(r.ignored),
+				},
+			},
+			Spec: v1alpha1.ReleaseSpec{
+				State: r.state,
+			},
+		}
+
+		err := ctrlClient.Create(context.Background(), release)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return ctrlClient
+}
@@ -1,7 +1,32 @@
 package scheduledupgrades
 
-import "github.com/giantswarm/microerror"
+import (
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+)
 
 var notAllowedError = &microerror.Error{
 	Kind: "notAllowedError",
 }
+
+// IsNotAllowed asserts notAllowedError.
+func IsNotAllowed(err error) bool {
+	return microerror.Cause(err) == notAllowedError
+}
+
+var invalidTimeError = &microerror.Error{
+	Kind: "invalidTimeError",
+}
+
+// IsInvalidTime asserts invalidTimeError.
+func IsInvalidTime(err error) bool {
+	return microerror.Cause(err) == invalidTimeError
+}
+
+func init() {
+	errors.RegisterValidationErrors(
+		notAllowedError,
+		invalidTimeError,
+	)
+}
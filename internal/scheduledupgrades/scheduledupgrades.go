@@ -12,9 +12,22 @@ import (
 	"github.com/giantswarm/apiextensions/v6/pkg/annotation"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 
+	"github.com/giantswarm/azure-admission-controller/internal/conditions"
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/maintenance"
 	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
 	"github.com/giantswarm/azure-admission-controller/internal/semverhelper"
+	"github.com/giantswarm/azure-admission-controller/pkg/release"
+)
+
+const (
+	// businessHoursStart and businessHoursEnd define the hours (UTC) of a
+	// working day in which scheduled upgrades don't cause a warning, when no
+	// maintenance windows are configured.
+	businessHoursStart = 8
+	businessHoursEnd   = 18
+
+	azureOperatorComponentName = "azure-operator"
 )
 
 func ValidateClusterAnnotationUpgradeTime(oldCluster *capi.Cluster, newCluster *capi.Cluster) error {
@@ -26,39 +39,117 @@ func ValidateClusterAnnotationUpgradeTime(oldCluster *capi.Cluster, newCluster *
 				}
 			}
 		}
-		if !ValidateUpgradeScheduleTime(updateTime) {
+		err := validateUpgradeScheduleTime(updateTime, time.Now().UTC())
+		if err != nil {
 			return microerror.Maskf(notAllowedError,
-				fmt.Sprintf("Cluster annotation '%s' value '%s' is not valid. Value must be in RFC822 format and UTC time zone (e.g. 30 Jan 21 15:04 UTC) and should be a date 16 mins - 6months in the future.",
+				fmt.Sprintf("Cluster annotation '%s' value '%s' is not valid. %v",
 					annotation.UpdateScheduleTargetTime,
-					updateTime),
+					updateTime,
+					err),
 			)
 		}
 	}
 	return nil
 }
 
-func ValidateUpgradeScheduleTime(updateTime string) bool {
-	// parse time
-	t, err := time.Parse(time.RFC822, updateTime)
+// ValidateClusterAnnotationUpgradeTimeSchedule checks the scheduled upgrade
+// time is within a maintenance window and outside of the change freezes of the
+// given schedule, when it is set or changed.
+func ValidateClusterAnnotationUpgradeTimeSchedule(schedule maintenance.Schedule, oldCluster *capi.Cluster, newCluster *capi.Cluster) error {
+	updateTime, ok := newCluster.GetAnnotations()[annotation.UpdateScheduleTargetTime]
+	if !ok {
+		return nil
+	}
+
+	if oldCluster != nil {
+		if updateTimeOld, ok := oldCluster.GetAnnotations()[annotation.UpdateScheduleTargetTime]; ok && updateTime == updateTimeOld {
+			return nil
+		}
+	}
+
+	t, err := ParseUpgradeScheduleTime(updateTime)
 	if err != nil {
-		return false
+		// Invalid values are handled by ValidateClusterAnnotationUpgradeTime.
+		return nil
 	}
-	// check whether it is UTC
-	if t.Location().String() != "UTC" {
-		return false
+
+	err = schedule.CheckScheduledTime(t)
+	if err != nil {
+		return microerror.Maskf(notAllowedError,
+			fmt.Sprintf("Cluster annotation '%s' value '%s' is not valid. %v",
+				annotation.UpdateScheduleTargetTime,
+				updateTime,
+				err),
+		)
 	}
-	// time already passed or is less than 16 minutes in the future
-	if t.Before(time.Now().UTC().Add(16 * time.Minute)) {
-		return false
+
+	return nil
+}
+
+// WarnClusterAnnotationUpgradeTime returns a warning when the scheduled upgrade
+// time is set or changed to a time outside of business hours, when nobody may
+// be around to look after the upgrade. When the schedule has maintenance
+// windows, they define when upgrades may happen instead, and times outside of
+// them are reported by ValidateClusterAnnotationUpgradeTimeSchedule.
+func WarnClusterAnnotationUpgradeTime(schedule maintenance.Schedule, oldCluster *capi.Cluster, newCluster *capi.Cluster) []string {
+	if schedule.HasWindows() {
+		return nil
+	}
+
+	updateTime, ok := newCluster.GetAnnotations()[annotation.UpdateScheduleTargetTime]
+	if !ok {
+		return nil
 	}
-	// time is 6 months or more in the future (6 months are 4380 hours)
-	if t.Sub(time.Now().UTC()) > 4380*time.Hour {
+
+	if oldCluster != nil {
+		if updateTimeOld, ok := oldCluster.GetAnnotations()[annotation.UpdateScheduleTargetTime]; ok && updateTime == updateTimeOld {
+			return nil
+		}
+	}
+
+	t, err := ParseUpgradeScheduleTime(updateTime)
+	if err != nil {
+		// Invalid values are handled by ValidateClusterAnnotationUpgradeTime.
+		return nil
+	}
+
+	if isWithinBusinessHours(t) {
+		return nil
+	}
+
+	return []string{
+		fmt.Sprintf("Cluster annotation '%s' value '%s' is outside of business hours (%s to %s, %02d:00 to %02d:00 UTC).",
+			annotation.UpdateScheduleTargetTime,
+			updateTime,
+			time.Monday,
+			time.Friday,
+			businessHoursStart,
+			businessHoursEnd,
+		),
+	}
+}
+
+func isWithinBusinessHours(t time.Time) bool {
+	t = t.UTC()
+
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
 		return false
 	}
-	return true
+
+	return t.Hour() >= businessHoursStart && t.Hour() < businessHoursEnd
+}
+
+// ValidateUpgradeScheduleTime returns whether the scheduled upgrade time is
+// valid and 16 minutes to 6 months in the future.
+func ValidateUpgradeScheduleTime(updateTime string) bool {
+	return validateUpgradeScheduleTime(updateTime, time.Now().UTC()) == nil
 }
 
-func ValidateClusterAnnotationUpgradeRelease(ctx context.Context, client client.Client, cluster *capi.Cluster) error {
+// ValidateClusterAnnotationUpgradeRelease checks the scheduled upgrade release
+// is a valid upgrade from the current release of the cluster, also according
+// to the given release policy. The cluster must not be being created, and the
+// target release must be newer than a release that is being rolled out.
+func ValidateClusterAnnotationUpgradeRelease(ctx context.Context, client client.Client, policy releaseversion.Policy, cluster *capi.Cluster) error {
 	if targetRelease, ok := cluster.GetAnnotations()[annotation.UpdateScheduleTargetRelease]; ok {
 		oldVersion, err := semverhelper.GetSemverFromLabels(cluster.Labels)
 		if err != nil {
@@ -70,7 +161,20 @@ func ValidateClusterAnnotationUpgradeRelease(ctx context.Context, client client.
 			return microerror.Mask(err)
 		}
 
-		err = releaseversion.Validate(ctx, client, oldVersion, newVersion)
+		err = conditions.ValidateUpgradeCanBeScheduled(cluster, newVersion)
+		if err != nil {
+			return microerror.Maskf(notAllowedError,
+				fmt.Sprintf("Cluster annotation '%s' value '%s' is not valid. %v",
+					annotation.UpdateScheduleTargetRelease,
+					targetRelease,
+					err),
+			)
+		}
+
+		err = releaseversion.Validate(ctx, client, policy, oldVersion, newVersion)
+		if err == nil {
+			err = releaseversion.ValidatePolicyOnUpgrade(ctx, client, policy, oldVersion, newVersion)
+		}
 		if err != nil {
 			return microerror.Maskf(notAllowedError,
 				fmt.Sprintf("Cluster annotation '%s' value '%s' is not valid. Value must be an existing giant swarm release version above the current release version %s and must not have a v prefix. %v",
@@ -89,6 +193,44 @@ func ValidateClusterAnnotationUpgradeRelease(ctx context.Context, client client.
 				),
 			)
 		}
+
+		err = validateLegacyRelease(ctx, client, oldVersion, newVersion)
+		if err != nil {
+			return microerror.Maskf(notAllowedError,
+				fmt.Sprintf("Cluster annotation '%s' value '%s' is not valid. %v",
+					annotation.UpdateScheduleTargetRelease,
+					targetRelease,
+					err),
+			)
+		}
+	}
+	return nil
+}
+
+// validateLegacyRelease checks that a cluster running a release with
+// azure-operator is not upgraded to a release without it, as that would move
+// the cluster off the legacy path.
+func validateLegacyRelease(ctx context.Context, client client.Client, oldVersion semver.Version, newVersion semver.Version) error {
+	oldRelease, err := release.FindRelease(ctx, client, oldVersion.String())
+	if release.IsReleaseNotFoundError(err) {
+		// The current release is gone, so there is nothing to compare with.
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
 	}
+
+	if release.GetComponentVersionsFromReleaseCR(oldRelease)[azureOperatorComponentName] == "" {
+		return nil
+	}
+
+	newRelease, err := release.FindRelease(ctx, client, newVersion.String())
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if release.GetComponentVersionsFromReleaseCR(newRelease)[azureOperatorComponentName] == "" {
+		return microerror.Maskf(errors.InvalidOperationError, "release %s does not contain %s, upgrading the cluster from release %s to it is not supported", newVersion, azureOperatorComponentName, oldVersion)
+	}
+
 	return nil
 }
@@ -0,0 +1,82 @@
+package scheduledupgrades
+
+import (
+	"time"
+
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	// minScheduleLead and maxScheduleLead define how far in the future
+	// upgrades can be scheduled (6 months are 4380 hours).
+	minScheduleLead = 16 * time.Minute
+	maxScheduleLead = 4380 * time.Hour
+)
+
+// ParseUpgradeScheduleTime parses the scheduled upgrade time. It accepts
+// RFC3339 (e.g. 2021-01-30T16:04:00+01:00) and RFC822 times in any time zone.
+// RFC822 zones must be UTC, GMT or a numeric offset (e.g. 30 Jan 21 16:04
+// +0100), as other zone abbreviations are ambiguous.
+func ParseUpgradeScheduleTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(time.RFC822Z, value)
+	if err == nil {
+		return t, nil
+	}
+
+	t, err = time.Parse(time.RFC822, value)
+	if err != nil {
+		return time.Time{}, microerror.Maskf(invalidTimeError, "value must be an RFC3339 (e.g. 2021-01-30T15:04:00Z) or RFC822 (e.g. 30 Jan 21 15:04 UTC) time")
+	}
+
+	// Unknown zone abbreviations are parsed with a zero offset, so only the
+	// ones without offset are unambiguous.
+	zone, offset := t.Zone()
+	if offset == 0 && zone != "UTC" && zone != "GMT" {
+		return time.Time{}, microerror.Maskf(invalidTimeError, "time zone %s is ambiguous, use UTC or a numeric offset (e.g. 30 Jan 21 16:04 +0100)", zone)
+	}
+
+	return t, nil
+}
+
+// FormatUpgradeScheduleTime returns the canonical form of the scheduled
+// upgrade time the upgrade scheduler expects, which is RFC822 in UTC.
+func FormatUpgradeScheduleTime(t time.Time) string {
+	return t.UTC().Format(time.RFC822)
+}
+
+// NormalizeUpgradeScheduleTime returns the canonical form of the given
+// scheduled upgrade time, see FormatUpgradeScheduleTime.
+func NormalizeUpgradeScheduleTime(value string) (string, error) {
+	t, err := ParseUpgradeScheduleTime(value)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	return FormatUpgradeScheduleTime(t), nil
+}
+
+// validateUpgradeScheduleTime checks the scheduled upgrade time is valid and
+// 16 minutes to 6 months in the future.
+func validateUpgradeScheduleTime(value string, now time.Time) error {
+	t, err := ParseUpgradeScheduleTime(value)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	earliest := now.Add(minScheduleLead)
+	latest := now.Add(maxScheduleLead)
+	if t.Before(earliest) || t.After(latest) {
+		return microerror.Maskf(invalidTimeError, "the time %s must be between %s and %s (16 minutes to 6 months in the future)",
+			FormatUpgradeScheduleTime(t),
+			FormatUpgradeScheduleTime(earliest),
+			FormatUpgradeScheduleTime(latest),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package scheduledupgrades
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNormalizeUpgradeScheduleTime(t *testing.T) {
+	testCases := []struct {
+		name         string
+		value        string
+		expected     string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:     "case 0: RFC822 in UTC",
+			value:    "30 Jan 21 15:04 UTC",
+			expected: "30 Jan 21 15:04 UTC",
+		},
+		{
+			name:     "case 1: RFC822 in GMT",
+			value:    "30 Jan 21 15:04 GMT",
+			expected: "30 Jan 21 15:04 UTC",
+		},
+		{
+			name:     "case 2: RFC822 with numeric offset",
+			value:    "30 Jan 21 16:04 +0100",
+			expected: "30 Jan 21 15:04 UTC",
+		},
+		{
+			name:     "case 3: RFC3339 in UTC",
+			value:    "2021-01-30T15:04:00Z",
+			expected: "30 Jan 21 15:04 UTC",
+		},
+		{
+			name:     "case 4: RFC3339 with offset",
+			value:    "2021-01-30T10:04:00-05:00",
+			expected: "30 Jan 21 15:04 UTC",
+		},
+		{
+			name:         "case 5: RFC822 with unknown zone abbreviation",
+			value:        "30 Jan 21 16:04 XYZ",
+			errorMatcher: IsInvalidTime,
+		},
+		{
+			name:         "case 6: unknown format",
+			value:        "2021-01-30 15:04",
+			errorMatcher: IsInvalidTime,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			normalized, err := NormalizeUpgradeScheduleTime(tc.value)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			if normalized != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, normalized)
+			}
+		})
+	}
+}
+
+func TestValidateUpgradeScheduleTime(t *testing.T) {
+	now := time.Date(2021, 1, 30, 15, 4, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		value        string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:  "case 0: one hour ahead in another zone",
+			value: "2021-01-30T17:04:00+01:00",
+		},
+		{
+			name:         "case 1: less than 16 minutes ahead",
+			value:        "30 Jan 21 15:10 UTC",
+			errorMatcher: IsInvalidTime,
+		},
+		{
+			name:         "case 2: more than 6 months ahead",
+			value:        "2021-08-30T15:04:00Z",
+			errorMatcher: IsInvalidTime,
+		},
+		{
+			name:         "case 3: in the past in another zone",
+			value:        "30 Jan 21 15:30 +0100",
+			errorMatcher: IsInvalidTime,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			err := validateUpgradeScheduleTime(tc.value, now)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
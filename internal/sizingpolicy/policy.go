@@ -0,0 +1,182 @@
+package sizingpolicy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is the name of a field of a sizing policy.
+type Rule string
+
+const (
+	RuleAllowedFamilies Rule = "allowedFamilies"
+	RuleDeniedFamilies  Rule = "deniedFamilies"
+	RuleMinCPUs         Rule = "minCPUs"
+	RuleMaxCPUs         Rule = "maxCPUs"
+	RuleMinMemoryGB     Rule = "minMemoryGB"
+	RuleMaxMemoryGB     Rule = "maxMemoryGB"
+	RuleMaxDataDisks    Rule = "maxDataDisks"
+)
+
+const (
+	// PolicyBuiltIn is the name of the policy that applies when no sizing
+	// policy file is configured, see BuiltIn.
+	PolicyBuiltIn = "built-in"
+	// PolicyInstallation is the name of the policy of the installation.
+	PolicyInstallation = "installation"
+)
+
+// Policy defines which VM sizes node pools can use. Unset fields don't
+// restrict anything by themselves, they are inherited from the policy below,
+// see Registry.Effective.
+type Policy struct {
+	// AllowedFamilies are the Azure VM families node pools can use, e.g.
+	// standardDSv3Family. All families are allowed when it is not set.
+	AllowedFamilies []string `json:"allowedFamilies,omitempty"`
+	// DeniedFamilies are the Azure VM families node pools can't use.
+	DeniedFamilies []string `json:"deniedFamilies,omitempty"`
+	MinCPUs        *int     `json:"minCPUs,omitempty"`
+	MaxCPUs        *int     `json:"maxCPUs,omitempty"`
+	MinMemoryGB    *int     `json:"minMemoryGB,omitempty"`
+	MaxMemoryGB    *int     `json:"maxMemoryGB,omitempty"`
+	MaxDataDisks   *int     `json:"maxDataDisks,omitempty"`
+}
+
+// BuiltIn returns the policy that applies when no sizing policy file is
+// configured. Its minimums are the ones node pools always had.
+func BuiltIn() Policy {
+	minCPUs := 4
+	minMemoryGB := 16
+
+	return Policy{
+		MinCPUs:     &minCPUs,
+		MinMemoryGB: &minMemoryGB,
+	}
+}
+
+// NodePool is what a sizing policy is checked against.
+type NodePool struct {
+	VMSize    string
+	Family    string
+	CPUs      int
+	MemoryGB  int
+	DataDisks int
+}
+
+// Effective is the policy that applies to an organization, made of the rules
+// of the built-in, installation and organization policies.
+type Effective struct {
+	Policy
+
+	// Sources maps every rule that is set to the name of the policy it comes
+	// from, so that violations can name it.
+	Sources map[Rule]string
+}
+
+// Violation describes a rule of a sizing policy that a node pool breaks.
+type Violation struct {
+	// Policy is the name of the violated policy, e.g. "installation" or
+	// "organization acme".
+	Policy  string
+	Rule    Rule
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s, as required by rule %s of the %s sizing policy", v.Message, v.Rule, v.Policy)
+}
+
+// Check returns the first rule of the policy that the node pool violates, or
+// nil when the node pool complies with all of them.
+func (e Effective) Check(nodePool NodePool) *Violation {
+	if e.AllowedFamilies != nil && !containsFold(e.AllowedFamilies, nodePool.Family) {
+		return e.violation(RuleAllowedFamilies, "VM size %s belongs to family %q, the allowed families are %s", nodePool.VMSize, nodePool.Family, strings.Join(e.AllowedFamilies, ", "))
+	}
+	if containsFold(e.DeniedFamilies, nodePool.Family) {
+		return e.violation(RuleDeniedFamilies, "VM size %s belongs to family %q, which is denied", nodePool.VMSize, nodePool.Family)
+	}
+	if e.MinCPUs != nil && nodePool.CPUs < *e.MinCPUs {
+		return e.violation(RuleMinCPUs, "VM size %s has %d cores, the minimum is %d", nodePool.VMSize, nodePool.CPUs, *e.MinCPUs)
+	}
+	if e.MaxCPUs != nil && nodePool.CPUs > *e.MaxCPUs {
+		return e.violation(RuleMaxCPUs, "VM size %s has %d cores, the maximum is %d", nodePool.VMSize, nodePool.CPUs, *e.MaxCPUs)
+	}
+	if e.MinMemoryGB != nil && nodePool.MemoryGB < *e.MinMemoryGB {
+		return e.violation(RuleMinMemoryGB, "VM size %s has %d GBs of memory, the minimum is %d GBs", nodePool.VMSize, nodePool.MemoryGB, *e.MinMemoryGB)
+	}
+	if e.MaxMemoryGB != nil && nodePool.MemoryGB > *e.MaxMemoryGB {
+		return e.violation(RuleMaxMemoryGB, "VM size %s has %d GBs of memory, the maximum is %d GBs", nodePool.VMSize, nodePool.MemoryGB, *e.MaxMemoryGB)
+	}
+	if e.MaxDataDisks != nil && nodePool.DataDisks > *e.MaxDataDisks {
+		return e.violation(RuleMaxDataDisks, "The node pool has %d data disks, the maximum is %d", nodePool.DataDisks, *e.MaxDataDisks)
+	}
+
+	return nil
+}
+
+func (e Effective) violation(rule Rule, format string, args ...interface{}) *Violation {
+	return &Violation{
+		Policy:  e.Sources[rule],
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// effective merges the given policies, later ones overriding the rules they
+// set.
+func effective(names []string, policies []Policy) Effective {
+	e := Effective{
+		Sources: map[Rule]string{},
+	}
+
+	for i, p := range policies {
+		name := names[i]
+
+		if p.AllowedFamilies != nil {
+			e.AllowedFamilies = p.AllowedFamilies
+			e.Sources[RuleAllowedFamilies] = name
+		}
+		if p.DeniedFamilies != nil {
+			e.DeniedFamilies = p.DeniedFamilies
+			e.Sources[RuleDeniedFamilies] = name
+		}
+		if p.MinCPUs != nil {
+			e.MinCPUs = p.MinCPUs
+			e.Sources[RuleMinCPUs] = name
+		}
+		if p.MaxCPUs != nil {
+			e.MaxCPUs = p.MaxCPUs
+			e.Sources[RuleMaxCPUs] = name
+		}
+		if p.MinMemoryGB != nil {
+			e.MinMemoryGB = p.MinMemoryGB
+			e.Sources[RuleMinMemoryGB] = name
+		}
+		if p.MaxMemoryGB != nil {
+			e.MaxMemoryGB = p.MaxMemoryGB
+			e.Sources[RuleMaxMemoryGB] = name
+		}
+		if p.MaxDataDisks != nil {
+			e.MaxDataDisks = p.MaxDataDisks
+			e.Sources[RuleMaxDataDisks] = name
+		}
+	}
+
+	return e
+}
+
+// OrganizationPolicyName returns the name of the policy of the given
+// organization.
+func OrganizationPolicyName(organization string) string {
+	return fmt.Sprintf("organization %s", organization)
+}
+
+func containsFold(list []string, s string) bool {
+	for _, l := range list {
+		if strings.EqualFold(l, s) {
+			return true
+		}
+	}
+
+	return false
+}
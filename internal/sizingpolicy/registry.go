@@ -0,0 +1,149 @@
+package sizingpolicy
+
+import (
+	"context"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/azure-admission-controller/internal/filewatch"
+)
+
+// FileConfig is the content of the sizing policy file.
+//
+// Example:
+//
+//	installation:
+//	  deniedFamilies: [standardLSv2Family]
+//	  maxDataDisks: 4
+//	organizations:
+//	  acme:
+//	    minCPUs: 8
+//	    maxCPUs: 32
+type FileConfig struct {
+	// Installation is the policy of all organizations of the installation.
+	Installation Policy `json:"installation"`
+	// Organizations overrides rules of the installation policy per
+	// organization.
+	Organizations map[string]Policy `json:"organizations,omitempty"`
+}
+
+type RegistryConfig struct {
+	Logger micrologger.Logger
+
+	// File is the path of the sizing policy file. It is optional, when empty
+	// only the built-in policy applies.
+	File string
+}
+
+// Registry holds the sizing policies of the installation. They are read from
+// the policy file, which is reloaded by Watch, so that they can be changed
+// without restarting the webhook.
+type Registry struct {
+	file *filewatch.File[FileConfig]
+}
+
+func NewRegistry(config RegistryConfig) (*Registry, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	file, err := filewatch.New(filewatch.Config[FileConfig]{
+		Logger: config.Logger,
+		File:   config.File,
+		Name:   "sizing policies",
+		Parse:  parse,
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	r := &Registry{
+		file: file,
+	}
+
+	return r, nil
+}
+
+// Effective returns the policy that applies to node pools of the given
+// organization. Rules set in the organization policy override the ones of the
+// installation policy, which override the built-in ones.
+func (r *Registry) Effective(organization string) Effective {
+	names := []string{PolicyBuiltIn}
+	policies := []Policy{BuiltIn()}
+
+	if r == nil {
+		return effective(names, policies)
+	}
+
+	config := r.file.Value()
+
+	names = append(names, PolicyInstallation)
+	policies = append(policies, config.Installation)
+
+	if p, ok := config.Organizations[organization]; ok {
+		names = append(names, OrganizationPolicyName(organization))
+		policies = append(policies, p)
+	}
+
+	return effective(names, policies)
+}
+
+// Reload reads the policy file again. An invalid policy file is rejected as a
+// whole, and the previously loaded policies are kept.
+func (r *Registry) Reload() error {
+	return microerror.Mask(r.file.Reload())
+}
+
+// Watch reloads the policy file at the given interval until the context is
+// done.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	r.file.Watch(ctx, interval)
+}
+
+func parse(content []byte) (FileConfig, error) {
+	var fileConfig FileConfig
+	err := yaml.UnmarshalStrict(content, &fileConfig)
+	if err != nil {
+		return FileConfig{}, microerror.Maskf(invalidPolicyError, "%s", err)
+	}
+
+	err = validate(PolicyInstallation, fileConfig.Installation)
+	if err != nil {
+		return FileConfig{}, microerror.Mask(err)
+	}
+	for organization, p := range fileConfig.Organizations {
+		err = validate(OrganizationPolicyName(organization), p)
+		if err != nil {
+			return FileConfig{}, microerror.Mask(err)
+		}
+	}
+
+	return fileConfig, nil
+}
+
+func validate(name string, p Policy) error {
+	limits := map[Rule]*int{
+		RuleMinCPUs:      p.MinCPUs,
+		RuleMaxCPUs:      p.MaxCPUs,
+		RuleMinMemoryGB:  p.MinMemoryGB,
+		RuleMaxMemoryGB:  p.MaxMemoryGB,
+		RuleMaxDataDisks: p.MaxDataDisks,
+	}
+	for rule, limit := range limits {
+		if limit != nil && *limit < 0 {
+			return microerror.Maskf(invalidPolicyError, "rule %s of the %s sizing policy must not be negative", rule, name)
+		}
+	}
+
+	if p.MinCPUs != nil && p.MaxCPUs != nil && *p.MinCPUs > *p.MaxCPUs {
+		return microerror.Maskf(invalidPolicyError, "rule %s of the %s sizing policy must not be greater than rule %s", RuleMinCPUs, name, RuleMaxCPUs)
+	}
+	if p.MinMemoryGB != nil && p.MaxMemoryGB != nil && *p.MinMemoryGB > *p.MaxMemoryGB {
+		return microerror.Maskf(invalidPolicyError, "rule %s of the %s sizing policy must not be greater than rule %s", RuleMinMemoryGB, name, RuleMaxMemoryGB)
+	}
+
+	return nil
+}
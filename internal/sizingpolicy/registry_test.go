@@ -0,0 +1,155 @@
+package sizingpolicy
+
+import (
+	"testing"
+
+	"github.com/giantswarm/azure-admission-controller/internal/filewatch"
+)
+
+const testConfig = `
+installation:
+  deniedFamilies: [standardLSv2Family]
+  maxCPUs: 32
+organizations:
+  acme:
+    minCPUs: 8
+    allowedFamilies: [standardDSv3Family]
+`
+
+func TestEffectiveCheck(t *testing.T) {
+	testCases := []struct {
+		name              string
+		config            string
+		organization      string
+		nodePool          NodePool
+		expectedViolation *Violation
+	}{
+		{
+			name:         "case 0: built-in policy allows big enough VM sizes",
+			config:       "",
+			organization: "giantswarm",
+			nodePool:     NodePool{VMSize: "Standard_D4_v3", Family: "standardDv3Family", CPUs: 4, MemoryGB: 16, DataDisks: 2},
+		},
+		{
+			name:              "case 1: built-in minimum cores",
+			config:            "",
+			organization:      "giantswarm",
+			nodePool:          NodePool{VMSize: "Standard_D2_v3", Family: "standardDv3Family", CPUs: 2, MemoryGB: 16},
+			expectedViolation: &Violation{Policy: PolicyBuiltIn, Rule: RuleMinCPUs},
+		},
+		{
+			name:              "case 2: built-in minimum memory",
+			config:            testConfig,
+			organization:      "giantswarm",
+			nodePool:          NodePool{VMSize: "Standard_F4s_v2", Family: "standardFSv2Family", CPUs: 4, MemoryGB: 8},
+			expectedViolation: &Violation{Policy: PolicyBuiltIn, Rule: RuleMinMemoryGB},
+		},
+		{
+			name:              "case 3: installation denied family",
+			config:            testConfig,
+			organization:      "giantswarm",
+			nodePool:          NodePool{VMSize: "Standard_L8s_v2", Family: "standardLSv2Family", CPUs: 8, MemoryGB: 64},
+			expectedViolation: &Violation{Policy: PolicyInstallation, Rule: RuleDeniedFamilies},
+		},
+		{
+			name:              "case 4: installation maximum cores",
+			config:            testConfig,
+			organization:      "giantswarm",
+			nodePool:          NodePool{VMSize: "Standard_D64s_v3", Family: "standardDSv3Family", CPUs: 64, MemoryGB: 256},
+			expectedViolation: &Violation{Policy: PolicyInstallation, Rule: RuleMaxCPUs},
+		},
+		{
+			name:              "case 5: organization minimum cores override the built-in one",
+			config:            testConfig,
+			organization:      "acme",
+			nodePool:          NodePool{VMSize: "Standard_D4s_v3", Family: "standardDSv3Family", CPUs: 4, MemoryGB: 16},
+			expectedViolation: &Violation{Policy: "organization acme", Rule: RuleMinCPUs},
+		},
+		{
+			name:              "case 6: organization allowed families",
+			config:            testConfig,
+			organization:      "acme",
+			nodePool:          NodePool{VMSize: "Standard_D8_v3", Family: "standardDv3Family", CPUs: 8, MemoryGB: 32},
+			expectedViolation: &Violation{Policy: "organization acme", Rule: RuleAllowedFamilies},
+		},
+		{
+			name:         "case 7: organization complying with all policies",
+			config:       testConfig,
+			organization: "acme",
+			nodePool:     NodePool{VMSize: "Standard_D8s_v3", Family: "standardDSv3Family", CPUs: 8, MemoryGB: 32},
+		},
+		{
+			name:              "case 8: maximum data disks",
+			config:            "installation:\n  maxDataDisks: 1\n",
+			organization:      "giantswarm",
+			nodePool:          NodePool{VMSize: "Standard_D4_v3", CPUs: 4, MemoryGB: 16, DataDisks: 2},
+			expectedViolation: &Violation{Policy: PolicyInstallation, Rule: RuleMaxDataDisks},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := newTestRegistry(t, tc.config)
+
+			violation := registry.Effective(tc.organization).Check(tc.nodePool)
+
+			switch {
+			case violation == nil && tc.expectedViolation == nil:
+				// fall through
+			case violation != nil && tc.expectedViolation == nil:
+				t.Fatalf("unexpected violation: %s", violation.Error())
+			case violation == nil && tc.expectedViolation != nil:
+				t.Fatalf("expected violation of rule %s, got nil", tc.expectedViolation.Rule)
+			case violation.Policy != tc.expectedViolation.Policy || violation.Rule != tc.expectedViolation.Rule:
+				t.Fatalf("expected violation of rule %s of the %s policy, got %s", tc.expectedViolation.Rule, tc.expectedViolation.Policy, violation.Error())
+			}
+		})
+	}
+}
+
+func TestEffectiveWithoutRegistry(t *testing.T) {
+	var registry *Registry
+
+	violation := registry.Effective("giantswarm").Check(NodePool{VMSize: "Standard_D2_v3", CPUs: 2, MemoryGB: 8})
+	if violation == nil || violation.Policy != PolicyBuiltIn {
+		t.Fatalf("expected violation of the built-in policy, got %v", violation)
+	}
+}
+
+func TestParseInvalidPolicy(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "case 0: unknown rule",
+			config: "installation:\n  minGPUs: 1\n",
+		},
+		{
+			name:   "case 1: negative limit",
+			config: "installation:\n  maxDataDisks: -1\n",
+		},
+		{
+			name:   "case 2: minimum greater than maximum",
+			config: "organizations:\n  acme:\n    minMemoryGB: 64\n    maxMemoryGB: 32\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parse([]byte(tc.config))
+			if !IsInvalidPolicy(err) {
+				t.Fatalf("expected invalid policy error, got %#v", err)
+			}
+		})
+	}
+}
+
+func newTestRegistry(t *testing.T, config string) *Registry {
+	fileConfig, err := parse([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Registry{file: filewatch.Static(fileConfig)}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/giantswarm/apiextensions/v6/pkg/label"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -41,6 +42,18 @@ func Labels(labels map[string]string) BuilderOption {
 	}
 }
 
+func Annotations(annotations map[string]string) BuilderOption {
+	return func(azureCluster *capz.AzureCluster) *capz.AzureCluster {
+		if azureCluster.Annotations == nil {
+			azureCluster.Annotations = map[string]string{}
+		}
+		for k, v := range annotations {
+			azureCluster.Annotations[k] = v
+		}
+		return azureCluster
+	}
+}
+
 func Location(location string) BuilderOption {
 	return func(azureCluster *capz.AzureCluster) *capz.AzureCluster {
 		azureCluster.Spec.Location = location
@@ -56,6 +69,17 @@ func ControlPlaneEndpoint(controlPlaneEndpointHost string, controlPlaneEndpointP
 	}
 }
 
+func IdentityRef(name string, namespace string) BuilderOption {
+	return func(azureCluster *capz.AzureCluster) *capz.AzureCluster {
+		azureCluster.Spec.IdentityRef = &corev1.ObjectReference{
+			Kind:      "AzureClusterIdentity",
+			Name:      name,
+			Namespace: namespace,
+		}
+		return azureCluster
+	}
+}
+
 func WithDeletionTimestamp() BuilderOption {
 	return func(azureCluster *capz.AzureCluster) *capz.AzureCluster {
 		now := metav1.Now()
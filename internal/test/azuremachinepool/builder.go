@@ -31,6 +31,20 @@ func Cluster(clusterName string) BuilderOption {
 	}
 }
 
+func CachingType(cachingType string) BuilderOption {
+	return func(azureMachinePool *capzexp.AzureMachinePool) *capzexp.AzureMachinePool {
+		azureMachinePool.Spec.Template.OSDisk.CachingType = cachingType
+		return azureMachinePool
+	}
+}
+
+func Conditions(conditions capi.Conditions) BuilderOption {
+	return func(azureMachinePool *capzexp.AzureMachinePool) *capzexp.AzureMachinePool {
+		azureMachinePool.Status.Conditions = conditions
+		return azureMachinePool
+	}
+}
+
 func DataDisks(dataDisks []capz.DataDisk) BuilderOption {
 	return func(azureMachinePool *capzexp.AzureMachinePool) *capzexp.AzureMachinePool {
 		azureMachinePool.Spec.Template.DataDisks = dataDisks
@@ -38,6 +52,15 @@ func DataDisks(dataDisks []capz.DataDisk) BuilderOption {
 	}
 }
 
+func EphemeralOSDisk() BuilderOption {
+	return func(azureMachinePool *capzexp.AzureMachinePool) *capzexp.AzureMachinePool {
+		azureMachinePool.Spec.Template.OSDisk.DiffDiskSettings = &capz.DiffDiskSettings{
+			Option: "Local",
+		}
+		return azureMachinePool
+	}
+}
+
 func Location(location string) BuilderOption {
 	return func(azureMachinePool *capzexp.AzureMachinePool) *capzexp.AzureMachinePool {
 		azureMachinePool.Spec.Location = location
@@ -45,6 +68,13 @@ func Location(location string) BuilderOption {
 	}
 }
 
+func OSDiskSizeGB(diskSizeGB int32) BuilderOption {
+	return func(azureMachinePool *capzexp.AzureMachinePool) *capzexp.AzureMachinePool {
+		azureMachinePool.Spec.Template.OSDisk.DiskSizeGB = &diskSizeGB
+		return azureMachinePool
+	}
+}
+
 func Organization(org string) BuilderOption {
 	return func(azureMachinePool *capzexp.AzureMachinePool) *capzexp.AzureMachinePool {
 		azureMachinePool.Labels[label.Organization] = org
@@ -31,6 +31,18 @@ func Labels(labels map[string]string) BuilderOption {
 	}
 }
 
+func Annotations(annotations map[string]string) BuilderOption {
+	return func(cluster *capi.Cluster) *capi.Cluster {
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		for k, v := range annotations {
+			cluster.Annotations[k] = v
+		}
+		return cluster
+	}
+}
+
 func ControlPlaneEndpoint(controlPlaneEndpointHost string, controlPlaneEndpointPort int32) BuilderOption {
 	return func(cluster *capi.Cluster) *capi.Cluster {
 		cluster.Spec.ControlPlaneEndpoint.Host = controlPlaneEndpointHost
@@ -39,6 +51,13 @@ func ControlPlaneEndpoint(controlPlaneEndpointHost string, controlPlaneEndpointP
 	}
 }
 
+func Conditions(conditions capi.Conditions) BuilderOption {
+	return func(cluster *capi.Cluster) *capi.Cluster {
+		cluster.Status.Conditions = conditions
+		return cluster
+	}
+}
+
 func WithDeletionTimestamp() BuilderOption {
 	return func(cluster *capi.Cluster) *capi.Cluster {
 		now := metav1.Now()
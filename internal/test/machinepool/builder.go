@@ -23,6 +23,13 @@ func AzureMachinePool(azureMachinePoolName string) BuilderOption {
 	}
 }
 
+func Conditions(conditions capi.Conditions) BuilderOption {
+	return func(machinePool *capiexp.MachinePool) *capiexp.MachinePool {
+		machinePool.Status.Conditions = conditions
+		return machinePool
+	}
+}
+
 func FailureDomains(failureDomains []string) BuilderOption {
 	return func(machinePool *capiexp.MachinePool) *capiexp.MachinePool {
 		machinePool.Spec.FailureDomains = failureDomains
@@ -65,6 +72,13 @@ func Annotation(name, val string) BuilderOption {
 	}
 }
 
+func Label(name, val string) BuilderOption {
+	return func(machinePool *capiexp.MachinePool) *capiexp.MachinePool {
+		machinePool.Labels[name] = val
+		return machinePool
+	}
+}
+
 func WithDeletionTimestamp() BuilderOption {
 	return func(machinePool *capiexp.MachinePool) *capiexp.MachinePool {
 		now := metav1.Now()
@@ -0,0 +1,253 @@
+package vmcapabilities
+
+import (
+	"context"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/yaml"
+)
+
+// resourceTypeVirtualMachines is the resource type of the VM SKUs. Only these
+// are added to a catalog, the webhooks don't look up any other SKUs.
+const resourceTypeVirtualMachines = "virtualMachines"
+
+// Catalog is a snapshot of the VM SKUs of some locations. It is used instead
+// of the Azure API where the API can't be reached, see FileAPI.
+//
+// The compute API types can't be used for this, as they don't marshal their
+// read-only fields.
+//
+// Example:
+//
+//	subscriptionID: 00000000-0000-0000-0000-000000000000
+//	locations:
+//	  westeurope:
+//	  - name: Standard_D4s_v3
+//	    family: standardDSv3Family
+//	    zones: ["1", "2", "3"]
+//	    capabilities:
+//	      MemoryGB: "16"
+//	      vCPUs: "4"
+//	    restrictions:
+//	    - type: Zone
+//	      reasonCode: NotAvailableForSubscription
+//	      values: [westeurope]
+//	      locations: [westeurope]
+//	      zones: ["2"]
+type Catalog struct {
+	// SubscriptionID is the subscription the SKUs were listed for. Their
+	// restrictions only apply to it, see FileAPIConfig.SubscriptionID.
+	SubscriptionID string `json:"subscriptionID,omitempty"`
+	// Locations maps a location to its VM SKUs.
+	Locations map[string][]CatalogSKU `json:"locations"`
+}
+
+type CatalogSKU struct {
+	Name         string               `json:"name"`
+	Family       string               `json:"family,omitempty"`
+	Tier         string               `json:"tier,omitempty"`
+	Size         string               `json:"size,omitempty"`
+	Zones        []string             `json:"zones,omitempty"`
+	Capabilities map[string]string    `json:"capabilities,omitempty"`
+	Restrictions []CatalogRestriction `json:"restrictions,omitempty"`
+}
+
+type CatalogRestriction struct {
+	Type       string   `json:"type"`
+	ReasonCode string   `json:"reasonCode,omitempty"`
+	Values     []string `json:"values,omitempty"`
+	Locations  []string `json:"locations,omitempty"`
+	Zones      []string `json:"zones,omitempty"`
+}
+
+// LoadCatalog reads a catalog from the given JSON or YAML file.
+func LoadCatalog(path string) (*Catalog, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	catalog, err := ParseCatalog(content)
+	if err != nil {
+		return nil, microerror.Maskf(invalidCatalogError, "catalog file %#q: %s", path, err.Error())
+	}
+
+	return catalog, nil
+}
+
+// ParseCatalog decodes a JSON or YAML catalog.
+func ParseCatalog(content []byte) (*Catalog, error) {
+	var catalog Catalog
+	err := yaml.UnmarshalStrict(content, &catalog)
+	if err != nil {
+		return nil, microerror.Maskf(invalidCatalogError, "%s", err)
+	}
+
+	for location, skus := range catalog.Locations {
+		for i, sku := range skus {
+			if sku.Name == "" {
+				return nil, microerror.Maskf(invalidCatalogError, "SKU %d of location %#q has no name", i, location)
+			}
+		}
+	}
+
+	return &catalog, nil
+}
+
+// Add stores the VM SKUs of the given location in the catalog, replacing the
+// ones it had before. SKUs of other resource types are skipped.
+func (c *Catalog) Add(location string, skus map[string]compute.ResourceSku) {
+	if c.Locations == nil {
+		c.Locations = map[string][]CatalogSKU{}
+	}
+
+	var catalogSKUs []CatalogSKU
+	for _, sku := range skus {
+		if to.String(sku.ResourceType) != resourceTypeVirtualMachines {
+			continue
+		}
+		catalogSKUs = append(catalogSKUs, toCatalogSKU(sku, location))
+	}
+
+	// Keep the output stable, so that exported catalogs can be diffed.
+	sort.Slice(catalogSKUs, func(i, j int) bool {
+		return catalogSKUs[i].Name < catalogSKUs[j].Name
+	})
+
+	c.Locations[normalizeLocation(location)] = catalogSKUs
+}
+
+// SKUs returns the VM SKUs of the given location by name, in the format
+// returned by the Azure API.
+func (c *Catalog) SKUs(location string) (map[string]compute.ResourceSku, bool) {
+	catalogSKUs, ok := c.Locations[normalizeLocation(location)]
+	if !ok {
+		return nil, false
+	}
+
+	skus := map[string]compute.ResourceSku{}
+	for _, catalogSKU := range catalogSKUs {
+		skus[catalogSKU.Name] = fromCatalogSKU(catalogSKU, location)
+	}
+
+	return skus, true
+}
+
+func toCatalogSKU(sku compute.ResourceSku, location string) CatalogSKU {
+	catalogSKU := CatalogSKU{
+		Name:   to.String(sku.Name),
+		Family: to.String(sku.Family),
+		Tier:   to.String(sku.Tier),
+		Size:   to.String(sku.Size),
+	}
+
+	if sku.LocationInfo != nil {
+		for _, locationInfo := range *sku.LocationInfo {
+			if strings.EqualFold(to.String(locationInfo.Location), location) && locationInfo.Zones != nil {
+				catalogSKU.Zones = append(catalogSKU.Zones, *locationInfo.Zones...)
+			}
+		}
+		sort.Strings(catalogSKU.Zones)
+	}
+
+	if sku.Capabilities != nil {
+		catalogSKU.Capabilities = map[string]string{}
+		for _, capability := range *sku.Capabilities {
+			catalogSKU.Capabilities[to.String(capability.Name)] = to.String(capability.Value)
+		}
+	}
+
+	if sku.Restrictions != nil {
+		for _, restriction := range *sku.Restrictions {
+			catalogRestriction := CatalogRestriction{
+				Type:       string(restriction.Type),
+				ReasonCode: string(restriction.ReasonCode),
+				Values:     to.StringSlice(restriction.Values),
+			}
+			if restriction.RestrictionInfo != nil {
+				catalogRestriction.Locations = to.StringSlice(restriction.RestrictionInfo.Locations)
+				catalogRestriction.Zones = to.StringSlice(restriction.RestrictionInfo.Zones)
+			}
+			catalogSKU.Restrictions = append(catalogSKU.Restrictions, catalogRestriction)
+		}
+	}
+
+	return catalogSKU
+}
+
+func fromCatalogSKU(catalogSKU CatalogSKU, location string) compute.ResourceSku {
+	sku := compute.ResourceSku{
+		ResourceType: to.StringPtr(resourceTypeVirtualMachines),
+		Name:         to.StringPtr(catalogSKU.Name),
+		Family:       stringPtrOrNil(catalogSKU.Family),
+		Tier:         stringPtrOrNil(catalogSKU.Tier),
+		Size:         stringPtrOrNil(catalogSKU.Size),
+		Locations:    &[]string{location},
+		LocationInfo: &[]compute.ResourceSkuLocationInfo{
+			{
+				Location: to.StringPtr(location),
+				Zones:    to.StringSlicePtr(append([]string{}, catalogSKU.Zones...)),
+			},
+		},
+	}
+
+	capabilities := []compute.ResourceSkuCapabilities{}
+	for name, value := range catalogSKU.Capabilities {
+		capabilities = append(capabilities, compute.ResourceSkuCapabilities{
+			Name:  to.StringPtr(name),
+			Value: to.StringPtr(value),
+		})
+	}
+	sku.Capabilities = &capabilities
+
+	restrictions := []compute.ResourceSkuRestrictions{}
+	for _, catalogRestriction := range catalogSKU.Restrictions {
+		restrictions = append(restrictions, compute.ResourceSkuRestrictions{
+			Type:       compute.ResourceSkuRestrictionsType(catalogRestriction.Type),
+			ReasonCode: compute.ResourceSkuRestrictionsReasonCode(catalogRestriction.ReasonCode),
+			Values:     to.StringSlicePtr(append([]string{}, catalogRestriction.Values...)),
+			RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
+				Locations: to.StringSlicePtr(append([]string{}, catalogRestriction.Locations...)),
+				Zones:     to.StringSlicePtr(append([]string{}, catalogRestriction.Zones...)),
+			},
+		})
+	}
+	sku.Restrictions = &restrictions
+
+	return sku
+}
+
+// normalizeLocation returns the location name the way Azure lists it, e.g.
+// "westeurope" for "West Europe".
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
+
+func stringPtrOrNil(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return to.StringPtr(s)
+}
+
+// ExportCatalog lists the VM SKUs of the given locations from the API, usually
+// the Azure API of the given subscription, and returns them as a catalog.
+func ExportCatalog(ctx context.Context, api API, subscriptionID string, locations []string) (*Catalog, error) {
+	catalog := &Catalog{SubscriptionID: subscriptionID}
+	for _, location := range locations {
+		skus, err := api.List(ctx, location)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		catalog.Add(location, skus)
+	}
+
+	return catalog, nil
+}
@@ -0,0 +1,396 @@
+package vmcapabilities
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/giantswarm/micrologger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func newCatalogTestSKUs() map[string]compute.ResourceSku {
+	return map[string]compute.ResourceSku{
+		"Standard_D4s_v3": {
+			ResourceType: to.StringPtr(resourceTypeVirtualMachines),
+			Name:         to.StringPtr("Standard_D4s_v3"),
+			LocationInfo: &[]compute.ResourceSkuLocationInfo{
+				{
+					Location: to.StringPtr("westeurope"),
+					Zones:    &[]string{"3", "1", "2"},
+				},
+			},
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(capabilityCPUs), Value: to.StringPtr("4")},
+				{Name: to.StringPtr(capabilityMemory), Value: to.StringPtr("16")},
+				{Name: to.StringPtr(CapabilityPremiumIO), Value: to.StringPtr(CapabilitySupported)},
+			},
+			Restrictions: &[]compute.ResourceSkuRestrictions{
+				{
+					Type:   compute.Zone,
+					Values: &[]string{"westeurope"},
+					RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
+						Locations: &[]string{"westeurope"},
+						Zones:     &[]string{"2"},
+					},
+					ReasonCode: compute.NotAvailableForSubscription,
+				},
+			},
+		},
+		"Premium_LRS": {
+			ResourceType: to.StringPtr("disks"),
+			Name:         to.StringPtr("Premium_LRS"),
+		},
+	}
+}
+
+func writeCatalog(t *testing.T, catalog *Catalog) string {
+	content, err := yaml.Marshal(catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "catalog.yaml")
+	err = os.WriteFile(path, content, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestExportedCatalogIsUsable(t *testing.T) {
+	logger, _ := micrologger.New(micrologger.Config{})
+	ctx := context.Background()
+
+	catalog, err := ExportCatalog(ctx, stubAPI(newCatalogTestSKUs()), "subscription-a", []string{"westeurope"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if catalog.SubscriptionID != "subscription-a" {
+		t.Fatalf("expected subscription %q, got %q", "subscription-a", catalog.SubscriptionID)
+	}
+	if len(catalog.Locations["westeurope"]) != 1 {
+		t.Fatalf("expected only the VM SKU to be exported, got %v", catalog.Locations["westeurope"])
+	}
+
+	api, err := NewFileAPI(FileAPIConfig{Path: writeCatalog(t, catalog)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vmsku, err := New(Config{Azure: api, Logger: logger})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cpus, err := vmsku.CPUs(ctx, "westeurope", "Standard_D4s_v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpus != 4 {
+		t.Fatalf("expected 4 CPUs, got %d", cpus)
+	}
+
+	memory, err := vmsku.Memory(ctx, "westeurope", "Standard_D4s_v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memory != 16 {
+		t.Fatalf("expected 16 GB of memory, got %d", memory)
+	}
+
+	premiumIO, err := vmsku.HasCapability(ctx, "westeurope", "Standard_D4s_v3", CapabilityPremiumIO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !premiumIO {
+		t.Fatalf("expected PremiumIO to be supported")
+	}
+
+	azs, err := vmsku.SupportedAZs(ctx, "westeurope", "Standard_D4s_v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(azs, []string{"1", "3"}) {
+		t.Fatalf("expected zones [1 3], got %v", azs)
+	}
+
+	_, err = vmsku.CPUs(ctx, "germanywestcentral", "Standard_D4s_v3")
+	if !IsLocationNotInCatalog(err) {
+		t.Fatalf("expected locationNotInCatalogError, got %v", err)
+	}
+}
+
+func TestParseCatalog(t *testing.T) {
+	testCases := []struct {
+		name         string
+		content      string
+		errorMatcher func(err error) bool
+	}{
+		{
+			name: "case 0: YAML catalog",
+			content: `locations:
+  westeurope:
+  - name: Standard_D4s_v3
+    capabilities:
+      vCPUs: "4"
+`,
+		},
+		{
+			name:    "case 1: JSON catalog",
+			content: `{"locations": {"westeurope": [{"name": "Standard_D4s_v3", "zones": ["1"]}]}}`,
+		},
+		{
+			name: "case 2: SKU without name",
+			content: `locations:
+  westeurope:
+  - capabilities:
+      vCPUs: "4"
+`,
+			errorMatcher: IsInvalidCatalog,
+		},
+		{
+			name: "case 3: unknown field",
+			content: `locations:
+  westeurope:
+  - name: Standard_D4s_v3
+    vcpus: 4
+`,
+			errorMatcher: IsInvalidCatalog,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseCatalog([]byte(tc.content))
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
+
+func TestFallbackIsOnlyUsedWithoutLiveSKUs(t *testing.T) {
+	logger, _ := micrologger.New(micrologger.Config{})
+	ctx := context.Background()
+
+	catalog := &Catalog{}
+	catalog.Add("westeurope", newCatalogTestSKUs())
+	fileAPI, err := NewFileAPI(FileAPIConfig{Path: writeCatalog(t, catalog)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := &flakyAPI{
+		failing: 1,
+		skus:    newTestSKUs("8"),
+	}
+	vmsku, err := New(Config{Azure: api, Fallback: fileAPI, Logger: logger, TTL: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vmsku.retryInterval = time.Hour
+
+	assertCPUs := func(expected int) {
+		t.Helper()
+
+		cpus, err := vmsku.CPUs(ctx, "westeurope", "Standard_D4s_v3")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cpus != expected {
+			t.Fatalf("expected %d CPUs, got %d", expected, cpus)
+		}
+	}
+
+	// Without any SKUs listed from Azure, the catalog is served.
+	assertCPUs(4)
+	assertCPUs(4)
+	waitForRefreshes(t, vmsku)
+	if calls := atomic.LoadInt32(&api.calls); calls != 1 {
+		t.Fatalf("expected 1 SKU list call, got %d", calls)
+	}
+
+	// Azure is retried after the retry interval, and replaces the catalog.
+	atomic.StoreInt32(&api.failing, 0)
+	vmsku.retryInterval = 0
+	assertCPUs(4)
+	waitForRefreshes(t, vmsku)
+	assertCPUs(8)
+	waitForRefreshes(t, vmsku)
+
+	// Once listed from Azure, the stale SKUs are served instead of the
+	// catalog.
+	atomic.StoreInt32(&api.failing, 1)
+	time.Sleep(10 * time.Millisecond)
+	assertCPUs(8)
+	waitForRefreshes(t, vmsku)
+	assertCPUs(8)
+	waitForRefreshes(t, vmsku)
+
+	// When the catalog doesn't help either, the error of Azure is returned.
+	_, err = vmsku.CPUs(ctx, "germanywestcentral", "Standard_D4s_v3")
+	if err == nil || IsLocationNotInCatalog(err) {
+		t.Fatalf("expected the Azure error, got %v", err)
+	}
+}
+
+func TestCatalogRestrictionsOfOtherSubscriptions(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		catalogSubscriptionID string
+		subscriptionID        string
+		expectedAZs           []string
+	}{
+		{
+			name:                  "case 0: no subscription",
+			catalogSubscriptionID: "subscription-a",
+			subscriptionID:        "",
+			expectedAZs:           []string{"1", "3"},
+		},
+		{
+			name:                  "case 1: exported subscription",
+			catalogSubscriptionID: "subscription-a",
+			subscriptionID:        "subscription-a",
+			expectedAZs:           []string{"1", "3"},
+		},
+		{
+			name:                  "case 2: other subscription",
+			catalogSubscriptionID: "subscription-a",
+			subscriptionID:        "subscription-b",
+			expectedAZs:           []string{"1", "2", "3"},
+		},
+		{
+			name:                  "case 3: unknown exported subscription",
+			catalogSubscriptionID: "",
+			subscriptionID:        "subscription-b",
+			expectedAZs:           []string{"1", "2", "3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := micrologger.New(micrologger.Config{})
+			ctx := context.Background()
+
+			catalog := &Catalog{SubscriptionID: tc.catalogSubscriptionID}
+			catalog.Add("westeurope", newCatalogTestSKUs())
+			fileAPI, err := NewFileAPI(FileAPIConfig{Path: writeCatalog(t, catalog)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			vmsku, err := New(Config{Azure: fileAPI.ForSubscription(tc.subscriptionID), Logger: logger})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			azs, err := vmsku.SupportedAZs(ctx, "westeurope", "Standard_D4s_v3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(azs, tc.expectedAZs) {
+				t.Fatalf("expected zones %v, got %v", tc.expectedAZs, azs)
+			}
+		})
+	}
+}
+
+func TestFactorySources(t *testing.T) {
+	logger, _ := micrologger.New(micrologger.Config{})
+
+	catalog := &Catalog{}
+	catalog.Add("westeurope", newCatalogTestSKUs())
+	path := writeCatalog(t, catalog)
+
+	invalidPath := filepath.Join(t.TempDir(), "invalid.yaml")
+	err := os.WriteFile(invalidPath, []byte("locations: [westeurope]\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name         string
+		source       string
+		catalogFile  string
+		errorMatcher func(err error) bool
+	}{
+		{
+			name: "case 0: live by default",
+		},
+		{
+			name:        "case 1: file",
+			source:      SourceFile,
+			catalogFile: path,
+		},
+		{
+			name:         "case 2: file without catalog",
+			source:       SourceFile,
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name:         "case 3: fallback with invalid catalog",
+			source:       SourceLiveWithFileFallback,
+			catalogFile:  invalidPath,
+			errorMatcher: IsInvalidCatalog,
+		},
+		{
+			name:         "case 4: unknown source",
+			source:       "cache",
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			factory, err := NewFactory(FactoryConfig{
+				Logger:      logger,
+				Source:      tc.source,
+				CatalogFile: tc.catalogFile,
+			})
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			if tc.source == SourceFile && err == nil {
+				// No credentials are looked up for the file source, so no
+				// client is needed.
+				vmsku, err := factory.GetClient(context.Background(), nil, metav1.ObjectMeta{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				cpus, err := vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cpus != 4 {
+					t.Fatalf("expected 4 CPUs, got %d", cpus)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package vmcapabilities
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
 	"github.com/giantswarm/microerror"
@@ -19,9 +20,10 @@ func NewAzureAPI(c AzureConfig) API {
 	return &Azure{resourceSkuClient: c.ResourceSkuClient}
 }
 
-func (a *Azure) List(ctx context.Context, filter string) (map[string]compute.ResourceSku, error) {
+func (a *Azure) List(ctx context.Context, location string) (map[string]compute.ResourceSku, error) {
 	skus := map[string]compute.ResourceSku{}
 
+	filter := fmt.Sprintf("location eq '%s'", location)
 	iterator, err := a.resourceSkuClient.ListComplete(ctx, filter)
 	if err != nil {
 		return skus, microerror.Mask(err)
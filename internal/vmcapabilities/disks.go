@@ -0,0 +1,50 @@
+package vmcapabilities
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/giantswarm/microerror"
+)
+
+// CacheDiskSizeGB returns the size of the cache disk of the VM size in GB. VM
+// sizes without a cache disk don't have the capability, so 0 is returned for
+// them.
+func (v *VMSKU) CacheDiskSizeGB(ctx context.Context, location string, vmType string) (int, error) {
+	capability, err := v.getCapability(ctx, location, vmType, capabilityCachedDiskBytes)
+	if err != nil {
+		return 0, microerror.Mask(err)
+	}
+
+	if capability == nil {
+		return 0, nil
+	}
+
+	bytes, err := strconv.ParseInt(*capability, 10, 64)
+	if err != nil {
+		return 0, microerror.Mask(invalidUpstreamResponseError)
+	}
+
+	return int(bytes / (1 << 30)), nil
+}
+
+// TempDiskSizeGB returns the size of the temporary disk of the VM size in GB.
+// VM sizes without a temporary disk don't have the capability, so 0 is
+// returned for them.
+func (v *VMSKU) TempDiskSizeGB(ctx context.Context, location string, vmType string) (int, error) {
+	capability, err := v.getCapability(ctx, location, vmType, capabilityMaxResourceVolumeMB)
+	if err != nil {
+		return 0, microerror.Mask(err)
+	}
+
+	if capability == nil {
+		return 0, nil
+	}
+
+	mb, err := strconv.Atoi(*capability)
+	if err != nil {
+		return 0, microerror.Mask(invalidUpstreamResponseError)
+	}
+
+	return mb / 1024, nil
+}
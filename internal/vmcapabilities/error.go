@@ -37,3 +37,21 @@ var skuNotFoundError = &microerror.Error{
 func IsSkuNotFoundError(err error) bool {
 	return microerror.Cause(err) == skuNotFoundError
 }
+
+var invalidCatalogError = &microerror.Error{
+	Kind: "invalidCatalogError",
+}
+
+// IsInvalidCatalog asserts invalidCatalogError.
+func IsInvalidCatalog(err error) bool {
+	return microerror.Cause(err) == invalidCatalogError
+}
+
+var locationNotInCatalogError = &microerror.Error{
+	Kind: "locationNotInCatalogError",
+}
+
+// IsLocationNotInCatalog asserts locationNotInCatalogError.
+func IsLocationNotInCatalog(err error) bool {
+	return microerror.Cause(err) == locationNotInCatalogError
+}
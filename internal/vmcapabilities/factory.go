@@ -2,9 +2,10 @@ package vmcapabilities
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,25 +14,131 @@ import (
 	"github.com/giantswarm/azure-admission-controller/internal/capzcredentials"
 )
 
+const (
+	// SourceLive lists the SKUs from the Azure API.
+	SourceLive = "live"
+	// SourceFile lists the SKUs from the catalog file only. The Azure API is
+	// never called and no credentials are needed.
+	SourceFile = "file"
+	// SourceLiveWithFileFallback lists the SKUs from the Azure API, and from
+	// the catalog file when the Azure API fails before any SKUs were listed
+	// from it, see Config.Fallback.
+	SourceLiveWithFileFallback = "live-with-file-fallback"
+)
+
+// Sources returns all valid values of FactoryConfig.Source.
+func Sources() []string {
+	return []string{SourceLive, SourceFile, SourceLiveWithFileFallback}
+}
+
+// catalogCacheKey is the cache key of the single client used with SourceFile.
+// Credentials cache keys are never empty, so it doesn't clash with them.
+const catalogCacheKey = ""
+
+type FactoryConfig struct {
+	// Credentials looks up the Azure credentials of the clusters. It is
+	// optional, when nil they are looked up on every call.
+	Credentials *capzcredentials.Resolver
+	Logger      micrologger.Logger
+
+	// TTL is how long the SKUs are cached, see Config.TTL.
+	TTL time.Duration
+
+	// Source is where the SKUs are listed from, one of Sources(). Defaults to
+	// SourceLive.
+	Source string
+	// CatalogFile is the catalog used by SourceFile and
+	// SourceLiveWithFileFallback, see Catalog.
+	CatalogFile string
+}
+
+// FactoryImpl creates and caches a VMSKU client per Azure credentials, see
+// capzcredentials.AzureCredentials.CacheKey. Clients are dropped when the
+// credentials resolver drops their credentials, e.g. because the Secret they
+// were read from changed. It is safe for concurrent use.
 type FactoryImpl struct {
-	cache  map[string]*VMSKU
-	logger micrologger.Logger
+	credentials *capzcredentials.Resolver
+	logger      micrologger.Logger
+	ttl         time.Duration
+	source      string
+	catalog     *FileAPI
+
+	mutex sync.RWMutex
+	cache map[string]*VMSKU
 }
 
-func NewFactory(logger micrologger.Logger) (*FactoryImpl, error) {
-	return &FactoryImpl{
-		cache:  make(map[string]*VMSKU),
-		logger: logger,
-	}, nil
+func NewFactory(config FactoryConfig) (*FactoryImpl, error) {
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+	if config.TTL < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.TTL must not be negative", config)
+	}
+	if config.Source == "" {
+		config.Source = SourceLive
+	}
+
+	var catalog *FileAPI
+	switch config.Source {
+	case SourceLive:
+	case SourceFile, SourceLiveWithFileFallback:
+		if config.CatalogFile == "" {
+			return nil, microerror.Maskf(invalidConfigError, "%T.CatalogFile must not be empty with source %#q", config, config.Source)
+		}
+
+		var err error
+		catalog, err = NewFileAPI(FileAPIConfig{Path: config.CatalogFile})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	default:
+		return nil, microerror.Maskf(invalidConfigError, "%T.Source must be one of %v, got %#q", config, Sources(), config.Source)
+	}
+
+	f := &FactoryImpl{
+		cache:       make(map[string]*VMSKU),
+		credentials: config.Credentials,
+		logger:      config.Logger,
+		ttl:         config.TTL,
+		source:      config.Source,
+		catalog:     catalog,
+	}
+	f.credentials.OnInvalidate(f.drop)
+
+	return f, nil
 }
 
 func (f *FactoryImpl) GetClient(ctx context.Context, ctrlClient client.Client, objectMeta v1.ObjectMeta) (*VMSKU, error) {
-	azureCredentials, err := capzcredentials.GetAzureCredentialsFromMetadata(ctx, ctrlClient, objectMeta)
+	// The catalog is the same for all subscriptions, so there is a single
+	// client and the credentials are not looked up.
+	if f.source == SourceFile {
+		vmsku, hit := f.cached(catalogCacheKey)
+		if hit {
+			return vmsku, nil
+		}
+
+		f.logger.Debugf(ctx, "Initializing VMSKU client for catalog file")
+
+		vmsku, err := New(Config{
+			Azure:  f.catalog,
+			Logger: f.logger,
+			TTL:    f.ttl,
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		return f.store(catalogCacheKey, vmsku), nil
+	}
+
+	azureCredentials, err := f.credentials.GetAzureCredentials(ctx, ctrlClient, objectMeta)
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	if vmsku, hit := f.cache[azureCredentials.SubscriptionID]; hit {
+	key := azureCredentials.CacheKey()
+	vmsku, hit := f.cached(key)
+	if hit {
 		f.logger.Debugf(ctx, "VMSKU client found in cache for subscription %q", azureCredentials.SubscriptionID)
 		return vmsku, nil
 	}
@@ -40,8 +147,7 @@ func (f *FactoryImpl) GetClient(ctx context.Context, ctrlClient client.Client, o
 
 	var resourceSkusClient compute.ResourceSkusClient
 	{
-		settings := auth.NewClientCredentialsConfig(azureCredentials.ClientID, azureCredentials.ClientSecret, azureCredentials.TenantID)
-		authorizer, err := settings.Authorizer()
+		authorizer, err := azureCredentials.Authorizer()
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -49,15 +155,53 @@ func (f *FactoryImpl) GetClient(ctx context.Context, ctrlClient client.Client, o
 		resourceSkusClient.Client.Authorizer = authorizer
 	}
 
-	vmsku, err := New(Config{
-		Azure:  NewAzureAPI(AzureConfig{ResourceSkuClient: &resourceSkusClient}),
-		Logger: f.logger,
+	// The catalog is only a stand-in for the SKUs of this subscription, so
+	// the restrictions of another subscription are dropped from it.
+	var fallback API
+	if f.source == SourceLiveWithFileFallback {
+		fallback = f.catalog.ForSubscription(azureCredentials.SubscriptionID)
+	}
+
+	vmsku, err = New(Config{
+		Azure:    NewAzureAPI(AzureConfig{ResourceSkuClient: &resourceSkusClient}),
+		Fallback: fallback,
+		Logger:   f.logger,
+		TTL:      f.ttl,
 	})
 	if err != nil {
 		return nil, microerror.Mask(err)
 	}
 
-	f.cache[azureCredentials.SubscriptionID] = vmsku
+	return f.store(key, vmsku), nil
+}
+
+func (f *FactoryImpl) cached(key string) (*VMSKU, bool) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	vmsku, hit := f.cache[key]
+	return vmsku, hit
+}
+
+// store caches the given client and returns it. Another request may have
+// created a client for the same key in the meantime. That one is kept and
+// returned instead, so its cached SKUs are not lost.
+func (f *FactoryImpl) store(key string, vmsku *VMSKU) *VMSKU {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if cached, hit := f.cache[key]; hit {
+		return cached
+	}
+	f.cache[key] = vmsku
+
+	return vmsku
+}
+
+// drop removes the client of the given credentials from the cache.
+func (f *FactoryImpl) drop(credentials capzcredentials.AzureCredentials) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
 
-	return vmsku, nil
+	delete(f.cache, credentials.CacheKey())
 }
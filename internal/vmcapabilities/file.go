@@ -0,0 +1,75 @@
+package vmcapabilities
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/giantswarm/microerror"
+)
+
+type FileAPIConfig struct {
+	// Path is the catalog file, see Catalog. It is usually mounted from a
+	// ConfigMap created with the export-sku-catalog command.
+	Path string
+	// SubscriptionID is the subscription the SKUs are listed for. The
+	// restrictions of a catalog exported for another subscription, or for an
+	// unknown one, don't apply to it and are dropped. It is optional, when
+	// empty the restrictions are kept.
+	SubscriptionID string
+}
+
+// FileAPI lists the SKUs from a catalog file instead of the Azure API. The
+// file is read on every List call, so changes of a mounted ConfigMap are
+// picked up with the next refresh of the cached SKUs.
+type FileAPI struct {
+	path           string
+	subscriptionID string
+}
+
+func NewFileAPI(config FileAPIConfig) (*FileAPI, error) {
+	if config.Path == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Path must not be empty", config)
+	}
+
+	// Fail early on a missing or invalid file, instead of with the first
+	// admission request.
+	_, err := LoadCatalog(config.Path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return &FileAPI{
+		path:           config.Path,
+		subscriptionID: config.SubscriptionID,
+	}, nil
+}
+
+// ForSubscription returns a FileAPI reading the same catalog file for the
+// given subscription, see FileAPIConfig.SubscriptionID.
+func (f *FileAPI) ForSubscription(subscriptionID string) *FileAPI {
+	return &FileAPI{
+		path:           f.path,
+		subscriptionID: subscriptionID,
+	}
+}
+
+func (f *FileAPI) List(_ context.Context, location string) (map[string]compute.ResourceSku, error) {
+	catalog, err := LoadCatalog(f.path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	skus, ok := catalog.SKUs(location)
+	if !ok {
+		return nil, microerror.Maskf(locationNotInCatalogError, "location %#q is not in catalog file %#q", location, f.path)
+	}
+
+	if f.subscriptionID != "" && catalog.SubscriptionID != f.subscriptionID {
+		for name, sku := range skus {
+			sku.Restrictions = &[]compute.ResourceSkuRestrictions{}
+			skus[name] = sku
+		}
+	}
+
+	return skus, nil
+}
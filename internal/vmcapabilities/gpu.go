@@ -0,0 +1,81 @@
+package vmcapabilities
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+)
+
+// gpuVMSizePrefixes are the prefixes of the N-series VM families with GPUs,
+// e.g. Standard_NC6s_v3 or Standard_ND40rs_v2. Other N-series families, like
+// the NP family with FPGAs, don't have GPUs.
+var gpuVMSizePrefixes = []string{
+	"standard_nc",
+	"standard_nd",
+	"standard_ng",
+	"standard_nv",
+}
+
+// IsGPUVMSize returns true when the VM size belongs to a GPU family. It only
+// looks at the name, so it also works for VM sizes that are not offered in a
+// location and which capabilities can't be looked up.
+func IsGPUVMSize(vmType string) bool {
+	vmType = strings.ToLower(vmType)
+	for _, prefix := range gpuVMSizePrefixes {
+		if strings.HasPrefix(vmType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GPUs returns the number of GPUs of the VM size. VM sizes without GPUs don't
+// have the GPUs capability, so 0 is returned for them.
+func (v *VMSKU) GPUs(ctx context.Context, location string, vmType string) (int, error) {
+	capability, err := v.getCapability(ctx, location, vmType, CapabilityGPUs)
+	if err != nil {
+		return 0, microerror.Mask(err)
+	}
+
+	if capability == nil {
+		return 0, nil
+	}
+
+	gpus, err := strconv.Atoi(*capability)
+	if err != nil {
+		return 0, microerror.Mask(invalidUpstreamResponseError)
+	}
+
+	return gpus, nil
+}
+
+// GPUVMSizes returns the names of the VM sizes with GPUs offered in the
+// location, sorted by name.
+func (v *VMSKU) GPUVMSizes(ctx context.Context, location string) ([]string, error) {
+	if location == "" {
+		return nil, microerror.Maskf(invalidRequestError, "location can't be empty")
+	}
+
+	skus, err := v.getSKUs(ctx, location)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var vmSizes []string
+	for name := range skus {
+		gpus, err := v.GPUs(ctx, location, name)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if gpus > 0 {
+			vmSizes = append(vmSizes, name)
+		}
+	}
+	sort.Strings(vmSizes)
+
+	return vmSizes, nil
+}
@@ -0,0 +1,53 @@
+package vmcapabilities
+
+import (
+	"testing"
+)
+
+func TestIsGPUVMSize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		vmType   string
+		expected bool
+	}{
+		{
+			name:     "case 0: NC family",
+			vmType:   "Standard_NC6s_v3",
+			expected: true,
+		},
+		{
+			name:     "case 1: ND family",
+			vmType:   "Standard_ND40rs_v2",
+			expected: true,
+		},
+		{
+			name:     "case 2: NV family, lower case",
+			vmType:   "standard_nv12s_v3",
+			expected: true,
+		},
+		{
+			name:     "case 3: NG family",
+			vmType:   "Standard_NG8ads_V620_v1",
+			expected: true,
+		},
+		{
+			name:     "case 4: NP family has FPGAs",
+			vmType:   "Standard_NP10s",
+			expected: false,
+		},
+		{
+			name:     "case 5: D family",
+			vmType:   "Standard_D4s_v3",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := IsGPUVMSize(tc.vmType)
+			if result != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package vmcapabilities
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/giantswarm/microerror"
+)
+
+// Restrictions describes where a VM size can't be used by the subscription
+// that the SKUs were listed for.
+type Restrictions struct {
+	// Location is true when the VM size can't be used in the location at all,
+	// e.g. because it is NotAvailableForSubscription.
+	Location bool
+	// Zones are the availability zones of the location in which the VM size
+	// can't be used.
+	Zones []string
+	// Reasons are the reason codes of the restrictions.
+	Reasons []string
+}
+
+// IsZoneRestricted returns true when the VM size can't be used in the given
+// availability zone.
+func (r Restrictions) IsZoneRestricted(zone string) bool {
+	for _, z := range r.Zones {
+		if z == zone {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Restrictions returns the restrictions of the VM size in the given location.
+func (v *VMSKU) Restrictions(ctx context.Context, location string, vmType string) (Restrictions, error) {
+	sku, err := v.getSKU(ctx, location, vmType)
+	if err != nil {
+		return Restrictions{}, microerror.Mask(err)
+	}
+
+	return restrictions(sku, location), nil
+}
+
+func restrictions(sku compute.ResourceSku, location string) Restrictions {
+	var result Restrictions
+	if sku.Restrictions == nil {
+		return result
+	}
+
+	for _, restriction := range *sku.Restrictions {
+		if !restrictionAppliesTo(restriction, location) {
+			continue
+		}
+
+		switch restriction.Type {
+		case compute.Location:
+			result.Location = true
+		case compute.Zone:
+			if restriction.RestrictionInfo != nil && restriction.RestrictionInfo.Zones != nil {
+				result.Zones = append(result.Zones, *restriction.RestrictionInfo.Zones...)
+			}
+		default:
+			continue
+		}
+
+		if restriction.ReasonCode != "" {
+			result.Reasons = append(result.Reasons, string(restriction.ReasonCode))
+		}
+	}
+
+	return result
+}
+
+// restrictionAppliesTo returns true when the restriction is for the given
+// location. Restrictions list their locations both in Values and in
+// RestrictionInfo.Locations, depending on the type.
+func restrictionAppliesTo(restriction compute.ResourceSkuRestrictions, location string) bool {
+	var locations []string
+	if restriction.Values != nil {
+		locations = append(locations, *restriction.Values...)
+	}
+	if restriction.RestrictionInfo != nil && restriction.RestrictionInfo.Locations != nil {
+		locations = append(locations, *restriction.RestrictionInfo.Locations...)
+	}
+
+	for _, l := range locations {
+		if strings.EqualFold(l, location) {
+			return true
+		}
+	}
+
+	return false
+}
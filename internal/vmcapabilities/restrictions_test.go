@@ -0,0 +1,121 @@
+package vmcapabilities
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/giantswarm/micrologger"
+)
+
+type stubAPI map[string]compute.ResourceSku
+
+func (s stubAPI) List(_ context.Context, _ string) (map[string]compute.ResourceSku, error) {
+	return s, nil
+}
+
+func TestRestrictions(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		restrictions         []compute.ResourceSkuRestrictions
+		expectedRestrictions Restrictions
+		expectedAZs          []string
+	}{
+		{
+			name:                 "case 0: no restrictions",
+			expectedRestrictions: Restrictions{},
+			expectedAZs:          []string{"1", "2", "3"},
+		},
+		{
+			name: "case 1: not available for subscription",
+			restrictions: []compute.ResourceSkuRestrictions{
+				{
+					Type:       compute.Location,
+					Values:     &[]string{"westeurope"},
+					ReasonCode: compute.NotAvailableForSubscription,
+				},
+			},
+			expectedRestrictions: Restrictions{
+				Location: true,
+				Reasons:  []string{"NotAvailableForSubscription"},
+			},
+			expectedAZs: []string{"1", "2", "3"},
+		},
+		{
+			name: "case 2: restricted zone",
+			restrictions: []compute.ResourceSkuRestrictions{
+				{
+					Type:   compute.Zone,
+					Values: &[]string{"westeurope"},
+					RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
+						Locations: &[]string{"westeurope"},
+						Zones:     &[]string{"2"},
+					},
+					ReasonCode: compute.NotAvailableForSubscription,
+				},
+			},
+			expectedRestrictions: Restrictions{
+				Zones:   []string{"2"},
+				Reasons: []string{"NotAvailableForSubscription"},
+			},
+			expectedAZs: []string{"1", "3"},
+		},
+		{
+			name: "case 3: restriction in another location",
+			restrictions: []compute.ResourceSkuRestrictions{
+				{
+					Type:       compute.Location,
+					Values:     &[]string{"germanywestcentral"},
+					ReasonCode: compute.NotAvailableForSubscription,
+				},
+			},
+			expectedRestrictions: Restrictions{},
+			expectedAZs:          []string{"1", "2", "3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := micrologger.New(micrologger.Config{})
+
+			sku := compute.ResourceSku{
+				Name: to.StringPtr("Standard_D4s_v3"),
+				LocationInfo: &[]compute.ResourceSkuLocationInfo{
+					{
+						Location: to.StringPtr("westeurope"),
+						Zones:    &[]string{"1", "2", "3"},
+					},
+				},
+			}
+			if tc.restrictions != nil {
+				sku.Restrictions = &tc.restrictions
+			}
+
+			vmsku, err := New(Config{
+				Azure:  stubAPI{"Standard_D4s_v3": sku},
+				Logger: logger,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			restrictions, err := vmsku.Restrictions(context.Background(), "westeurope", "Standard_D4s_v3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(restrictions, tc.expectedRestrictions) {
+				t.Fatalf("expected restrictions %#v, got %#v", tc.expectedRestrictions, restrictions)
+			}
+
+			azs, err := vmsku.SupportedAZs(context.Background(), "westeurope", "Standard_D4s_v3")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(azs, tc.expectedAZs) {
+				t.Fatalf("expected zones %v, got %v", tc.expectedAZs, azs)
+			}
+		})
+	}
+}
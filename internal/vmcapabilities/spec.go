@@ -8,8 +8,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// API lists the resource SKUs available in a location, by name.
 type API interface {
-	List(ctx context.Context, filter string) (map[string]compute.ResourceSku, error)
+	List(ctx context.Context, location string) (map[string]compute.ResourceSku, error)
 }
 
 type Factory interface {
@@ -6,38 +6,102 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
+
+	"github.com/giantswarm/azure-admission-controller/pkg/metrics"
 )
 
 const (
 	// CapabilitySupported is the value returned by this API from Azure when the capability is supported
 	CapabilitySupported = "True"
 
-	CapabilityAcceleratedNetworking = "AcceleratedNetworkingEnabled"
-	CapabilityPremiumIO             = "PremiumIO"
+	CapabilityAcceleratedNetworking    = "AcceleratedNetworkingEnabled"
+	CapabilityEphemeralOSDiskSupported = "EphemeralOSDiskSupported"
+	CapabilityGPUs                     = "GPUs"
+	CapabilityPremiumIO                = "PremiumIO"
 
 	// For internal use only.
-	capabilityMemory = "MemoryGB"
-	capabilityCPUs   = "vCPUs"
+	capabilityCachedDiskBytes     = "CachedDiskBytes"
+	capabilityMaxResourceVolumeMB = "MaxResourceVolumeMB"
+	capabilityMemory              = "MemoryGB"
+	capabilityCPUs                = "vCPUs"
+)
+
+const (
+	// DefaultTTL is how long the SKUs of a location are served before they
+	// are refreshed, when no TTL is configured.
+	DefaultTTL = time.Hour
+
+	// refreshTimeout bounds a single SKU list call. Refreshes are not bound to
+	// the request that triggered them, so that they are not cancelled when the
+	// request is done.
+	refreshTimeout = 2 * time.Minute
+
+	// maxRetryInterval bounds how long failed SKU list calls are not retried.
+	// They are retried at most once per TTL, when it is shorter.
+	maxRetryInterval = 5 * time.Minute
 )
 
 type Config struct {
 	Azure  API
 	Logger micrologger.Logger
+
+	// Fallback lists the SKUs of a location when Azure fails and no SKUs were
+	// listed from it yet, usually a FileAPI. Stale SKUs listed from Azure are
+	// preferred over it. It is optional.
+	Fallback API
+
+	// TTL is how long the SKUs of a location are served before they are
+	// refreshed. Defaults to DefaultTTL.
+	TTL time.Duration
 }
 
+// VMSKU provides the capabilities of the VM SKUs, which are cached per
+// location. It is safe for concurrent use.
+//
+// Once the TTL of the cached SKUs expires, they are refreshed in the
+// background and the stale SKUs are served until the refresh is done.
+// Concurrent refreshes of the same location are deduplicated, so there is at
+// most one SKU list call per location in flight. Failed list calls are retried
+// at most once per TTL or maxRetryInterval, whichever is shorter, meanwhile
+// the stale SKUs or the last error are returned. SKUs listed from the fallback
+// are served until Azure works again, and don't delay retrying it.
 type VMSKU struct {
-	azure     API
-	initMutex sync.Mutex
-	logger    micrologger.Logger
-	skus      map[string]cache
+	azure         API
+	fallback      API
+	logger        micrologger.Logger
+	ttl           time.Duration
+	retryInterval time.Duration
+
+	mutex     sync.RWMutex
+	skus      map[string]snapshot
+	refreshes map[string]*refresh
 }
 
 type cache map[string]compute.ResourceSku
 
+// snapshot is the SKUs of a location at the time they were listed.
+type snapshot struct {
+	skus     cache
+	listedAt time.Time
+	// attemptedAt is the time of the last list call, whether it failed or
+	// not.
+	attemptedAt time.Time
+	// err is the error of the last list call when no SKUs were listed yet,
+	// neither from Azure nor from the fallback.
+	err error
+}
+
+// refresh is a SKU list call in flight. done is closed once it is finished.
+type refresh struct {
+	done chan struct{}
+	err  error
+}
+
 func New(config Config) (*VMSKU, error) {
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
@@ -45,10 +109,26 @@ func New(config Config) (*VMSKU, error) {
 	if config.Azure == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Azure must not be empty", config)
 	}
+	if config.TTL < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.TTL must not be negative", config)
+	}
+	if config.TTL == 0 {
+		config.TTL = DefaultTTL
+	}
+
+	retryInterval := config.TTL
+	if retryInterval > maxRetryInterval {
+		retryInterval = maxRetryInterval
+	}
+
 	return &VMSKU{
-		logger: config.Logger,
-		azure:  config.Azure,
-		skus:   make(map[string]cache),
+		logger:        config.Logger,
+		azure:         config.Azure,
+		fallback:      config.Fallback,
+		ttl:           config.TTL,
+		retryInterval: retryInterval,
+		skus:          make(map[string]snapshot),
+		refreshes:     make(map[string]*refresh),
 	}, nil
 }
 
@@ -70,6 +150,22 @@ func (v *VMSKU) CPUs(ctx context.Context, location string, vmType string) (int,
 	return 0, microerror.Mask(invalidUpstreamResponseError)
 }
 
+// Family returns the Azure VM family of the VM size, e.g. standardDSv3Family.
+func (v *VMSKU) Family(ctx context.Context, location string, vmType string) (string, error) {
+	sku, err := v.getSKU(ctx, location, vmType)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if sku.Family == nil {
+		return "", nil
+	}
+
+	return *sku.Family, nil
+}
+
+// HasCapability returns true when the VM size has the given capability. It
+// doesn't take restrictions into account, see Restrictions.
 func (v *VMSKU) HasCapability(ctx context.Context, location string, vmType string, name string) (bool, error) {
 	capability, err := v.getCapability(ctx, location, vmType, name)
 	if err != nil {
@@ -100,16 +196,24 @@ func (v *VMSKU) Memory(ctx context.Context, location string, vmType string) (int
 	return 0, microerror.Mask(invalidUpstreamResponseError)
 }
 
+// SupportedAZs returns the availability zones in which the VM size can be
+// used. Zones that are restricted for the subscription are left out.
 func (v *VMSKU) SupportedAZs(ctx context.Context, location string, vmType string) ([]string, error) {
 	sku, err := v.getSKU(ctx, location, vmType)
 	if err != nil {
 		return []string{}, nil
 	}
 
+	r := restrictions(sku, location)
+
 	var azs []string
 	for _, l := range *sku.LocationInfo {
 		if l.Zones != nil {
-			azs = append(azs, *l.Zones...)
+			for _, zone := range *l.Zones {
+				if !r.IsZoneRestricted(zone) {
+					azs = append(azs, zone)
+				}
+			}
 		}
 	}
 
@@ -143,13 +247,12 @@ func (v *VMSKU) getSKU(ctx context.Context, location string, vmType string) (com
 		return compute.ResourceSku{}, microerror.Maskf(invalidRequestError, "vmType can't be empty")
 	}
 
-	if _, ok := v.skus[location]; !ok {
-		err := v.initCache(ctx, location)
-		if err != nil {
-			return compute.ResourceSku{}, microerror.Mask(err)
-		}
+	skus, err := v.getSKUs(ctx, location)
+	if err != nil {
+		return compute.ResourceSku{}, microerror.Mask(err)
 	}
-	vmsku, found := v.skus[location][vmType]
+
+	vmsku, found := skus[vmType]
 	if !found {
 		return compute.ResourceSku{}, microerror.Maskf(skuNotFoundError, vmType)
 	}
@@ -157,19 +260,142 @@ func (v *VMSKU) getSKU(ctx context.Context, location string, vmType string) (com
 	return vmsku, nil
 }
 
+// getSKUs returns the cached SKUs of the given location. The first call for a
+// location waits for the SKUs to be listed. Later calls return the cached SKUs
+// right away, and refresh them in the background once they are expired. Until
+// the retry interval since a failed list call has passed, the stale SKUs, or
+// the error when there are none, are returned without listing them again.
+func (v *VMSKU) getSKUs(ctx context.Context, location string) (cache, error) {
+	v.mutex.RLock()
+	s, ok := v.skus[location]
+	v.mutex.RUnlock()
+
+	// Only failed list calls, made after the last successful one, delay
+	// the next one.
+	retry := !s.attemptedAt.After(s.listedAt) || time.Since(s.attemptedAt) > v.retryInterval
+
+	if ok && s.err == nil {
+		if time.Since(s.listedAt) > v.ttl && retry {
+			v.startRefresh(location)
+		}
+
+		return s.skus, nil
+	}
+
+	if ok && !retry {
+		return nil, microerror.Mask(s.err)
+	}
+
+	r := v.startRefresh(location)
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return nil, microerror.Mask(ctx.Err())
+	}
+
+	if r.err != nil {
+		return nil, microerror.Mask(r.err)
+	}
+
+	v.mutex.RLock()
+	s = v.skus[location]
+	v.mutex.RUnlock()
+
+	return s.skus, nil
+}
+
+// startRefresh starts listing the SKUs of the given location, unless it is
+// already in progress, and returns the refresh in flight.
+func (v *VMSKU) startRefresh(location string) *refresh {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if r, ok := v.refreshes[location]; ok {
+		return r
+	}
+
+	r := &refresh{
+		done: make(chan struct{}),
+	}
+	v.refreshes[location] = r
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+
+		r.err = v.initCache(ctx, location)
+
+		v.mutex.Lock()
+		delete(v.refreshes, location)
+		v.mutex.Unlock()
+
+		close(r.done)
+	}()
+
+	return r
+}
+
 func (v *VMSKU) initCache(ctx context.Context, location string) error {
-	v.initMutex.Lock()
-	defer v.initMutex.Unlock()
-	filter := fmt.Sprintf("location eq '%s'", location)
-	v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("Initializing cache for location %s with filter: %s", location, filter))
-	skus, err := v.azure.List(ctx, filter)
+	v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("Initializing cache for location %s", location))
+
+	start := time.Now()
+	skus, err := v.azure.List(ctx, location)
+	metrics.ObserveAzureSKUList(location, start, err)
 	if err != nil {
-		return microerror.Mask(err)
+		v.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("Failed to list SKUs for location %s", location), "stack", microerror.JSON(err))
+
+		return microerror.Mask(v.keepCache(ctx, location, start, err))
 	}
 
-	v.skus[location] = skus
+	v.mutex.Lock()
+	v.skus[location] = snapshot{
+		skus:        skus,
+		listedAt:    start,
+		attemptedAt: start,
+	}
+	v.mutex.Unlock()
 
 	v.logger.LogCtx(ctx, "level", "debug", "message", fmt.Sprintf("Initialized cache. Number of SKUs in cache for location %s: '%d'", location, len(skus)))
 
 	return nil
 }
+
+// keepCache records the failed list call of the given location. The stale
+// SKUs, if any, are kept and served until the next refresh. Only when no SKUs
+// were listed from Azure yet, they are listed from the fallback. Their listing
+// time is left empty, so that Azure is retried after the retry interval. The
+// given error is returned when no SKUs can be served.
+func (v *VMSKU) keepCache(ctx context.Context, location string, attemptedAt time.Time, err error) error {
+	// Refreshes of a location are deduplicated, so no one else writes its
+	// snapshot meanwhile.
+	v.mutex.RLock()
+	s := v.skus[location]
+	v.mutex.RUnlock()
+
+	s.attemptedAt = attemptedAt
+	if s.listedAt.IsZero() && v.fallback != nil {
+		skus, fallbackErr := v.fallback.List(ctx, location)
+		if fallbackErr != nil {
+			v.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("Failed to list SKUs for location %s from the fallback", location), "stack", microerror.JSON(fallbackErr))
+		} else {
+			v.logger.LogCtx(ctx, "level", "warning", "message", fmt.Sprintf("Serving SKUs for location %s from the fallback", location))
+			metrics.SKUCatalogFallback(location)
+
+			s.skus = skus
+			s.err = nil
+		}
+	}
+	if s.skus == nil && s.listedAt.IsZero() {
+		s.err = err
+	}
+
+	v.mutex.Lock()
+	v.skus[location] = s
+	v.mutex.Unlock()
+
+	if s.err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package vmcapabilities
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/giantswarm/micrologger"
+)
+
+// blockingAPI counts the SKU list calls and blocks them until release is
+// closed.
+type blockingAPI struct {
+	calls   int32
+	release chan struct{}
+	skus    map[string]compute.ResourceSku
+}
+
+func (a *blockingAPI) List(ctx context.Context, _ string) (map[string]compute.ResourceSku, error) {
+	atomic.AddInt32(&a.calls, 1)
+	<-a.release
+
+	return a.skus, nil
+}
+
+func newTestSKUs(cpus string) map[string]compute.ResourceSku {
+	return map[string]compute.ResourceSku{
+		"Standard_D4s_v3": {
+			Name: to.StringPtr("Standard_D4s_v3"),
+			Capabilities: &[]compute.ResourceSkuCapabilities{
+				{Name: to.StringPtr(capabilityCPUs), Value: to.StringPtr(cpus)},
+			},
+		},
+	}
+}
+
+func TestConcurrentCallsAreDeduplicated(t *testing.T) {
+	logger, _ := micrologger.New(micrologger.Config{})
+	api := &blockingAPI{
+		release: make(chan struct{}),
+		skus:    newTestSKUs("4"),
+	}
+	vmsku, err := New(Config{Azure: api, Logger: logger})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cpus, err := vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+			if err != nil {
+				t.Error(err)
+			}
+			if cpus != 4 {
+				t.Errorf("expected 4 CPUs, got %d", cpus)
+			}
+		}()
+	}
+
+	// Give the goroutines some time to wait for the SKU list call.
+	time.Sleep(50 * time.Millisecond)
+	close(api.release)
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&api.calls); calls != 1 {
+		t.Fatalf("expected 1 SKU list call, got %d", calls)
+	}
+}
+
+func TestStaleSKUsAreServedDuringRefresh(t *testing.T) {
+	logger, _ := micrologger.New(micrologger.Config{})
+	api := &blockingAPI{
+		release: make(chan struct{}),
+		skus:    newTestSKUs("4"),
+	}
+	vmsku, err := New(Config{Azure: api, Logger: logger, TTL: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fill the cache.
+	close(api.release)
+	_, err = vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Block the refresh, and let the cached SKUs expire.
+	api.release = make(chan struct{})
+	api.skus = newTestSKUs("8")
+	time.Sleep(10 * time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		cpus, err := vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cpus != 4 {
+			t.Fatalf("expected stale 4 CPUs, got %d", cpus)
+		}
+	}
+
+	close(api.release)
+
+	// Wait for the refresh to finish.
+	for i := 0; i < 100; i++ {
+		cpus, err := vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cpus == 8 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("expected SKUs to be refreshed")
+}
+
+// flakyAPI counts the SKU list calls, and fails them while failing is set.
+type flakyAPI struct {
+	calls   int32
+	failing int32
+	skus    map[string]compute.ResourceSku
+}
+
+func (a *flakyAPI) List(ctx context.Context, _ string) (map[string]compute.ResourceSku, error) {
+	atomic.AddInt32(&a.calls, 1)
+	if atomic.LoadInt32(&a.failing) == 1 {
+		return nil, errors.New("azure API is not reachable")
+	}
+
+	return a.skus, nil
+}
+
+func TestFailedRefreshesAreRetriedAfterInterval(t *testing.T) {
+	logger, _ := micrologger.New(micrologger.Config{})
+	api := &flakyAPI{
+		skus: newTestSKUs("4"),
+	}
+	vmsku, err := New(Config{Azure: api, Logger: logger, TTL: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vmsku.retryInterval = time.Hour
+
+	// Fill the cache, let it expire, and fail the refresh.
+	_, err = vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	atomic.StoreInt32(&api.failing, 1)
+	time.Sleep(10 * time.Millisecond)
+
+	_, err = vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForRefreshes(t, vmsku)
+
+	// The stale SKUs are served without listing them again.
+	for i := 0; i < 10; i++ {
+		cpus, err := vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cpus != 4 {
+			t.Fatalf("expected stale 4 CPUs, got %d", cpus)
+		}
+	}
+	waitForRefreshes(t, vmsku)
+
+	if calls := atomic.LoadInt32(&api.calls); calls != 2 {
+		t.Fatalf("expected 2 SKU list calls, got %d", calls)
+	}
+
+	// Once the retry interval passed, the SKUs are listed again.
+	vmsku.retryInterval = 0
+	_, err = vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForRefreshes(t, vmsku)
+
+	if calls := atomic.LoadInt32(&api.calls); calls != 3 {
+		t.Fatalf("expected 3 SKU list calls, got %d", calls)
+	}
+}
+
+func TestFailedInitialListIsRetriedAfterInterval(t *testing.T) {
+	logger, _ := micrologger.New(micrologger.Config{})
+	api := &flakyAPI{
+		failing: 1,
+		skus:    newTestSKUs("4"),
+	}
+	vmsku, err := New(Config{Azure: api, Logger: logger})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The error is returned without listing the SKUs again.
+	for i := 0; i < 3; i++ {
+		_, err = vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	}
+
+	if calls := atomic.LoadInt32(&api.calls); calls != 1 {
+		t.Fatalf("expected 1 SKU list call, got %d", calls)
+	}
+
+	// Once the retry interval passed, the SKUs are listed again.
+	atomic.StoreInt32(&api.failing, 0)
+	vmsku.retryInterval = 0
+
+	cpus, err := vmsku.CPUs(context.Background(), "westeurope", "Standard_D4s_v3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpus != 4 {
+		t.Fatalf("expected 4 CPUs, got %d", cpus)
+	}
+}
+
+// waitForRefreshes waits until there is no refresh in flight.
+func waitForRefreshes(t *testing.T, v *VMSKU) {
+	for i := 0; i < 100; i++ {
+		v.mutex.RLock()
+		n := len(v.refreshes)
+		v.mutex.RUnlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("expected refreshes to finish")
+}
@@ -3,13 +3,19 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
 	"github.com/dyson/certman"
 	expcapz "github.com/giantswarm/apiextensions/v6/pkg/apis/capzexp/v1alpha3"
 	corev1alpha1 "github.com/giantswarm/apiextensions/v6/pkg/apis/core/v1alpha1"
@@ -19,6 +25,7 @@ import (
 	"github.com/giantswarm/micrologger"
 	securityv1alpha1 "github.com/giantswarm/organization-operator/api/v1alpha1"
 	releasev1alpha1 "github.com/giantswarm/release-operator/v3/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/client-go/rest"
 	restclient "k8s.io/client-go/rest"
 	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
@@ -27,13 +34,27 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
 
+	"github.com/giantswarm/azure-admission-controller/internal/azurequota"
+	"github.com/giantswarm/azure-admission-controller/internal/capzcredentials"
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/maintenance"
+	"github.com/giantswarm/azure-admission-controller/internal/quota"
+	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
+	"github.com/giantswarm/azure-admission-controller/internal/sizingpolicy"
 	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
 	"github.com/giantswarm/azure-admission-controller/pkg/app"
 	"github.com/giantswarm/azure-admission-controller/pkg/config"
 	"github.com/giantswarm/azure-admission-controller/pkg/project"
 )
 
+const (
+	// configReloadInterval is how often the config files mounted from ConfigMaps
+	// are read again.
+	configReloadInterval = 30 * time.Second
+)
+
 func main() {
 	err := mainError()
 	if err != nil {
@@ -47,6 +68,10 @@ func mainError() error {
 		return microerror.Mask(err)
 	}
 
+	if cfg.Command == config.CommandExportSKUCatalog {
+		return exportSKUCatalog(cfg.ExportSKUCatalog)
+	}
+
 	var newLogger micrologger.Logger
 	{
 		newLogger, err = micrologger.New(micrologger.Config{})
@@ -124,14 +149,148 @@ func mainError() error {
 	// Here we register our endpoints.
 	handler := http.NewServeMux()
 	handler.HandleFunc("/healthz", healthCheck)
+	handler.Handle("/metrics", promhttp.Handler())
 
-	vmcapsFactory, err := vmcapabilities.NewFactory(newLogger)
-	if err != nil {
-		return microerror.Mask(err)
+	var credentialsResolver *capzcredentials.Resolver
+	{
+		c := capzcredentials.ResolverConfig{
+			Logger: newLogger,
+		}
+		credentialsResolver, err = capzcredentials.NewResolver(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// Cache the credentials per cluster, and drop them when the
+		// objects they were read from change.
+		err = credentialsResolver.Watch(context.Background(), ctrlCache)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	var vmcapsFactory *vmcapabilities.FactoryImpl
+	{
+		c := vmcapabilities.FactoryConfig{
+			Credentials: credentialsResolver,
+			Logger:      newLogger,
+			TTL:         cfg.VMSKUCacheTTL,
+
+			Source:      cfg.VMSKUSource,
+			CatalogFile: cfg.VMSKUCatalogFile,
+		}
+		vmcapsFactory, err = vmcapabilities.NewFactory(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	var enforcementRegistry *enforcement.Registry
+	{
+		c := enforcement.RegistryConfig{
+			Logger: newLogger,
+			File:   cfg.EnforcementConfigFile,
+		}
+		enforcementRegistry, err = enforcement.NewRegistry(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// Pick up changes of the mounted config file without restarting.
+		go enforcementRegistry.Watch(context.Background(), configReloadInterval)
+	}
+
+	var sizingPolicy *sizingpolicy.Registry
+	{
+		c := sizingpolicy.RegistryConfig{
+			Logger: newLogger,
+			File:   cfg.SizingPolicyFile,
+		}
+		sizingPolicy, err = sizingpolicy.NewRegistry(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// Pick up changes of the mounted policy file without restarting.
+		go sizingPolicy.Watch(context.Background(), configReloadInterval)
+	}
+
+	var quotaRegistry *quota.Registry
+	{
+		c := quota.RegistryConfig{
+			Logger: newLogger,
+			File:   cfg.QuotaFile,
+		}
+		quotaRegistry, err = quota.NewRegistry(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// Pick up changes of the mounted quota file without restarting.
+		go quotaRegistry.Watch(context.Background(), configReloadInterval)
+	}
+
+	var releasePolicy *releaseversion.PolicyRegistry
+	{
+		c := releaseversion.PolicyRegistryConfig{
+			Logger: newLogger,
+			File:   cfg.ReleasePolicyFile,
+		}
+		releasePolicy, err = releaseversion.NewPolicyRegistry(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// Pick up changes of the mounted policy file without restarting.
+		go releasePolicy.Watch(context.Background(), configReloadInterval)
+	}
+
+	var maintenanceRegistry *maintenance.Registry
+	{
+		c := maintenance.RegistryConfig{
+			Logger: newLogger,
+			File:   cfg.MaintenanceFile,
+		}
+		maintenanceRegistry, err = maintenance.NewRegistry(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		// Pick up changes of the mounted maintenance file without restarting.
+		go maintenanceRegistry.Watch(context.Background(), configReloadInterval)
+	}
+
+	// The Azure quota check is optional, as it calls the Azure API on every
+	// node pool creation and scale up.
+	var azureQuotaFactory azurequota.Factory
+	if cfg.AzureQuotaCheck {
+		c := azurequota.FactoryConfig{
+			Credentials: credentialsResolver,
+			Logger:      newLogger,
+		}
+		azureQuotaFactory, err = azurequota.NewFactory(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	// Serve the upgrade path between releases, so users can look up how to
+	// get to a release the webhook refuses to upgrade to directly.
+	{
+		c := releaseversion.UpgradePathHandlerConfig{
+			CtrlClient: ctrlClient,
+			Logger:     newLogger,
+			Policy:     releasePolicy,
+		}
+		upgradePathHandler, err := releaseversion.NewUpgradePathHandler(c)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		handler.Handle(releaseversion.UpgradePathPattern, upgradePathHandler)
 	}
 
 	// Register all webhook handlers
-	err = app.RegisterWebhookHandlers(handler, cfg, newLogger, ctrlClient, ctrlCache, vmcapsFactory)
+	err = app.RegisterWebhookHandlers(handler, cfg, newLogger, ctrlClient, ctrlCache, vmcapsFactory, enforcementRegistry, sizingPolicy, quotaRegistry, azureQuotaFactory, releasePolicy, maintenanceRegistry)
 	if err != nil {
 		return microerror.Mask(err)
 	}
@@ -142,6 +301,48 @@ func mainError() error {
 	return nil
 }
 
+// exportSKUCatalog writes the VM SKUs of the configured locations into a
+// catalog file, which can be used with the file and live-with-file-fallback
+// VM SKU sources.
+func exportSKUCatalog(cfg config.ExportSKUCatalogConfig) error {
+	var resourceSkusClient compute.ResourceSkusClient
+	{
+		authorizer, err := auth.NewAuthorizerFromEnvironment()
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		resourceSkusClient = compute.NewResourceSkusClient(cfg.SubscriptionID)
+		resourceSkusClient.Client.Authorizer = authorizer
+	}
+
+	api := vmcapabilities.NewAzureAPI(vmcapabilities.AzureConfig{ResourceSkuClient: &resourceSkusClient})
+	catalog, err := vmcapabilities.ExportCatalog(context.Background(), api, cfg.SubscriptionID, cfg.Locations)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var content []byte
+	if strings.HasSuffix(cfg.Output, ".json") {
+		content, err = json.MarshalIndent(catalog, "", "  ")
+	} else {
+		content, err = yaml.Marshal(catalog)
+	}
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if cfg.Output == "" {
+		_, err = os.Stdout.Write(content)
+	} else {
+		err = ioutil.WriteFile(cfg.Output, content, 0600)
+	}
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
 func healthCheck(writer http.ResponseWriter, request *http.Request) {
 	writer.WriteHeader(http.StatusOK)
 	_, err := writer.Write([]byte("ok"))
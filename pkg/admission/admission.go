@@ -8,7 +8,7 @@ import (
 	"net/http"
 
 	"github.com/giantswarm/microerror"
-	"k8s.io/api/admission/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -17,7 +17,7 @@ import (
 )
 
 type Admitter interface {
-	Admit(review *v1beta1.AdmissionRequest) ([]PatchOperation, error)
+	Admit(review *admissionv1.AdmissionRequest) ([]PatchOperation, error)
 	Log(keyVals ...interface{})
 }
 
@@ -43,9 +43,9 @@ func Handler(admitter Admitter) http.HandlerFunc {
 			return
 		}
 
-		review := v1beta1.AdmissionReview{}
-		if _, _, err := Deserializer.Decode(data, nil, &review); err != nil {
-			admitter.Log("level", "error", "message", "unable to parse admission review request")
+		review, err := DecodeReview(data)
+		if err != nil {
+			admitter.Log("level", "error", "message", "unable to parse admission review request", "stack", microerror.JSON(err))
 			writer.WriteHeader(http.StatusBadRequest)
 			return
 		}
@@ -53,21 +53,21 @@ func Handler(admitter Admitter) http.HandlerFunc {
 
 		patch, err := admitter.Admit(review.Request)
 		if err != nil {
-			writeResponse(admitter, writer, errorResponse(review.Request.UID, microerror.Mask(err)))
+			writeResponse(admitter, writer, review, errorResponse(review.Request.UID, microerror.Mask(err)))
 			return
 		}
 
 		patchData, err := json.Marshal(patch)
 		if err != nil {
 			admitter.Log("level", "error", "message", fmt.Sprintf("unable to serialize patch for %s: %v", resourceName, err))
-			writeResponse(admitter, writer, errorResponse(review.Request.UID, InternalError))
+			writeResponse(admitter, writer, review, errorResponse(review.Request.UID, InternalError))
 			return
 		}
 
 		admitter.Log("level", "debug", "message", fmt.Sprintf("admitted %s (with %d patches)", resourceName, len(patch)))
 
-		pt := v1beta1.PatchTypeJSONPatch
-		writeResponse(admitter, writer, &v1beta1.AdmissionResponse{
+		pt := admissionv1.PatchTypeJSONPatch
+		writeResponse(admitter, writer, review, &admissionv1.AdmissionResponse{
 			Allowed:   true,
 			UID:       review.Request.UID,
 			Patch:     patchData,
@@ -76,7 +76,7 @@ func Handler(admitter Admitter) http.HandlerFunc {
 	}
 }
 
-func extractName(request *v1beta1.AdmissionRequest) string {
+func extractName(request *admissionv1.AdmissionRequest) string {
 	if request.Name != "" {
 		return request.Name
 	}
@@ -95,10 +95,8 @@ func extractName(request *v1beta1.AdmissionRequest) string {
 	return "<unknown>"
 }
 
-func writeResponse(admitter Admitter, writer http.ResponseWriter, response *v1beta1.AdmissionResponse) {
-	resp, err := json.Marshal(v1beta1.AdmissionReview{
-		Response: response,
-	})
+func writeResponse(admitter Admitter, writer http.ResponseWriter, review *Review, response *admissionv1.AdmissionResponse) {
+	resp, err := review.EncodeResponse(response)
 	if err != nil {
 		admitter.Log("level", "error", "message", "unable to serialize response", microerror.JSON(err))
 		writer.WriteHeader(http.StatusInternalServerError)
@@ -108,8 +106,8 @@ func writeResponse(admitter Admitter, writer http.ResponseWriter, response *v1be
 	}
 }
 
-func errorResponse(uid types.UID, err error) *v1beta1.AdmissionResponse {
-	return &v1beta1.AdmissionResponse{
+func errorResponse(uid types.UID, err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
 		Allowed: false,
 		UID:     uid,
 		Result: &metav1.Status{
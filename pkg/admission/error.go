@@ -0,0 +1,23 @@
+package admission
+
+import (
+	"github.com/giantswarm/microerror"
+)
+
+var invalidReviewError = &microerror.Error{
+	Kind: "invalidReviewError",
+}
+
+// IsInvalidReview asserts invalidReviewError.
+func IsInvalidReview(err error) bool {
+	return microerror.Cause(err) == invalidReviewError
+}
+
+var unsupportedAPIVersionError = &microerror.Error{
+	Kind: "unsupportedAPIVersionError",
+}
+
+// IsUnsupportedAPIVersion asserts unsupportedAPIVersionError.
+func IsUnsupportedAPIVersion(err error) bool {
+	return microerror.Cause(err) == unsupportedAPIVersionError
+}
@@ -0,0 +1,147 @@
+package admission
+
+import (
+	"encoding/json"
+
+	"github.com/giantswarm/microerror"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	APIVersionV1      = "admission.k8s.io/v1"
+	APIVersionV1beta1 = "admission.k8s.io/v1beta1"
+
+	reviewKind = "AdmissionReview"
+)
+
+// Review is a version agnostic AdmissionReview. The request is always
+// represented as an admission.k8s.io/v1 AdmissionRequest, while APIVersion
+// remembers in which version the API server sent the review, so that the
+// response can be sent back in the same version.
+type Review struct {
+	APIVersion string
+	Request    *admissionv1.AdmissionRequest
+}
+
+// DecodeReview parses an AdmissionReview in either admission.k8s.io/v1 or
+// admission.k8s.io/v1beta1 version.
+func DecodeReview(data []byte) (*Review, error) {
+	typeMeta := metav1.TypeMeta{}
+	err := json.Unmarshal(data, &typeMeta)
+	if err != nil {
+		return nil, microerror.Maskf(invalidReviewError, "unable to parse AdmissionReview type: %v", err)
+	}
+
+	var review *Review
+	switch typeMeta.APIVersion {
+	case APIVersionV1:
+		v1Review := admissionv1.AdmissionReview{}
+		if _, _, err := Deserializer.Decode(data, nil, &v1Review); err != nil {
+			return nil, microerror.Maskf(invalidReviewError, "unable to parse %s AdmissionReview: %v", APIVersionV1, err)
+		}
+
+		review = &Review{
+			APIVersion: APIVersionV1,
+			Request:    v1Review.Request,
+		}
+	case APIVersionV1beta1, "":
+		// Reviews without apiVersion are treated as v1beta1, as that was the
+		// only version supported before v1 was introduced.
+		v1beta1Review := admissionv1beta1.AdmissionReview{}
+		if _, _, err := Deserializer.Decode(data, nil, &v1beta1Review); err != nil {
+			return nil, microerror.Maskf(invalidReviewError, "unable to parse %s AdmissionReview: %v", APIVersionV1beta1, err)
+		}
+
+		review = &Review{
+			APIVersion: APIVersionV1beta1,
+			Request:    requestFromV1beta1(v1beta1Review.Request),
+		}
+	default:
+		return nil, microerror.Maskf(unsupportedAPIVersionError, "AdmissionReview apiVersion %q is not supported, expected %q or %q", typeMeta.APIVersion, APIVersionV1, APIVersionV1beta1)
+	}
+
+	if review.Request == nil {
+		return nil, microerror.Maskf(invalidReviewError, "AdmissionReview does not contain a request")
+	}
+
+	return review, nil
+}
+
+// EncodeResponse serializes an AdmissionReview with the given response, using
+// the same apiVersion as the review that was received.
+func (r *Review) EncodeResponse(response *admissionv1.AdmissionResponse) ([]byte, error) {
+	typeMeta := metav1.TypeMeta{
+		Kind:       reviewKind,
+		APIVersion: r.APIVersion,
+	}
+
+	var data []byte
+	var err error
+	switch r.APIVersion {
+	case APIVersionV1:
+		data, err = json.Marshal(admissionv1.AdmissionReview{
+			TypeMeta: typeMeta,
+			Response: response,
+		})
+	case APIVersionV1beta1:
+		data, err = json.Marshal(admissionv1beta1.AdmissionReview{
+			TypeMeta: typeMeta,
+			Response: responseToV1beta1(response),
+		})
+	default:
+		return nil, microerror.Maskf(unsupportedAPIVersionError, "AdmissionReview apiVersion %q is not supported, expected %q or %q", r.APIVersion, APIVersionV1, APIVersionV1beta1)
+	}
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return data, nil
+}
+
+func requestFromV1beta1(request *admissionv1beta1.AdmissionRequest) *admissionv1.AdmissionRequest {
+	if request == nil {
+		return nil
+	}
+
+	return &admissionv1.AdmissionRequest{
+		UID:                request.UID,
+		Kind:               request.Kind,
+		Resource:           request.Resource,
+		SubResource:        request.SubResource,
+		RequestKind:        request.RequestKind,
+		RequestResource:    request.RequestResource,
+		RequestSubResource: request.RequestSubResource,
+		Name:               request.Name,
+		Namespace:          request.Namespace,
+		Operation:          admissionv1.Operation(request.Operation),
+		UserInfo:           request.UserInfo,
+		Object:             request.Object,
+		OldObject:          request.OldObject,
+		DryRun:             request.DryRun,
+		Options:            request.Options,
+	}
+}
+
+func responseToV1beta1(response *admissionv1.AdmissionResponse) *admissionv1beta1.AdmissionResponse {
+	if response == nil {
+		return nil
+	}
+
+	var patchType *admissionv1beta1.PatchType
+	if response.PatchType != nil {
+		pt := admissionv1beta1.PatchType(*response.PatchType)
+		patchType = &pt
+	}
+
+	return &admissionv1beta1.AdmissionResponse{
+		UID:              response.UID,
+		Allowed:          response.Allowed,
+		Result:           response.Result,
+		Patch:            response.Patch,
+		PatchType:        patchType,
+		AuditAnnotations: response.AuditAnnotations,
+		Warnings:         response.Warnings,
+	}
+}
@@ -9,8 +9,15 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/azure-admission-controller/internal/azurequota"
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/maintenance"
+	"github.com/giantswarm/azure-admission-controller/internal/quota"
+	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
+	"github.com/giantswarm/azure-admission-controller/internal/sizingpolicy"
 	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
 	"github.com/giantswarm/azure-admission-controller/pkg/azurecluster"
+	"github.com/giantswarm/azure-admission-controller/pkg/azureclusteridentity"
 	"github.com/giantswarm/azure-admission-controller/pkg/azuremachine"
 	"github.com/giantswarm/azure-admission-controller/pkg/azuremachinepool"
 	"github.com/giantswarm/azure-admission-controller/pkg/azureupdate"
@@ -32,20 +39,27 @@ import (
 // - A webhook handler implementation that implements validator.WebhookUpdateHandler will be
 // registered to handle HTTP requests at path `/validate/<resource name>/update`.
 //
+// - A webhook handler implementation that implements validator.WebhookStatusUpdateHandler will be
+// registered to handle HTTP requests at path `/validate/<resource name>/status`.
+//
+// - A webhook handler implementation that implements validator.WebhookDeleteHandler will be
+// registered to handle HTTP requests at path `/validate/<resource name>/delete`.
+//
 // - A webhook handler implementation that implements mutator.WebhookCreateHandler will be
 // registered to handle HTTP requests at path `/mutate/<resource name>/create`.
 //
 // - A webhook handler implementation that implements mutator.WebhookUpdateHandler will be
 // registered to handle HTTP requests at path `/mutate/<resource name>/update`.
-func RegisterWebhookHandlers(httpRequestHandler HttpRequestHandler, cfg config.Config, newLogger micrologger.Logger, ctrlClient client.Client, ctrlReader client.Reader, vmcapsFactory vmcapabilities.Factory) error {
+func RegisterWebhookHandlers(httpRequestHandler HttpRequestHandler, cfg config.Config, newLogger micrologger.Logger, ctrlClient client.Client, ctrlReader client.Reader, vmcapsFactory vmcapabilities.Factory, enforcementRegistry *enforcement.Registry, sizingPolicy *sizingpolicy.Registry, quotaRegistry *quota.Registry, azureQuotaFactory azurequota.Factory, releasePolicy *releaseversion.PolicyRegistry, maintenanceRegistry *maintenance.Registry) error {
 	var err error
 
 	var validatorHttpHandlerFactory *validator.HttpHandlerFactory
 	{
 		c := validator.HttpHandlerFactoryConfig{
-			CtrlClient: ctrlClient,
-			CtrlReader: ctrlReader,
-			Logger:     newLogger,
+			CtrlClient:  ctrlClient,
+			CtrlReader:  ctrlReader,
+			Enforcement: enforcementRegistry,
+			Logger:      newLogger,
 		}
 		validatorHttpHandlerFactory, err = validator.NewHttpHandlerFactory(c)
 		if err != nil {
@@ -66,7 +80,7 @@ func RegisterWebhookHandlers(httpRequestHandler HttpRequestHandler, cfg config.C
 		}
 	}
 
-	handlers, err := getAllHandlers(cfg, newLogger, ctrlClient, ctrlReader, vmcapsFactory)
+	handlers, err := getAllHandlers(cfg, newLogger, ctrlClient, ctrlReader, vmcapsFactory, sizingPolicy, quotaRegistry, azureQuotaFactory, releasePolicy, maintenanceRegistry)
 	if err != nil {
 		return microerror.Mask(err)
 	}
@@ -88,6 +102,22 @@ func RegisterWebhookHandlers(httpRequestHandler HttpRequestHandler, cfg config.C
 			httpRequestHandler.Handle(pattern, httpHandlerFunc)
 		}
 
+		// Check if the handler is implementing validator.WebhookStatusUpdateHandler, and if it does,
+		// register a handler function for validating status subresource update requests.
+		if webhookHandler, ok := h.(validator.WebhookStatusUpdateHandler); ok {
+			pattern := fmt.Sprintf("/validate/%s/status", webhookHandler.Resource())
+			httpHandlerFunc := validatorHttpHandlerFactory.NewStatusUpdateHandler(webhookHandler)
+			httpRequestHandler.Handle(pattern, httpHandlerFunc)
+		}
+
+		// Check if the handler is implementing validator.WebhookDeleteHandler, and if it does,
+		// register a handler function for validating delete requests.
+		if webhookHandler, ok := h.(validator.WebhookDeleteHandler); ok {
+			pattern := fmt.Sprintf("/validate/%s/delete", webhookHandler.Resource())
+			httpHandlerFunc := validatorHttpHandlerFactory.NewDeleteHandler(webhookHandler)
+			httpRequestHandler.Handle(pattern, httpHandlerFunc)
+		}
+
 		// Check if the handler is implementing mutator.WebhookCreateHandler, and if it does,
 		// register a handler function for validating create requests.
 		if webhookHandler, ok := h.(mutator.WebhookCreateHandler); ok {
@@ -108,17 +138,34 @@ func RegisterWebhookHandlers(httpRequestHandler HttpRequestHandler, cfg config.C
 	return nil
 }
 
-func getAllHandlers(cfg config.Config, newLogger micrologger.Logger, ctrlClient client.Client, ctrlReader client.Reader, vmcapsFactory vmcapabilities.Factory) ([]ResourceHandler, error) {
+func getAllHandlers(cfg config.Config, newLogger micrologger.Logger, ctrlClient client.Client, ctrlReader client.Reader, vmcapsFactory vmcapabilities.Factory, sizingPolicy *sizingpolicy.Registry, quotaRegistry *quota.Registry, azureQuotaFactory azurequota.Factory, releasePolicy *releaseversion.PolicyRegistry, maintenanceRegistry *maintenance.Registry) ([]ResourceHandler, error) {
 	scheme := runtime.NewScheme()
 	codecs := serializer.NewCodecFactory(scheme)
 	universalDeserializer := codecs.UniversalDeserializer()
 	var handlers []ResourceHandler
 
+	var quotaChecker *quota.Checker
+	{
+		c := quota.CheckerConfig{
+			CtrlClient:    ctrlClient,
+			CtrlReader:    ctrlReader,
+			Logger:        newLogger,
+			Registry:      quotaRegistry,
+			VMcapsFactory: vmcapsFactory,
+		}
+		var err error
+		quotaChecker, err = quota.NewChecker(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
 	{
 		c := azureupdate.AzureConfigWebhookHandlerConfig{
-			CtrlClient: ctrlClient,
-			Decoder:    universalDeserializer,
-			Logger:     newLogger,
+			CtrlClient:    ctrlClient,
+			Decoder:       universalDeserializer,
+			Logger:        newLogger,
+			ReleasePolicy: releasePolicy,
 		}
 		azureConfigWebhookHandler, err := azureupdate.NewAzureConfigWebhookHandler(c)
 		if err != nil {
@@ -129,12 +176,13 @@ func getAllHandlers(cfg config.Config, newLogger micrologger.Logger, ctrlClient
 
 	{
 		c := azurecluster.WebhookHandlerConfig{
-			BaseDomain: cfg.BaseDomain,
-			CtrlReader: ctrlReader,
-			CtrlClient: ctrlClient,
-			Decoder:    universalDeserializer,
-			Location:   cfg.Location,
-			Logger:     newLogger,
+			BaseDomain:    cfg.BaseDomain,
+			CtrlReader:    ctrlReader,
+			CtrlClient:    ctrlClient,
+			Decoder:       universalDeserializer,
+			Location:      cfg.Location,
+			Logger:        newLogger,
+			ReleasePolicy: releasePolicy,
 		}
 		azureClusterWebhookHandler, err := azurecluster.NewWebhookHandler(c)
 		if err != nil {
@@ -144,11 +192,25 @@ func getAllHandlers(cfg config.Config, newLogger micrologger.Logger, ctrlClient
 	}
 
 	{
-		c := azureupdate.AzureClusterConfigWebhookHandlerConfig{
+		c := azureclusteridentity.WebhookHandlerConfig{
 			CtrlClient: ctrlClient,
 			Decoder:    universalDeserializer,
 			Logger:     newLogger,
 		}
+		azureClusterIdentityWebhookHandler, err := azureclusteridentity.NewWebhookHandler(c)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		handlers = append(handlers, azureClusterIdentityWebhookHandler)
+	}
+
+	{
+		c := azureupdate.AzureClusterConfigWebhookHandlerConfig{
+			CtrlClient:    ctrlClient,
+			Decoder:       universalDeserializer,
+			Logger:        newLogger,
+			ReleasePolicy: releasePolicy,
+		}
 		azureClusterConfigWebhookHandler, err := azureupdate.NewAzureClusterConfigWebhookHandler(c)
 		if err != nil {
 			return nil, microerror.Mask(err)
@@ -162,6 +224,7 @@ func getAllHandlers(cfg config.Config, newLogger micrologger.Logger, ctrlClient
 			Decoder:       universalDeserializer,
 			Location:      cfg.Location,
 			Logger:        newLogger,
+			ReleasePolicy: releasePolicy,
 			VMcapsFactory: vmcapsFactory,
 		}
 		azureMachineWebhookHandler, err := azuremachine.NewWebhookHandler(c)
@@ -173,11 +236,15 @@ func getAllHandlers(cfg config.Config, newLogger micrologger.Logger, ctrlClient
 
 	{
 		c := azuremachinepool.WebhookHandlerConfig{
-			CtrlClient:    ctrlClient,
-			Decoder:       universalDeserializer,
-			Location:      cfg.Location,
-			Logger:        newLogger,
-			VMcapsFactory: vmcapsFactory,
+			AzureQuotaFactory: azureQuotaFactory,
+			CtrlClient:        ctrlClient,
+			Decoder:           universalDeserializer,
+			GPUOrganizations:  cfg.GPUOrganizations,
+			Location:          cfg.Location,
+			Logger:            newLogger,
+			QuotaChecker:      quotaChecker,
+			SizingPolicy:      sizingPolicy,
+			VMcapsFactory:     vmcapsFactory,
 		}
 		azureMachinePoolWebhookHandler, err := azuremachinepool.NewWebhookHandler(c)
 		if err != nil {
@@ -188,11 +255,13 @@ func getAllHandlers(cfg config.Config, newLogger micrologger.Logger, ctrlClient
 
 	{
 		c := cluster.WebhookHandlerConfig{
-			BaseDomain: cfg.BaseDomain,
-			CtrlClient: ctrlClient,
-			CtrlReader: ctrlReader,
-			Decoder:    universalDeserializer,
-			Logger:     newLogger,
+			BaseDomain:    cfg.BaseDomain,
+			CtrlClient:    ctrlClient,
+			CtrlReader:    ctrlReader,
+			Decoder:       universalDeserializer,
+			Logger:        newLogger,
+			Maintenance:   maintenanceRegistry,
+			ReleasePolicy: releasePolicy,
 		}
 		clusterWebhookHandler, err := cluster.NewWebhookHandler(c)
 		if err != nil {
@@ -203,10 +272,12 @@ func getAllHandlers(cfg config.Config, newLogger micrologger.Logger, ctrlClient
 
 	{
 		c := machinepool.WebhookHandlerConfig{
-			CtrlClient:    ctrlClient,
-			Decoder:       universalDeserializer,
-			Logger:        newLogger,
-			VMcapsFactory: vmcapsFactory,
+			AzureQuotaFactory: azureQuotaFactory,
+			CtrlClient:        ctrlClient,
+			Decoder:           universalDeserializer,
+			Logger:            newLogger,
+			QuotaChecker:      quotaChecker,
+			VMcapsFactory:     vmcapsFactory,
 		}
 		machinePoolWebhookHandler, err := machinepool.NewWebhookHandler(c)
 		if err != nil {
@@ -7,6 +7,9 @@ import (
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/quota"
+	"github.com/giantswarm/azure-admission-controller/internal/sizingpolicy"
 	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
 	"github.com/giantswarm/azure-admission-controller/pkg/config"
 	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
@@ -31,7 +34,28 @@ func Test_RegisterWebhookHandlers(t *testing.T) {
 	fakeK8sClient := unittest.FakeK8sClient()
 	ctrlClient := fakeK8sClient.CtrlClient()
 
-	vmcaps, err := vmcapabilities.NewFactory(logger)
+	vmcaps, err := vmcapabilities.NewFactory(vmcapabilities.FactoryConfig{Logger: logger})
+	if err != nil {
+		t.Fatal(microerror.JSON(err))
+	}
+
+	enforcementRegistry, err := enforcement.NewRegistry(enforcement.RegistryConfig{
+		Logger: logger,
+	})
+	if err != nil {
+		t.Fatal(microerror.JSON(err))
+	}
+
+	sizingPolicy, err := sizingpolicy.NewRegistry(sizingpolicy.RegistryConfig{
+		Logger: logger,
+	})
+	if err != nil {
+		t.Fatal(microerror.JSON(err))
+	}
+
+	quotaRegistry, err := quota.NewRegistry(quota.RegistryConfig{
+		Logger: logger,
+	})
 	if err != nil {
 		t.Fatal(microerror.JSON(err))
 	}
@@ -40,7 +64,7 @@ func Test_RegisterWebhookHandlers(t *testing.T) {
 	handler := http.NewServeMux()
 
 	// Run webhook handlers registration.
-	err = RegisterWebhookHandlers(handler, cfg, logger, ctrlClient, ctrlClient, vmcaps)
+	err = RegisterWebhookHandlers(handler, cfg, logger, ctrlClient, ctrlClient, vmcaps, enforcementRegistry, sizingPolicy, quotaRegistry, nil, nil, nil)
 	if err != nil {
 		t.Fatalf("Error while registering webhook handlers %#v", err)
 	}
@@ -2,6 +2,8 @@ package azurecluster
 
 import (
 	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
 var invalidConfigError = &microerror.Error{
@@ -66,3 +68,13 @@ var unexpectedLocationError = &microerror.Error{
 func IsUnexpectedLocationError(err error) bool {
 	return microerror.Cause(err) == unexpectedLocationError
 }
+
+func init() {
+	errors.RegisterValidationErrors(
+		invalidControlPlaneEndpointHostError,
+		invalidControlPlaneEndpointPortError,
+		controlPlaneEndpointWasChangedError,
+		locationWasChangedError,
+		unexpectedLocationError,
+	)
+}
@@ -13,7 +13,7 @@ import (
 )
 
 func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{}) ([]mutator.PatchOperation, error) {
-	var result []mutator.PatchOperation
+	var builder mutator.PatchBuilder
 	azureClusterCR, err := key.ToAzureClusterPtr(object)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
@@ -24,41 +24,31 @@ func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{})
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	patch, err = h.ensureControlPlaneEndpointPort(ctx, azureClusterCR)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	patch, err = h.ensureLocation(ctx, azureClusterCR)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	patch, err = ensureAPIServerLB(azureClusterCR)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	patch, err = mutator.EnsureComponentVersionLabelFromRelease(ctx, h.ctrlReader, azureClusterCR.GetObjectMeta(), "azure-operator", label.AzureOperatorVersion)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	azureClusterCR.Default()
 	{
@@ -71,7 +61,15 @@ func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{})
 		capiPatches = patches.SkipForPath("/spec/networkSpec/vnet", capiPatches)
 		capiPatches = patches.SkipForPath("/spec/networkSpec/subnets", capiPatches)
 
-		result = append(result, capiPatches...)
+		builder.AddDefaults(capiPatches)
+	}
+
+	result, err := builder.Build()
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
+	}
+	for _, conflict := range builder.Conflicts() {
+		h.logger.LogCtx(ctx, "level", "warning", "message", conflict)
 	}
 
 	return result, nil
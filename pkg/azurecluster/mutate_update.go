@@ -12,7 +12,7 @@ import (
 )
 
 func (h *WebhookHandler) OnUpdateMutate(ctx context.Context, _ interface{}, object interface{}) ([]mutator.PatchOperation, error) {
-	var result []mutator.PatchOperation
+	var builder mutator.PatchBuilder
 	azureClusterCR, err := key.ToAzureClusterPtr(object)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
@@ -23,25 +23,19 @@ func (h *WebhookHandler) OnUpdateMutate(ctx context.Context, _ interface{}, obje
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	patch, err = mutator.EnsureComponentVersionLabelFromRelease(ctx, h.ctrlReader, azureClusterCR.GetObjectMeta(), "azure-operator", label.AzureOperatorVersion)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	patch, err = mutator.EnsureComponentVersionLabelFromRelease(ctx, h.ctrlReader, azureClusterCR.GetObjectMeta(), "cluster-operator", label.ClusterOperatorVersion)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	azureClusterCR.Default()
 	{
@@ -54,7 +48,15 @@ func (h *WebhookHandler) OnUpdateMutate(ctx context.Context, _ interface{}, obje
 		capiPatches = patches.SkipForPath("/spec/networkSpec/vnet", capiPatches)
 		capiPatches = patches.SkipForPath("/spec/networkSpec/subnets", capiPatches)
 
-		result = append(result, capiPatches...)
+		builder.AddDefaults(capiPatches)
+	}
+
+	result, err := builder.Build()
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
+	}
+	for _, conflict := range builder.Conflicts() {
+		h.logger.LogCtx(ctx, "level", "warning", "message", conflict)
 	}
 
 	return result, nil
@@ -4,45 +4,46 @@ import (
 	"context"
 
 	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 	"github.com/giantswarm/azure-admission-controller/pkg/key"
 )
 
+var (
+	controlPlaneEndpointPath = field.NewPath("spec", "controlPlaneEndpoint")
+	locationPath             = field.NewPath("spec", "location")
+)
+
 func (h *WebhookHandler) OnCreateValidate(ctx context.Context, object interface{}) error {
 	azureClusterCR, err := key.ToAzureClusterPtr(object)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
+
 	err = azureClusterCR.ValidateCreate()
 	err = errors.IgnoreCAPIErrorForField("metadata.Name", err)
 	err = errors.IgnoreCAPIErrorForField("spec.networkSpec.subnets", err)
 	err = errors.IgnoreCAPIErrorForField("spec.SubscriptionID", err)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(nil, err)
 
+	// Only an existing cluster is a validation failure, failing to list the
+	// clusters is returned as is.
 	err = generic.ClusterExists(ctx, h.ctrlClient, azureClusterCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = generic.ValidateOrganizationLabelContainsExistingOrganization(ctx, h.ctrlClient, azureClusterCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = validateControlPlaneEndpoint(*azureClusterCR, h.baseDomain)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = validateLocation(*azureClusterCR, h.location)
-	if err != nil {
+	if generic.IsNotAllowed(err) {
+		validationErrors.Add(nil, err)
+	} else if err != nil {
 		return microerror.Mask(err)
 	}
+	validationErrors.Add(generic.OrganizationLabelPath, generic.ValidateOrganizationLabelContainsExistingOrganization(ctx, h.ctrlClient, azureClusterCR))
+	validationErrors.Add(controlPlaneEndpointPath, enforcement.Apply(ctx, enforcement.AzureClusterControlPlaneEndpoint, validateControlPlaneEndpoint(*azureClusterCR, h.baseDomain)))
+	validationErrors.Add(locationPath, enforcement.Apply(ctx, enforcement.AzureClusterLocation, validateLocation(*azureClusterCR, h.location)))
 
-	return nil
+	return microerror.Mask(validationErrors.Err())
 }
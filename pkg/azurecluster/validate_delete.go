@@ -0,0 +1,45 @@
+package azurecluster
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/giantswarm/azure-admission-controller/internal/conditions"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/generic"
+	"github.com/giantswarm/azure-admission-controller/pkg/key"
+)
+
+var (
+	// The Creating and Upgrading conditions are set on the Cluster.
+	clusterConditionsPath = field.NewPath("cluster", "status", "conditions")
+)
+
+func (h *WebhookHandler) OnDeleteValidate(ctx context.Context, object interface{}) error {
+	azureClusterCR, err := key.ToAzureClusterPtr(object)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	cluster, ok, err := generic.TryGetOwnerCluster(ctx, h.ctrlReader, azureClusterCR)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if ok && !cluster.GetDeletionTimestamp().IsZero() {
+		// Deleting the Cluster was already validated, and CAPI is now
+		// deleting its AzureCluster.
+		h.logger.LogCtx(ctx, "level", "debug", "message", "The owner cluster is being deleted so we don't validate it")
+		return nil
+	}
+
+	var validationErrors errors.ValidationErrors
+
+	validationErrors.Add(generic.DeletionProtectionAnnotationPath, generic.ValidateDeletionProtectionRemoved(azureClusterCR))
+	if ok {
+		validationErrors.Add(clusterConditionsPath, conditions.ValidateClusterDeletion(&cluster))
+	}
+
+	return microerror.Mask(validationErrors.Err())
+}
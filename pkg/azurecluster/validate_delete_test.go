@@ -0,0 +1,112 @@
+package azurecluster
+
+import (
+	"context"
+	"testing"
+
+	aeconditions "github.com/giantswarm/apiextensions/v6/pkg/conditions"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	corev1 "k8s.io/api/core/v1"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/azurecluster"
+	clusterbuilder "github.com/giantswarm/azure-admission-controller/internal/test/cluster"
+	"github.com/giantswarm/azure-admission-controller/pkg/generic"
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+func TestAzureClusterDeleteValidate(t *testing.T) {
+	type testCase struct {
+		name         string
+		azureCluster *capz.AzureCluster
+		cluster      *capi.Cluster
+		errorMatcher func(err error) bool
+	}
+
+	upgrading := capi.Conditions{
+		{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionTrue},
+	}
+	protected := map[string]string{generic.DeletionProtectionAnnotation: "true"}
+
+	testCases := []testCase{
+		{
+			name:         "case 0: unprotected azure cluster without cluster",
+			azureCluster: builder.BuildAzureCluster(builder.Name("ab123")),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 1: protected azure cluster",
+			azureCluster: builder.BuildAzureCluster(builder.Name("ab123"), builder.Annotations(protected)),
+			cluster:      clusterbuilder.BuildCluster(clusterbuilder.Name("ab123")),
+			errorMatcher: generic.IsDeletionProtectedError,
+		},
+		{
+			name:         "case 2: cluster is being upgraded",
+			azureCluster: builder.BuildAzureCluster(builder.Name("ab123")),
+			cluster:      clusterbuilder.BuildCluster(clusterbuilder.Name("ab123"), clusterbuilder.Conditions(upgrading)),
+			errorMatcher: errors.IsInvalidOperationError,
+		},
+		{
+			name:         "case 3: cluster is being deleted",
+			azureCluster: builder.BuildAzureCluster(builder.Name("ab123"), builder.Annotations(protected)),
+			cluster:      clusterbuilder.BuildCluster(clusterbuilder.Name("ab123"), clusterbuilder.Conditions(upgrading), clusterbuilder.WithDeletionTimestamp()),
+			errorMatcher: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+
+			// Create a new logger that is used by all admitters.
+			var newLogger micrologger.Logger
+			{
+				newLogger, err = micrologger.New(micrologger.Config{})
+				if err != nil {
+					panic(microerror.JSON(err))
+				}
+			}
+
+			ctx := context.Background()
+			fakeK8sClient := unittest.FakeK8sClient()
+			ctrlClient := fakeK8sClient.CtrlClient()
+
+			if tc.cluster != nil {
+				err = ctrlClient.Create(ctx, tc.cluster)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			handler, err := NewWebhookHandler(WebhookHandlerConfig{
+				BaseDomain: "k8s.test.westeurope.azure.gigantic.io",
+				CtrlReader: ctrlClient,
+				CtrlClient: ctrlClient,
+				Decoder:    unittest.NewFakeDecoder(),
+				Location:   "westeurope",
+				Logger:     newLogger,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Run validating webhook handler on AzureCluster delete.
+			err = handler.OnDeleteValidate(ctx, tc.azureCluster)
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/giantswarm/microerror"
 	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
 	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
 	"github.com/giantswarm/azure-admission-controller/internal/semverhelper"
@@ -28,6 +29,10 @@ func (h *WebhookHandler) OnUpdateValidate(ctx context.Context, oldObject interfa
 		return microerror.Mask(err)
 	}
 
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
+
 	err = azureClusterNewCR.ValidateUpdate(azureClusterOldCR)
 	err = errors.IgnoreCAPIErrorForField("metadata.Name", err)
 	err = errors.IgnoreCAPIErrorForField("spec.networkSpec.subnets", err)
@@ -37,21 +42,13 @@ func (h *WebhookHandler) OnUpdateValidate(ctx context.Context, oldObject interfa
 	err = errors.IgnoreCAPIErrorForField("spec.ControlPlaneEndpoint.Host", err)
 	err = errors.IgnoreCAPIErrorForField("spec.ControlPlaneEndpoint.Port", err)
 	err = errors.IgnoreCAPIErrorForField("spec.networkSpec.nodeOutboundLB", err)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(nil, err)
 
-	err = generic.ValidateOrganizationLabelUnchanged(azureClusterOldCR, azureClusterNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = validateControlPlaneEndpointUnchanged(*azureClusterOldCR, *azureClusterNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(generic.OrganizationLabelPath, generic.ValidateOrganizationLabelUnchanged(azureClusterOldCR, azureClusterNewCR))
+	validationErrors.Add(controlPlaneEndpointPath, enforcement.Apply(ctx, enforcement.AzureClusterControlPlaneEndpoint, validateControlPlaneEndpointUnchanged(*azureClusterOldCR, *azureClusterNewCR)))
+	validationErrors.Add(generic.ReleaseVersionLabelPath, h.validateRelease(ctx, azureClusterOldCR, azureClusterNewCR))
 
-	return h.validateRelease(ctx, azureClusterOldCR, azureClusterNewCR)
+	return microerror.Mask(validationErrors.Err())
 }
 
 func (h *WebhookHandler) validateRelease(ctx context.Context, azureClusterOldCR *capz.AzureCluster, azureClusterNewCR *capz.AzureCluster) error {
@@ -64,5 +61,5 @@ func (h *WebhookHandler) validateRelease(ctx context.Context, azureClusterOldCR
 		return microerror.Maskf(errors.ParsingFailedError, "unable to parse version from applied AzureCluster")
 	}
 
-	return releaseversion.Validate(ctx, h.ctrlClient, oldClusterVersion, newClusterVersion)
+	return releaseversion.Validate(ctx, h.ctrlClient, h.releasePolicy.Policy(), oldClusterVersion, newClusterVersion)
 }
@@ -9,16 +9,18 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
 	"github.com/giantswarm/azure-admission-controller/pkg/validator"
 )
 
 type WebhookHandler struct {
-	baseDomain string
-	ctrlReader client.Reader
-	ctrlClient client.Client
-	decoder    runtime.Decoder
-	location   string
-	logger     micrologger.Logger
+	baseDomain    string
+	ctrlReader    client.Reader
+	ctrlClient    client.Client
+	decoder       runtime.Decoder
+	location      string
+	logger        micrologger.Logger
+	releasePolicy *releaseversion.PolicyRegistry
 }
 
 type WebhookHandlerConfig struct {
@@ -28,6 +30,11 @@ type WebhookHandlerConfig struct {
 	Decoder    runtime.Decoder
 	Location   string
 	Logger     micrologger.Logger
+
+	// ReleasePolicy is the release policy of the installation, used to plan
+	// upgrade paths. It is optional, when nil any patch release can be
+	// upgraded.
+	ReleasePolicy *releaseversion.PolicyRegistry
 }
 
 func NewWebhookHandler(config WebhookHandlerConfig) (*WebhookHandler, error) {
@@ -51,12 +58,13 @@ func NewWebhookHandler(config WebhookHandlerConfig) (*WebhookHandler, error) {
 	}
 
 	v := &WebhookHandler{
-		baseDomain: config.BaseDomain,
-		ctrlReader: config.CtrlReader,
-		ctrlClient: config.CtrlClient,
-		decoder:    config.Decoder,
-		location:   config.Location,
-		logger:     config.Logger,
+		baseDomain:    config.BaseDomain,
+		ctrlReader:    config.CtrlReader,
+		ctrlClient:    config.CtrlClient,
+		decoder:       config.Decoder,
+		location:      config.Location,
+		logger:        config.Logger,
+		releasePolicy: config.ReleasePolicy,
 	}
 
 	return v, nil
@@ -0,0 +1,71 @@
+package azureclusteridentity
+
+import (
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+)
+
+var identityInUseError = &microerror.Error{
+	Kind: "identityInUseError",
+}
+
+// IsIdentityInUse asserts identityInUseError.
+func IsIdentityInUse(err error) bool {
+	return microerror.Cause(err) == identityInUseError
+}
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var invalidIDError = &microerror.Error{
+	Kind: "invalidIDError",
+}
+
+// IsInvalidID asserts invalidIDError.
+func IsInvalidID(err error) bool {
+	return microerror.Cause(err) == invalidIDError
+}
+
+var invalidSecretError = &microerror.Error{
+	Kind: "invalidSecretError",
+}
+
+// IsInvalidSecret asserts invalidSecretError.
+func IsInvalidSecret(err error) bool {
+	return microerror.Cause(err) == invalidSecretError
+}
+
+var namespaceNotAllowedError = &microerror.Error{
+	Kind: "namespaceNotAllowedError",
+}
+
+// IsNamespaceNotAllowed asserts namespaceNotAllowedError.
+func IsNamespaceNotAllowed(err error) bool {
+	return microerror.Cause(err) == namespaceNotAllowedError
+}
+
+var unsupportedIdentityTypeError = &microerror.Error{
+	Kind: "unsupportedIdentityTypeError",
+}
+
+// IsUnsupportedIdentityType asserts unsupportedIdentityTypeError.
+func IsUnsupportedIdentityType(err error) bool {
+	return microerror.Cause(err) == unsupportedIdentityTypeError
+}
+
+func init() {
+	errors.RegisterValidationErrors(
+		identityInUseError,
+		invalidIDError,
+		invalidSecretError,
+		namespaceNotAllowedError,
+		unsupportedIdentityTypeError,
+	)
+}
@@ -0,0 +1,200 @@
+package azureclusteridentity
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/internal/capzcredentials"
+)
+
+var (
+	allowedNamespacesPath = field.NewPath("spec", "allowedNamespaces")
+	clientIDPath          = field.NewPath("spec", "clientID")
+	clientSecretPath      = field.NewPath("spec", "clientSecret")
+	tenantIDPath          = field.NewPath("spec", "tenantID")
+	typePath              = field.NewPath("spec", "type")
+)
+
+// guidRegexp matches the GUIDs Azure uses for tenant and client IDs, e.g.
+// "6b3a7a1e-2f4c-4b8e-9d2a-1c5e7f9a0b3d".
+var guidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func validateType(identity *capz.AzureClusterIdentity) error {
+	for _, t := range capzcredentials.IdentityTypes() {
+		if identity.Spec.Type == t {
+			return nil
+		}
+	}
+
+	return microerror.Maskf(unsupportedIdentityTypeError, "type %#q is not supported, expected one of %v", identity.Spec.Type, capzcredentials.IdentityTypes())
+}
+
+func validateTenantID(identity *capz.AzureClusterIdentity) error {
+	if !guidRegexp.MatchString(identity.Spec.TenantID) {
+		return microerror.Maskf(invalidIDError, "tenant ID %#q must be a GUID", identity.Spec.TenantID)
+	}
+
+	return nil
+}
+
+func validateClientID(identity *capz.AzureClusterIdentity) error {
+	// A user assigned managed identity can be referenced by its resource ID
+	// instead.
+	if identity.Spec.Type == capz.UserAssignedMSI && identity.Spec.ClientID == "" && identity.Spec.ResourceID != "" {
+		return nil
+	}
+
+	if !guidRegexp.MatchString(identity.Spec.ClientID) {
+		return microerror.Maskf(invalidIDError, "client ID %#q must be a GUID", identity.Spec.ClientID)
+	}
+
+	return nil
+}
+
+// validateSecret checks that the Secret of identities authenticating with a
+// client secret or certificate exists, and has the key the credentials are
+// read from, see capzcredentials.
+func (h *WebhookHandler) validateSecret(ctx context.Context, identity *capz.AzureClusterIdentity) error {
+	if !capzcredentials.RequiresSecret(identity.Spec.Type) {
+		return nil
+	}
+
+	ref := identity.Spec.ClientSecret
+	if ref.Name == "" || ref.Namespace == "" {
+		return microerror.Maskf(invalidSecretError, "name and namespace of the Secret must not be empty for type %#q", identity.Spec.Type)
+	}
+
+	secret := &corev1.Secret{}
+	err := h.ctrlClient.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, secret)
+	if apierrors.IsNotFound(err) {
+		return microerror.Maskf(invalidSecretError, "Secret %s/%s does not exist", ref.Namespace, ref.Name)
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(secret.Data[capzcredentials.IdentitySecretKey]) == 0 {
+		return microerror.Maskf(invalidSecretError, "Secret %s/%s must contain the key %#q", ref.Namespace, ref.Name, capzcredentials.IdentitySecretKey)
+	}
+
+	return nil
+}
+
+// validateAllowedNamespaces checks that the AzureClusters which already
+// reference the identity are still allowed to use it. CAPZ refuses to
+// reconcile the others.
+func (h *WebhookHandler) validateAllowedNamespaces(ctx context.Context, identity *capz.AzureClusterIdentity) error {
+	azureClusters, err := h.referencingAzureClusters(ctx, identity)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	namespaces := map[string]bool{}
+	for _, azureCluster := range azureClusters {
+		namespaces[azureCluster.Namespace] = true
+	}
+
+	var denied []string
+	for namespace := range namespaces {
+		allowed, err := h.isNamespaceAllowed(ctx, identity.Spec.AllowedNamespaces, namespace)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if !allowed {
+			denied = append(denied, namespace)
+		}
+	}
+
+	if len(denied) > 0 {
+		sort.Strings(denied)
+		return microerror.Maskf(namespaceNotAllowedError, "AzureClusters in namespaces %v reference this identity, it must allow them", denied)
+	}
+
+	return nil
+}
+
+// isNamespaceAllowed returns true when AzureClusters of the given namespace
+// may use an identity with the given allowed namespaces, with the same rules
+// as CAPZ: nil allows no namespace, an empty value allows all, and otherwise
+// the namespace must be listed or match the selector.
+func (h *WebhookHandler) isNamespaceAllowed(ctx context.Context, allowedNamespaces *capz.AllowedNamespaces, namespace string) (bool, error) {
+	if allowedNamespaces == nil {
+		return false, nil
+	}
+	if reflect.DeepEqual(*allowedNamespaces, capz.AllowedNamespaces{}) {
+		return true, nil
+	}
+
+	for _, n := range allowedNamespaces.NamespaceList {
+		if n == namespace {
+			return true, nil
+		}
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(allowedNamespaces.Selector)
+	if err != nil {
+		return false, microerror.Maskf(namespaceNotAllowedError, "invalid namespace selector: %s", err.Error())
+	}
+	// A nil or empty selector matches no namespace.
+	if selector.Empty() {
+		return false, nil
+	}
+
+	var namespaceList corev1.NamespaceList
+	err = h.ctrlClient.List(ctx, &namespaceList, client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+	for _, n := range namespaceList.Items {
+		if n.Name == namespace {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// referencingAzureClusters returns the AzureClusters of all namespaces which
+// IdentityRef points to the given identity.
+func (h *WebhookHandler) referencingAzureClusters(ctx context.Context, identity *capz.AzureClusterIdentity) ([]capz.AzureCluster, error) {
+	var azureClusterList capz.AzureClusterList
+	err := h.ctrlClient.List(ctx, &azureClusterList)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	key := client.ObjectKey{Namespace: identity.Namespace, Name: identity.Name}
+
+	var azureClusters []capz.AzureCluster
+	for i := range azureClusterList.Items {
+		azureCluster := &azureClusterList.Items[i]
+		if azureCluster.Spec.IdentityRef == nil {
+			continue
+		}
+		if capzcredentials.IdentityKey(azureCluster) == key {
+			azureClusters = append(azureClusters, *azureCluster)
+		}
+	}
+
+	return azureClusters, nil
+}
+
+func clusterNames(azureClusters []capz.AzureCluster) []string {
+	var names []string
+	for _, azureCluster := range azureClusters {
+		names = append(names, fmt.Sprintf("%s/%s", azureCluster.Namespace, azureCluster.Name))
+	}
+	sort.Strings(names)
+
+	return names
+}
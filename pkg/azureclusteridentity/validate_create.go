@@ -0,0 +1,30 @@
+package azureclusteridentity
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/key"
+)
+
+func (h *WebhookHandler) OnCreateValidate(ctx context.Context, object interface{}) error {
+	identity, err := key.ToAzureClusterIdentityPtr(object)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
+
+	validationErrors.Add(typePath, validateType(identity))
+	validationErrors.Add(tenantIDPath, enforcement.Apply(ctx, enforcement.AzureClusterIdentityIDs, validateTenantID(identity)))
+	validationErrors.Add(clientIDPath, enforcement.Apply(ctx, enforcement.AzureClusterIdentityIDs, validateClientID(identity)))
+	validationErrors.Add(clientSecretPath, enforcement.Apply(ctx, enforcement.AzureClusterIdentitySecret, h.validateSecret(ctx, identity)))
+	validationErrors.Add(allowedNamespacesPath, enforcement.Apply(ctx, enforcement.AzureClusterIdentityAllowedNamespaces, h.validateAllowedNamespaces(ctx, identity)))
+
+	return microerror.Mask(validationErrors.Err())
+}
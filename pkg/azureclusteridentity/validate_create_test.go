@@ -0,0 +1,265 @@
+package azureclusteridentity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/azurecluster"
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+const (
+	clientID = "6b3a7a1e-2f4c-4b8e-9d2a-1c5e7f9a0b3d"
+	tenantID = "0f1e2d3c-4b5a-4968-8776-655443322110"
+)
+
+func TestAzureClusterIdentityCreateValidate(t *testing.T) {
+	type testCase struct {
+		name         string
+		identity     *capz.AzureClusterIdentity
+		objects      []client.Object
+		errorMatcher func(err error) bool
+	}
+
+	testCases := []testCase{
+		{
+			name:         "case 0: valid service principal",
+			identity:     newIdentity(),
+			objects:      []client.Object{newSecret("clientSecret")},
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 1: secret does not exist",
+			identity:     newIdentity(),
+			errorMatcher: IsInvalidSecret,
+		},
+		{
+			name:         "case 2: secret without client secret key",
+			identity:     newIdentity(),
+			objects:      []client.Object{newSecret("password")},
+			errorMatcher: IsInvalidSecret,
+		},
+		{
+			name: "case 3: secret without namespace",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.ClientSecret.Namespace = ""
+			}),
+			objects:      []client.Object{newSecret("clientSecret")},
+			errorMatcher: IsInvalidSecret,
+		},
+		{
+			name: "case 4: tenant ID is no GUID",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.TenantID = "giantswarm.onmicrosoft.com"
+			}),
+			objects:      []client.Object{newSecret("clientSecret")},
+			errorMatcher: IsInvalidID,
+		},
+		{
+			name: "case 5: client ID is no GUID",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.ClientID = "6b3a7a1e2f4c4b8e9d2a1c5e7f9a0b3d"
+			}),
+			objects:      []client.Object{newSecret("clientSecret")},
+			errorMatcher: IsInvalidID,
+		},
+		{
+			name: "case 6: user assigned MSI needs no secret",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.Type = capz.UserAssignedMSI
+				i.Spec.ClientSecret = corev1.SecretReference{}
+			}),
+			errorMatcher: nil,
+		},
+		{
+			name: "case 7: user assigned MSI with resource ID instead of client ID",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.Type = capz.UserAssignedMSI
+				i.Spec.ClientID = ""
+				i.Spec.ResourceID = "/subscriptions/s/resourceGroups/g/providers/Microsoft.ManagedIdentity/userAssignedIdentities/i"
+			}),
+			errorMatcher: nil,
+		},
+		{
+			name: "case 8: unsupported type",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.Type = "Unknown"
+			}),
+			errorMatcher: IsUnsupportedIdentityType,
+		},
+		{
+			name: "case 9: referencing cluster in a listed namespace",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.AllowedNamespaces = &capz.AllowedNamespaces{NamespaceList: []string{"org-acme"}}
+			}),
+			objects: []client.Object{
+				newSecret("clientSecret"),
+				builder.BuildAzureCluster(builder.Name("ab123"), namespace("org-acme"), builder.IdentityRef("identity", "giantswarm")),
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 10: referencing cluster in a selected namespace",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.AllowedNamespaces = &capz.AllowedNamespaces{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"giantswarm.io/organization": "acme"}}}
+			}),
+			objects: []client.Object{
+				newSecret("clientSecret"),
+				newNamespace("org-acme", map[string]string{"giantswarm.io/organization": "acme"}),
+				builder.BuildAzureCluster(builder.Name("ab123"), namespace("org-acme"), builder.IdentityRef("identity", "giantswarm")),
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 11: referencing cluster in a namespace that is not allowed",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.AllowedNamespaces = &capz.AllowedNamespaces{NamespaceList: []string{"org-other"}}
+			}),
+			objects: []client.Object{
+				newSecret("clientSecret"),
+				newNamespace("org-acme", map[string]string{"giantswarm.io/organization": "acme"}),
+				builder.BuildAzureCluster(builder.Name("ab123"), namespace("org-acme"), builder.IdentityRef("identity", "giantswarm")),
+			},
+			errorMatcher: IsNamespaceNotAllowed,
+		},
+		{
+			name: "case 12: referencing cluster without allowed namespaces",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.AllowedNamespaces = nil
+			}),
+			objects: []client.Object{
+				newSecret("clientSecret"),
+				builder.BuildAzureCluster(builder.Name("ab123"), namespace("giantswarm"), builder.IdentityRef("identity", "")),
+			},
+			errorMatcher: IsNamespaceNotAllowed,
+		},
+		{
+			name: "case 13: clusters referencing other identities are ignored",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.AllowedNamespaces = nil
+			}),
+			objects: []client.Object{
+				newSecret("clientSecret"),
+				builder.BuildAzureCluster(builder.Name("ab123"), namespace("org-acme"), builder.IdentityRef("other", "giantswarm")),
+				builder.BuildAzureCluster(builder.Name("cd456"), namespace("org-acme")),
+			},
+			errorMatcher: nil,
+		},
+		{
+			name: "case 14: empty type",
+			identity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.Type = ""
+			}),
+			objects:      []client.Object{newSecret("clientSecret")},
+			errorMatcher: IsUnsupportedIdentityType,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			handler := newHandler(t, tc.objects...)
+
+			// Run validating webhook handler on AzureClusterIdentity creation.
+			err := handler.OnCreateValidate(ctx, tc.identity)
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
+
+func newHandler(t *testing.T, objects ...client.Object) *WebhookHandler {
+	newLogger, err := micrologger.New(micrologger.Config{})
+	if err != nil {
+		t.Fatal(microerror.JSON(err))
+	}
+
+	ctx := context.Background()
+	fakeK8sClient := unittest.FakeK8sClient()
+	ctrlClient := fakeK8sClient.CtrlClient()
+	for _, o := range objects {
+		err = ctrlClient.Create(ctx, o)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler, err := NewWebhookHandler(WebhookHandlerConfig{
+		CtrlClient: ctrlClient,
+		Decoder:    unittest.NewFakeDecoder(),
+		Logger:     newLogger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return handler
+}
+
+func newIdentity(opts ...func(*capz.AzureClusterIdentity)) *capz.AzureClusterIdentity {
+	identity := &capz.AzureClusterIdentity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "identity",
+			Namespace: "giantswarm",
+		},
+		Spec: capz.AzureClusterIdentitySpec{
+			Type:     capz.ServicePrincipal,
+			ClientID: clientID,
+			TenantID: tenantID,
+			ClientSecret: corev1.SecretReference{
+				Name:      "identity-secret",
+				Namespace: "giantswarm",
+			},
+			AllowedNamespaces: &capz.AllowedNamespaces{},
+		},
+	}
+
+	for _, opt := range opts {
+		opt(identity)
+	}
+
+	return identity
+}
+
+func newSecret(key string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "identity-secret",
+			Namespace: "giantswarm",
+		},
+		Data: map[string][]byte{key: []byte("secret")},
+	}
+}
+
+func newNamespace(name string, labels map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+func namespace(namespace string) builder.BuilderOption {
+	return func(azureCluster *capz.AzureCluster) *capz.AzureCluster {
+		azureCluster.Namespace = namespace
+		return azureCluster
+	}
+}
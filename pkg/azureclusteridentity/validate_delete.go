@@ -0,0 +1,41 @@
+package azureclusteridentity
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/key"
+)
+
+func (h *WebhookHandler) OnDeleteValidate(ctx context.Context, object interface{}) error {
+	identity, err := key.ToAzureClusterIdentityPtr(object)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var validationErrors errors.ValidationErrors
+
+	validationErrors.Add(nil, enforcement.Apply(ctx, enforcement.AzureClusterIdentityInUse, h.validateNotInUse(ctx, identity)))
+
+	return microerror.Mask(validationErrors.Err())
+}
+
+// validateNotInUse checks that no AzureCluster references the identity.
+// AzureClusters being deleted count as well, as CAPZ needs the identity to
+// delete their Azure resources.
+func (h *WebhookHandler) validateNotInUse(ctx context.Context, identity *capz.AzureClusterIdentity) error {
+	azureClusters, err := h.referencingAzureClusters(ctx, identity)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(azureClusters) > 0 {
+		return microerror.Maskf(identityInUseError, "AzureClusterIdentity %s/%s is still used by AzureClusters %v", identity.Namespace, identity.Name, clusterNames(azureClusters))
+	}
+
+	return nil
+}
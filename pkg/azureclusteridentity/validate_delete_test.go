@@ -0,0 +1,69 @@
+package azureclusteridentity
+
+import (
+	"context"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/azurecluster"
+)
+
+func TestAzureClusterIdentityDeleteValidate(t *testing.T) {
+	type testCase struct {
+		name         string
+		objects      []client.Object
+		errorMatcher func(err error) bool
+	}
+
+	testCases := []testCase{
+		{
+			name:         "case 0: identity without clusters",
+			errorMatcher: nil,
+		},
+		{
+			name: "case 1: identity referenced by a cluster",
+			objects: []client.Object{
+				builder.BuildAzureCluster(builder.Name("ab123"), namespace("org-acme"), builder.IdentityRef("identity", "giantswarm")),
+			},
+			errorMatcher: IsIdentityInUse,
+		},
+		{
+			name: "case 2: identity referenced by a cluster being deleted",
+			objects: []client.Object{
+				builder.BuildAzureCluster(builder.Name("ab123"), namespace("giantswarm"), builder.IdentityRef("identity", ""), builder.WithDeletionTimestamp()),
+			},
+			errorMatcher: IsIdentityInUse,
+		},
+		{
+			name: "case 3: clusters referencing other identities",
+			objects: []client.Object{
+				builder.BuildAzureCluster(builder.Name("ab123"), namespace("org-acme"), builder.IdentityRef("identity", "")),
+				builder.BuildAzureCluster(builder.Name("cd456"), namespace("giantswarm"), builder.IdentityRef("other", "giantswarm")),
+			},
+			errorMatcher: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			handler := newHandler(t, tc.objects...)
+
+			// Run validating webhook handler on AzureClusterIdentity delete.
+			err := handler.OnDeleteValidate(ctx, newIdentity())
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package azureclusteridentity
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/key"
+)
+
+func (h *WebhookHandler) OnUpdateValidate(ctx context.Context, oldObject interface{}, object interface{}) error {
+	identityNew, err := key.ToAzureClusterIdentityPtr(object)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !identityNew.GetDeletionTimestamp().IsZero() {
+		h.logger.LogCtx(ctx, "level", "debug", "message", "The object is being deleted so we don't validate it")
+		return nil
+	}
+
+	identityOld, err := key.ToAzureClusterIdentityPtr(oldObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	// Identities created before this webhook may be invalid already. Their
+	// metadata can still be changed, only spec changes are validated.
+	if reflect.DeepEqual(identityOld.Spec, identityNew.Spec) {
+		return nil
+	}
+
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
+
+	validationErrors.Add(typePath, validateType(identityNew))
+	validationErrors.Add(tenantIDPath, enforcement.Apply(ctx, enforcement.AzureClusterIdentityIDs, validateTenantID(identityNew)))
+	validationErrors.Add(clientIDPath, enforcement.Apply(ctx, enforcement.AzureClusterIdentityIDs, validateClientID(identityNew)))
+	validationErrors.Add(clientSecretPath, enforcement.Apply(ctx, enforcement.AzureClusterIdentitySecret, h.validateSecret(ctx, identityNew)))
+	validationErrors.Add(allowedNamespacesPath, enforcement.Apply(ctx, enforcement.AzureClusterIdentityAllowedNamespaces, h.validateAllowedNamespaces(ctx, identityNew)))
+
+	return microerror.Mask(validationErrors.Err())
+}
@@ -0,0 +1,84 @@
+package azureclusteridentity
+
+import (
+	"context"
+	"testing"
+
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/azurecluster"
+)
+
+func TestAzureClusterIdentityUpdateValidate(t *testing.T) {
+	type testCase struct {
+		name         string
+		oldIdentity  *capz.AzureClusterIdentity
+		newIdentity  *capz.AzureClusterIdentity
+		objects      []client.Object
+		errorMatcher func(err error) bool
+	}
+
+	testCases := []testCase{
+		{
+			name:        "case 0: metadata change of an identity without secret",
+			oldIdentity: newIdentity(),
+			newIdentity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Labels = map[string]string{"giantswarm.io/managed-by": "cluster-operator"}
+			}),
+			errorMatcher: nil,
+		},
+		{
+			name:        "case 1: client ID changed to a valid one",
+			oldIdentity: newIdentity(),
+			newIdentity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.ClientID = "2a4c6e8f-1b3d-4f5a-8c7e-9d0b1a2c3e4f"
+			}),
+			objects:      []client.Object{newSecret("clientSecret")},
+			errorMatcher: nil,
+		},
+		{
+			name:        "case 2: client ID changed to an invalid one",
+			oldIdentity: newIdentity(),
+			newIdentity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.ClientID = "client"
+			}),
+			objects:      []client.Object{newSecret("clientSecret")},
+			errorMatcher: IsInvalidID,
+		},
+		{
+			name:        "case 3: allowed namespaces no longer include a referencing cluster",
+			oldIdentity: newIdentity(),
+			newIdentity: newIdentity(func(i *capz.AzureClusterIdentity) {
+				i.Spec.AllowedNamespaces = &capz.AllowedNamespaces{NamespaceList: []string{"org-other"}}
+			}),
+			objects: []client.Object{
+				newSecret("clientSecret"),
+				builder.BuildAzureCluster(builder.Name("ab123"), namespace("org-acme"), builder.IdentityRef("identity", "giantswarm")),
+			},
+			errorMatcher: IsNamespaceNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			handler := newHandler(t, tc.objects...)
+
+			// Run validating webhook handler on AzureClusterIdentity update.
+			err := handler.OnUpdateValidate(ctx, tc.oldIdentity, tc.newIdentity)
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package azureclusteridentity
+
+import (
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/validator"
+)
+
+type WebhookHandler struct {
+	ctrlClient client.Client
+	decoder    runtime.Decoder
+	logger     micrologger.Logger
+}
+
+type WebhookHandlerConfig struct {
+	CtrlClient client.Client
+	Decoder    runtime.Decoder
+	Logger     micrologger.Logger
+}
+
+func NewWebhookHandler(config WebhookHandlerConfig) (*WebhookHandler, error) {
+	if config.CtrlClient == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.CtrlClient must not be empty", config)
+	}
+	if config.Decoder == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Decoder must not be empty", config)
+	}
+	if config.Logger == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
+	}
+
+	v := &WebhookHandler{
+		ctrlClient: config.CtrlClient,
+		decoder:    config.Decoder,
+		logger:     config.Logger,
+	}
+
+	return v, nil
+}
+
+func (h *WebhookHandler) Log(keyVals ...interface{}) {
+	h.logger.Log(keyVals...)
+}
+
+func (h *WebhookHandler) Resource() string {
+	return "azureclusteridentity"
+}
+
+// ValidateAllObjects makes the validator validate all AzureClusterIdentities.
+// They don't have a release label and don't belong to a cluster, so they would
+// never be validated otherwise.
+func (h *WebhookHandler) ValidateAllObjects() bool {
+	return true
+}
+
+func (h *WebhookHandler) Decode(rawObject runtime.RawExtension) (metav1.ObjectMetaAccessor, error) {
+	azureClusterIdentityCR := &capz.AzureClusterIdentity{}
+	if _, _, err := validator.Deserializer.Decode(rawObject.Raw, nil, azureClusterIdentityCR); err != nil {
+		return nil, microerror.Maskf(errors.ParsingFailedError, "unable to parse AzureClusterIdentity CR: %v", err)
+	}
+
+	return azureClusterIdentityCR, nil
+}
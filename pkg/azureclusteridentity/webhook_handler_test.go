@@ -0,0 +1,144 @@
+package azureclusteridentity
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/micrologger"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/azurecluster"
+	"github.com/giantswarm/azure-admission-controller/pkg/validator"
+)
+
+// TestHttpHandler checks that AzureClusterIdentities are validated even though
+// they don't have a release label, unlike the other resources, which are only
+// validated when they are reconciled by a legacy release.
+func TestHttpHandler(t *testing.T) {
+	type testCase struct {
+		name            string
+		objects         []client.Object
+		operation       admissionv1.Operation
+		identity        *capz.AzureClusterIdentity
+		expectedAllowed bool
+		expectedMessage string
+	}
+
+	testCases := []testCase{
+		{
+			name:      "case 0: create identity without release label",
+			objects:   []client.Object{newSecret("clientSecret")},
+			operation: admissionv1.Create,
+			identity: newIdentity(func(identity *capz.AzureClusterIdentity) {
+				identity.Spec.ClientID = "not-a-uuid"
+			}),
+			expectedAllowed: false,
+			expectedMessage: "invalid id error",
+		},
+		{
+			name:            "case 1: delete identity without release label",
+			objects:         []client.Object{builder.BuildAzureCluster(builder.Name("ab123"), namespace("giantswarm"), builder.IdentityRef("identity", "giantswarm"))},
+			operation:       admissionv1.Delete,
+			identity:        newIdentity(),
+			expectedAllowed: false,
+			expectedMessage: "identity in use error",
+		},
+		{
+			name:            "case 2: delete unused identity without release label",
+			operation:       admissionv1.Delete,
+			identity:        newIdentity(),
+			expectedAllowed: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, err := micrologger.New(micrologger.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler := newHandler(t, tc.objects...)
+
+			enforcementRegistry, err := enforcement.NewRegistry(enforcement.RegistryConfig{
+				Logger: logger,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			httpHandlerFactory, err := validator.NewHttpHandlerFactory(validator.HttpHandlerFactoryConfig{
+				CtrlReader:  handler.ctrlClient,
+				CtrlClient:  handler.ctrlClient,
+				Enforcement: enforcementRegistry,
+				Logger:      logger,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := json.Marshal(tc.identity)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			request := &admissionv1.AdmissionRequest{
+				UID:       "test-uid",
+				Operation: tc.operation,
+			}
+
+			var httpHandler http.HandlerFunc
+			switch tc.operation {
+			case admissionv1.Create:
+				request.Object = runtime.RawExtension{Raw: raw}
+				httpHandler = httpHandlerFactory.NewCreateHandler(handler)
+			case admissionv1.Delete:
+				// Delete requests only contain the old object.
+				request.OldObject = runtime.RawExtension{Raw: raw}
+				httpHandler = httpHandlerFactory.NewDeleteHandler(handler)
+			default:
+				t.Fatal("Unsupported operation")
+			}
+
+			body, err := json.Marshal(admissionv1.AdmissionReview{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: admissionv1.SchemeGroupVersion.String(),
+					Kind:       "AdmissionReview",
+				},
+				Request: request,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			httpRequest := httptest.NewRequest(http.MethodPost, "/validate", bytes.NewReader(body))
+			httpRequest.Header.Set("Content-Type", "application/json")
+
+			httpRecorder := httptest.NewRecorder()
+			httpHandler.ServeHTTP(httpRecorder, httpRequest)
+
+			var review admissionv1.AdmissionReview
+			err = json.Unmarshal(httpRecorder.Body.Bytes(), &review)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if review.Response.Allowed != tc.expectedAllowed {
+				t.Fatalf("expected allowed %t, got %t: %v", tc.expectedAllowed, review.Response.Allowed, review.Response.Result)
+			}
+
+			if !tc.expectedAllowed && !strings.Contains(review.Response.Result.Message, tc.expectedMessage) {
+				t.Fatalf("expected message containing %q, got %q", tc.expectedMessage, review.Response.Result.Message)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package azuremachine
 
 import (
 	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
 var invalidConfigError = &microerror.Error{
@@ -57,3 +59,12 @@ var sshFieldIsSetError = &microerror.Error{
 func IsSSHFieldIsSetError(err error) bool {
 	return microerror.Cause(err) == sshFieldIsSetError
 }
+
+func init() {
+	errors.RegisterValidationErrors(
+		unsupportedFailureDomainError,
+		locationWithNoFailureDomainSupportError,
+		failureDomainWasChangedError,
+		sshFieldIsSetError,
+	)
+}
@@ -59,7 +59,7 @@ func TestAzureMachineCreateMutate(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			vmcaps, err := vmcapabilities.NewFactory(newLogger)
+			vmcaps, err := vmcapabilities.NewFactory(vmcapabilities.FactoryConfig{Logger: newLogger})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -11,7 +11,7 @@ import (
 )
 
 func (h *WebhookHandler) OnUpdateMutate(ctx context.Context, _ interface{}, object interface{}) ([]mutator.PatchOperation, error) {
-	var result []mutator.PatchOperation
+	var builder mutator.PatchBuilder
 	azureMachineCR, err := key.ToAzureMachinePtr(object)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
@@ -22,9 +22,7 @@ func (h *WebhookHandler) OnUpdateMutate(ctx context.Context, _ interface{}, obje
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	azureMachineCR.Default()
 	{
@@ -36,7 +34,15 @@ func (h *WebhookHandler) OnUpdateMutate(ctx context.Context, _ interface{}, obje
 
 		capiPatches = patches.SkipForPath("/spec/sshPublicKey", capiPatches)
 
-		result = append(result, capiPatches...)
+		builder.AddDefaults(capiPatches)
+	}
+
+	result, err := builder.Build()
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
+	}
+	for _, conflict := range builder.Conflicts() {
+		h.logger.LogCtx(ctx, "level", "warning", "message", conflict)
 	}
 
 	return result, nil
@@ -4,48 +4,45 @@ import (
 	"context"
 
 	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 	"github.com/giantswarm/azure-admission-controller/pkg/key"
 )
 
+var (
+	sshPublicKeyPath  = field.NewPath("spec", "sshPublicKey")
+	failureDomainPath = field.NewPath("spec", "failureDomain")
+)
+
 func (h *WebhookHandler) OnCreateValidate(ctx context.Context, object interface{}) error {
 	cr, err := key.ToAzureMachinePtr(object)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
+
 	err = cr.ValidateCreate()
 	err = errors.IgnoreCAPIErrorForField("sshPublicKey", err)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = generic.ValidateOrganizationLabelContainsExistingOrganization(ctx, h.ctrlClient, cr)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(nil, err)
 
-	err = checkSSHKeyIsEmpty(ctx, cr)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(generic.OrganizationLabelPath, generic.ValidateOrganizationLabelContainsExistingOrganization(ctx, h.ctrlClient, cr))
+	validationErrors.Add(sshPublicKeyPath, enforcement.Apply(ctx, enforcement.AzureMachineSSHKey, checkSSHKeyIsEmpty(ctx, cr)))
 
 	vmcaps, err := h.vmcapsFactory.GetClient(ctx, h.ctrlClient, cr.ObjectMeta)
 	if err != nil {
 		return microerror.Mask(err)
 	}
-
 	supportedAZs, err := vmcaps.SupportedAZs(ctx, h.location, cr.Spec.VMSize)
 	if err != nil {
 		return microerror.Mask(err)
 	}
+	validationErrors.Add(failureDomainPath, enforcement.Apply(ctx, enforcement.AzureMachineFailureDomain, validateFailureDomain(*cr, supportedAZs, h.location)))
 
-	err = validateFailureDomain(*cr, supportedAZs, h.location)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	return nil
+	return microerror.Mask(validationErrors.Err())
 }
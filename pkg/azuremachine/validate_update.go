@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"github.com/giantswarm/microerror"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
 	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
 	"github.com/giantswarm/azure-admission-controller/internal/semverhelper"
@@ -27,27 +29,23 @@ func (h *WebhookHandler) OnUpdateValidate(ctx context.Context, oldObject interfa
 		return microerror.Mask(err)
 	}
 
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
+
 	err = azureMachineNewCR.ValidateUpdate(azureMachineOldCR)
 	err = errors.IgnoreCAPIErrorForField("sshPublicKey", err)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(nil, err)
 
-	err = checkSSHKeyIsEmpty(ctx, azureMachineNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(sshPublicKeyPath, enforcement.Apply(ctx, enforcement.AzureMachineSSHKey, checkSSHKeyIsEmpty(ctx, azureMachineNewCR)))
+	validationErrors.Add(generic.OrganizationLabelPath, generic.ValidateOrganizationLabelUnchanged(azureMachineOldCR, azureMachineNewCR))
+	validationErrors.Add(failureDomainPath, enforcement.Apply(ctx, enforcement.AzureMachineFailureDomain, validateFailureDomainUnchanged(*azureMachineOldCR, *azureMachineNewCR)))
+	validationErrors.Add(generic.ReleaseVersionLabelPath, h.validateRelease(ctx, azureMachineOldCR, azureMachineNewCR))
 
-	err = generic.ValidateOrganizationLabelUnchanged(azureMachineOldCR, azureMachineNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = validateFailureDomainUnchanged(*azureMachineOldCR, *azureMachineNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	return microerror.Mask(validationErrors.Err())
+}
 
+func (h *WebhookHandler) validateRelease(ctx context.Context, azureMachineOldCR *capz.AzureMachine, azureMachineNewCR *capz.AzureMachine) error {
 	oldClusterVersion, err := semverhelper.GetSemverFromLabels(azureMachineOldCR.Labels)
 	if err != nil {
 		return microerror.Maskf(errors.ParsingFailedError, "unable to parse version from AzureConfig (before edit)")
@@ -57,5 +55,5 @@ func (h *WebhookHandler) OnUpdateValidate(ctx context.Context, oldObject interfa
 		return microerror.Maskf(errors.ParsingFailedError, "unable to parse version from AzureConfig (after edit)")
 	}
 
-	return releaseversion.Validate(ctx, h.ctrlClient, oldClusterVersion, newClusterVersion)
+	return releaseversion.Validate(ctx, h.ctrlClient, h.releasePolicy.Policy(), oldClusterVersion, newClusterVersion)
 }
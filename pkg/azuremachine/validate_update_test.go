@@ -67,7 +67,7 @@ func TestAzureMachineUpdateValidate(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			vmcaps, err := vmcapabilities.NewFactory(newLogger)
+			vmcaps, err := vmcapabilities.NewFactory(vmcapabilities.FactoryConfig{Logger: newLogger})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -11,6 +11,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
 	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
 	"github.com/giantswarm/azure-admission-controller/pkg/key"
 	"github.com/giantswarm/azure-admission-controller/pkg/mutator"
@@ -23,6 +24,7 @@ type WebhookHandler struct {
 	location      string
 	logger        micrologger.Logger
 	vmcapsFactory vmcapabilities.Factory
+	releasePolicy *releaseversion.PolicyRegistry
 }
 
 type WebhookHandlerConfig struct {
@@ -31,6 +33,11 @@ type WebhookHandlerConfig struct {
 	Location      string
 	Logger        micrologger.Logger
 	VMcapsFactory vmcapabilities.Factory
+
+	// ReleasePolicy is the release policy of the installation, used to plan
+	// upgrade paths. It is optional, when nil any patch release can be
+	// upgraded.
+	ReleasePolicy *releaseversion.PolicyRegistry
 }
 
 func NewWebhookHandler(config WebhookHandlerConfig) (*WebhookHandler, error) {
@@ -55,6 +62,7 @@ func NewWebhookHandler(config WebhookHandlerConfig) (*WebhookHandler, error) {
 		decoder:       config.Decoder,
 		location:      config.Location,
 		logger:        config.Logger,
+		releasePolicy: config.ReleasePolicy,
 		vmcapsFactory: config.VMcapsFactory,
 	}
 
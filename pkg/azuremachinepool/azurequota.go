@@ -0,0 +1,64 @@
+package azuremachinepool
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/internal/quota"
+	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
+)
+
+// checkAzureQuota checks that the subscription has enough vCPU quota left in
+// the location for the nodes of the node pool. The nodes are the ones the
+// paired MachinePool can scale up to, or a single one when the MachinePool
+// doesn't exist yet. The MachinePool validation checks the rest once it is
+// created. It is not checked when no Azure quota factory is configured.
+func (h *WebhookHandler) checkAzureQuota(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMachinePool *capzexp.AzureMachinePool) error {
+	if h.azureQuotaFactory == nil {
+		return nil
+	}
+
+	location := azureMachinePool.Spec.Location
+	vmSize := azureMachinePool.Spec.Template.VMSize
+
+	cpus, err := vmcaps.CPUs(ctx, location, vmSize)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	family, err := vmcaps.Family(ctx, location, vmSize)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	nodes := 1
+	{
+		var machinePools capiexp.MachinePoolList
+		err = h.ctrlClient.List(ctx, &machinePools, client.InNamespace(azureMachinePool.Namespace))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		for i := range machinePools.Items {
+			if machinePools.Items[i].Spec.Template.Spec.InfrastructureRef.Name == azureMachinePool.Name {
+				nodes = quota.MaxNodes(&machinePools.Items[i])
+				break
+			}
+		}
+	}
+
+	vcpuQuota, err := h.azureQuotaFactory.GetClient(ctx, h.ctrlClient, azureMachinePool.ObjectMeta)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = vcpuQuota.CheckVCPUs(ctx, location, family, nodes*cpus)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
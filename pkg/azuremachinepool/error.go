@@ -2,6 +2,8 @@ package azuremachinepool
 
 import (
 	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
 var invalidConfigError = &microerror.Error{
@@ -130,6 +132,15 @@ func IsPremiumStorageNotSupportedByVMSizeError(err error) bool {
 	return microerror.Cause(err) == premiumStorageNotSupportedByVMSizeError
 }
 
+var sizingPolicyViolationError = &microerror.Error{
+	Kind: "sizingPolicyViolationError",
+}
+
+// IsSizingPolicyViolationError asserts sizingPolicyViolationError.
+func IsSizingPolicyViolationError(err error) bool {
+	return microerror.Cause(err) == sizingPolicyViolationError
+}
+
 var invalidStorageAccountTypeError = &microerror.Error{
 	Kind: "invalidStorageAccountTypeError",
 }
@@ -138,3 +149,92 @@ var invalidStorageAccountTypeError = &microerror.Error{
 func IsInvalidStorageAccountTypeError(err error) bool {
 	return microerror.Cause(err) == invalidStorageAccountTypeError
 }
+
+var vmSizeNotAvailableError = &microerror.Error{
+	Kind: "vmSizeNotAvailableError",
+}
+
+// IsVMSizeNotAvailableError asserts vmSizeNotAvailableError.
+func IsVMSizeNotAvailableError(err error) bool {
+	return microerror.Cause(err) == vmSizeNotAvailableError
+}
+
+var gpuVMSizeNotOfferedError = &microerror.Error{
+	Kind: "gpuVMSizeNotOfferedError",
+}
+
+// IsGPUVMSizeNotOfferedError asserts gpuVMSizeNotOfferedError.
+func IsGPUVMSizeNotOfferedError(err error) bool {
+	return microerror.Cause(err) == gpuVMSizeNotOfferedError
+}
+
+var gpuNotAllowedForOrganizationError = &microerror.Error{
+	Kind: "gpuNotAllowedForOrganizationError",
+}
+
+// IsGPUNotAllowedForOrganizationError asserts gpuNotAllowedForOrganizationError.
+func IsGPUNotAllowedForOrganizationError(err error) bool {
+	return microerror.Cause(err) == gpuNotAllowedForOrganizationError
+}
+
+var ephemeralOSDiskNotSupportedError = &microerror.Error{
+	Kind: "ephemeralOSDiskNotSupportedError",
+}
+
+// IsEphemeralOSDiskNotSupportedError asserts ephemeralOSDiskNotSupportedError.
+func IsEphemeralOSDiskNotSupportedError(err error) bool {
+	return microerror.Cause(err) == ephemeralOSDiskNotSupportedError
+}
+
+var ephemeralOSDiskTooLargeError = &microerror.Error{
+	Kind: "ephemeralOSDiskTooLargeError",
+}
+
+// IsEphemeralOSDiskTooLargeError asserts ephemeralOSDiskTooLargeError.
+func IsEphemeralOSDiskTooLargeError(err error) bool {
+	return microerror.Cause(err) == ephemeralOSDiskTooLargeError
+}
+
+var ephemeralOSDiskWasChangedError = &microerror.Error{
+	Kind: "ephemeralOSDiskWasChangedError",
+}
+
+// IsEphemeralOSDiskWasChangedError asserts ephemeralOSDiskWasChangedError.
+func IsEphemeralOSDiskWasChangedError(err error) bool {
+	return microerror.Cause(err) == ephemeralOSDiskWasChangedError
+}
+
+var invalidOSDiskCachingTypeError = &microerror.Error{
+	Kind: "invalidOSDiskCachingTypeError",
+}
+
+// IsInvalidOSDiskCachingTypeError asserts invalidOSDiskCachingTypeError.
+func IsInvalidOSDiskCachingTypeError(err error) bool {
+	return microerror.Cause(err) == invalidOSDiskCachingTypeError
+}
+
+func init() {
+	errors.RegisterValidationErrors(
+		vmsizeDoesNotSupportAcceleratedNetworkingError,
+		datadisksFieldIsSetError,
+		locationWasChangedError,
+		acceleratedNetworkingWasChangedError,
+		spotVMOptionsWasChangedError,
+		storageAccountWasChangedError,
+		unexpectedLocationError,
+		sshFieldIsSetError,
+		insufficientMemoryError,
+		insufficientCPUError,
+		switchToVmSizeThatDoesNotSupportAcceleratedNetworkingError,
+		premiumStorageNotSupportedByVMSizeError,
+		sizingPolicyViolationError,
+		invalidStorageAccountTypeError,
+		vmSizeNotAvailableError,
+		gpuVMSizeNotOfferedError,
+		gpuNotAllowedForOrganizationError,
+		ephemeralOSDiskNotSupportedError,
+		ephemeralOSDiskTooLargeError,
+		ephemeralOSDiskWasChangedError,
+		invalidOSDiskCachingTypeError,
+	)
+}
@@ -0,0 +1,96 @@
+package azuremachinepool
+
+import (
+	"context"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
+	"github.com/giantswarm/microerror"
+	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
+	"github.com/giantswarm/azure-admission-controller/pkg/generic"
+)
+
+// gpuVMSizeNotOffered returns the error for a GPU VM size that is not offered
+// in the location, listing the GPU VM sizes that are, so users can pick one
+// of them instead.
+func gpuVMSizeNotOffered(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMachinePool *capzexp.AzureMachinePool) error {
+	offered, err := vmcaps.GPUVMSizes(ctx, azureMachinePool.Spec.Location)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(offered) == 0 {
+		return microerror.Maskf(gpuVMSizeNotOfferedError, "GPU VM size %s is not offered in %s, which has no GPU VM sizes at all", azureMachinePool.Spec.Template.VMSize, azureMachinePool.Spec.Location)
+	}
+
+	return microerror.Maskf(gpuVMSizeNotOfferedError, "GPU VM size %s is not offered in %s, the GPU VM sizes offered there are %s", azureMachinePool.Spec.Template.VMSize, azureMachinePool.Spec.Location, strings.Join(offered, ", "))
+}
+
+// checkGPUOrganization checks that the organization of the node pool is
+// allowed to use GPU VM sizes. All organizations are allowed when no allowed
+// organizations are configured.
+func (h *WebhookHandler) checkGPUOrganization(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMachinePool *capzexp.AzureMachinePool) error {
+	if len(h.gpuOrganizations) == 0 {
+		return nil
+	}
+
+	gpus, err := vmcaps.GPUs(ctx, azureMachinePool.Spec.Location, azureMachinePool.Spec.Template.VMSize)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if gpus == 0 {
+		return nil
+	}
+
+	organization := azureMachinePool.GetLabels()[label.Organization]
+	for _, allowed := range h.gpuOrganizations {
+		if organization == allowed {
+			return nil
+		}
+	}
+
+	return microerror.Maskf(gpuNotAllowedForOrganizationError, "VM size %s has %d GPUs, and organization %#q is not allowed to use GPU VM sizes", azureMachinePool.Spec.Template.VMSize, gpus, organization)
+}
+
+// checkMachinePoolGPUConventions checks that the MachinePool paired with a
+// node pool with a GPU VM size follows the GPU node pool conventions. When
+// the MachinePool doesn't exist yet, the conventions are checked once it is
+// created, by the MachinePool validation.
+func (h *WebhookHandler) checkMachinePoolGPUConventions(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMachinePool *capzexp.AzureMachinePool) error {
+	gpus, err := vmcaps.GPUs(ctx, azureMachinePool.Spec.Location, azureMachinePool.Spec.Template.VMSize)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if gpus == 0 {
+		return nil
+	}
+
+	var machinePools capiexp.MachinePoolList
+	err = h.ctrlClient.List(ctx, &machinePools, client.InNamespace(azureMachinePool.Namespace))
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for i := range machinePools.Items {
+		machinePool := &machinePools.Items[i]
+		if machinePool.Spec.Template.Spec.InfrastructureRef.Name != azureMachinePool.Name {
+			continue
+		}
+
+		err = generic.ValidateGPULabel(machinePool)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		err = generic.ValidateGPUTaint(machinePool)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
@@ -14,7 +14,7 @@ import (
 )
 
 func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{}) ([]mutator.PatchOperation, error) {
-	var result []mutator.PatchOperation
+	var builder mutator.PatchBuilder
 	azureMPCR, err := key.ToAzureMachinePoolPtr(object)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
@@ -25,25 +25,25 @@ func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{})
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	patch, err = h.ensureStorageAccountType(ctx, azureMPCR)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
+	builder.Add(patch)
+
+	patch, err = h.ensureOSDiskCachingType(ctx, azureMPCR)
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
+	builder.Add(patch)
 
 	patch, err = h.ensureDataDisks(ctx, azureMPCR)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	azureMPCR.Default()
 	{
@@ -55,16 +55,30 @@ func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{})
 
 		capiPatches = patches.SkipForPath("/spec/template/sshPublicKey", capiPatches)
 
-		result = append(result, capiPatches...)
+		builder.AddDefaults(capiPatches)
+	}
+
+	result, err := builder.Build()
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
+	}
+	for _, conflict := range builder.Conflicts() {
+		h.logger.LogCtx(ctx, "level", "warning", "message", conflict)
 	}
 
 	return result, nil
 }
 
 func (h *WebhookHandler) ensureStorageAccountType(ctx context.Context, mpCR *capzexp.AzureMachinePool) (*mutator.PatchOperation, error) {
-	if mpCR.Spec.Template.OSDisk.ManagedDisk.StorageAccountType == "" {
+	if storageAccountType(mpCR) == "" {
 		// We need to set the default value as it is missing.
 
+		if hasEphemeralOSDisk(mpCR) {
+			// The OS disk lives on the local storage of the VM, so premium
+			// storage doesn't make a difference for it.
+			return patchStorageAccountType(mpCR, string(compute.StorageAccountTypesStandardLRS)), nil
+		}
+
 		location := mpCR.Spec.Location
 		if location == "" {
 			// The location was empty and we are adding it using this same mutator.
@@ -92,12 +106,31 @@ func (h *WebhookHandler) ensureStorageAccountType(ctx context.Context, mpCR *cap
 			}
 		}
 
-		return mutator.PatchAdd("/spec/template/osDisk/managedDisk/storageAccountType", storageAccountType), nil
+		return patchStorageAccountType(mpCR, storageAccountType), nil
 	}
 
 	return nil, nil
 }
 
+func patchStorageAccountType(mpCR *capzexp.AzureMachinePool, storageAccountType string) *mutator.PatchOperation {
+	if mpCR.Spec.Template.OSDisk.ManagedDisk == nil {
+		return mutator.PatchAdd("/spec/template/osDisk/managedDisk", map[string]string{"storageAccountType": storageAccountType})
+	}
+
+	return mutator.PatchAdd("/spec/template/osDisk/managedDisk/storageAccountType", storageAccountType)
+}
+
+// ensureOSDiskCachingType defaults the caching type of ephemeral OS disks to
+// ReadOnly, the only one Azure supports for them. Managed OS disks are left to
+// the Azure default.
+func (h *WebhookHandler) ensureOSDiskCachingType(_ context.Context, mpCR *capzexp.AzureMachinePool) (*mutator.PatchOperation, error) {
+	if !hasEphemeralOSDisk(mpCR) || mpCR.Spec.Template.OSDisk.CachingType != "" {
+		return nil, nil
+	}
+
+	return mutator.PatchAdd("/spec/template/osDisk/cachingType", string(compute.CachingTypesReadOnly)), nil
+}
+
 func (h *WebhookHandler) ensureDataDisks(_ context.Context, mpCR *capzexp.AzureMachinePool) (*mutator.PatchOperation, error) {
 	if len(mpCR.Spec.Template.DataDisks) > 0 {
 		return nil, nil
@@ -77,6 +77,29 @@ func TestAzureMachinePoolCreateMutate(t *testing.T) {
 			},
 			errorMatcher: nil,
 		},
+		{
+			name:     "case 4: default caching type and storage account type of an ephemeral OS disk",
+			nodePool: builder.BuildAzureMachinePool(builder.VMSize("Standard_D4s_v3"), builder.StorageAccountType(""), builder.EphemeralOSDisk()),
+			patches: []mutator.PatchOperation{
+				{
+					Operation: "add",
+					Path:      "/spec/template/osDisk/managedDisk/storageAccountType",
+					Value:     "Standard_LRS",
+				},
+				{
+					Operation: "add",
+					Path:      "/spec/template/osDisk/cachingType",
+					Value:     "ReadOnly",
+				},
+			},
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 5: keep caching type of an ephemeral OS disk",
+			nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D4s_v3"), builder.EphemeralOSDisk(), builder.CachingType("ReadOnly")),
+			patches:      nil,
+			errorMatcher: nil,
+		},
 	}
 
 	for _, tc := range testCases {
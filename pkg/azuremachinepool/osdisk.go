@@ -0,0 +1,117 @@
+package azuremachinepool
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2020-06-01/compute"
+	"github.com/giantswarm/microerror"
+	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
+)
+
+// defaultOSDiskSizeGB is the size of the OS disk Azure uses when DiskSizeGB
+// is not set.
+const defaultOSDiskSizeGB = 30
+
+// hasEphemeralOSDisk returns true when the OS disk is placed on the local
+// storage of the VM instead of a managed disk.
+func hasEphemeralOSDisk(azureMachinePool *capzexp.AzureMachinePool) bool {
+	diffDiskSettings := azureMachinePool.Spec.Template.OSDisk.DiffDiskSettings
+	return diffDiskSettings != nil && diffDiskSettings.Option == string(compute.Local)
+}
+
+// osDiskSizeGB returns the size of the OS disk, or the size Azure defaults it
+// to when it is not set.
+func osDiskSizeGB(azureMachinePool *capzexp.AzureMachinePool) int {
+	if azureMachinePool.Spec.Template.OSDisk.DiskSizeGB == nil {
+		return defaultOSDiskSizeGB
+	}
+
+	return int(*azureMachinePool.Spec.Template.OSDisk.DiskSizeGB)
+}
+
+// checkEphemeralOSDisk checks that the VM size supports ephemeral OS disks and
+// that the OS disk fits into the cache disk or the temp disk of the VM size,
+// which is where Azure places it. Node pools with a managed OS disk are not
+// checked.
+func checkEphemeralOSDisk(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMachinePool *capzexp.AzureMachinePool) error {
+	if !hasEphemeralOSDisk(azureMachinePool) {
+		return nil
+	}
+
+	location := azureMachinePool.Spec.Location
+	vmSize := azureMachinePool.Spec.Template.VMSize
+
+	supported, err := vmcaps.HasCapability(ctx, location, vmSize, vmcapabilities.CapabilityEphemeralOSDiskSupported)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !supported {
+		return microerror.Maskf(ephemeralOSDiskNotSupportedError, "VM size %s does not support ephemeral OS disks", vmSize)
+	}
+
+	cachingType := azureMachinePool.Spec.Template.OSDisk.CachingType
+	if cachingType != "" && cachingType != string(compute.CachingTypesReadOnly) {
+		return microerror.Maskf(invalidOSDiskCachingTypeError, "Caching type %q is invalid for an ephemeral OS disk. The only allowed value is %q", cachingType, string(compute.CachingTypesReadOnly))
+	}
+
+	cacheDiskSizeGB, err := vmcaps.CacheDiskSizeGB(ctx, location, vmSize)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	tempDiskSizeGB, err := vmcaps.TempDiskSizeGB(ctx, location, vmSize)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	diskSizeGB := osDiskSizeGB(azureMachinePool)
+	if diskSizeGB > cacheDiskSizeGB && diskSizeGB > tempDiskSizeGB {
+		return microerror.Maskf(ephemeralOSDiskTooLargeError, "The ephemeral OS disk of %d GB does not fit into the cache disk (%d GB) nor the temp disk (%d GB) of VM size %s", diskSizeGB, cacheDiskSizeGB, tempDiskSizeGB, vmSize)
+	}
+
+	return nil
+}
+
+// checkEphemeralOSDiskUnchanged checks that the OS disk of an existing node
+// pool is not switched between an ephemeral and a managed disk, as Azure can't
+// change that for existing VMs.
+func checkEphemeralOSDiskUnchanged(azureMPOldCR *capzexp.AzureMachinePool, azureMPNewCR *capzexp.AzureMachinePool) error {
+	oldEphemeral := hasEphemeralOSDisk(azureMPOldCR)
+	newEphemeral := hasEphemeralOSDisk(azureMPNewCR)
+
+	switch {
+	case !oldEphemeral && newEphemeral:
+		return microerror.Maskf(ephemeralOSDiskWasChangedError, "It is not possible to switch the OS disk of an existing node pool from a managed disk to an ephemeral disk")
+	case oldEphemeral && !newEphemeral:
+		return microerror.Maskf(ephemeralOSDiskWasChangedError, "It is not possible to switch the OS disk of an existing node pool from an ephemeral disk to a managed disk")
+	}
+
+	return nil
+}
+
+// checkEphemeralOSDiskUpdateIsValid checks that a node pool with an ephemeral
+// OS disk can keep it when the VM size is changed.
+func checkEphemeralOSDiskUpdateIsValid(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMPOldCR *capzexp.AzureMachinePool, azureMPNewCR *capzexp.AzureMachinePool) error {
+	if azureMPOldCR.Spec.Template.VMSize == azureMPNewCR.Spec.Template.VMSize &&
+		osDiskSizeGB(azureMPOldCR) == osDiskSizeGB(azureMPNewCR) {
+		return nil
+	}
+
+	err := checkEphemeralOSDisk(ctx, vmcaps, azureMPNewCR)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// storageAccountType returns the storage account type of the OS disk, which
+// is empty when the OS disk has no managed disk parameters.
+func storageAccountType(azureMachinePool *capzexp.AzureMachinePool) string {
+	if azureMachinePool.Spec.Template.OSDisk.ManagedDisk == nil {
+		return ""
+	}
+
+	return azureMachinePool.Spec.Template.OSDisk.ManagedDisk.StorageAccountType
+}
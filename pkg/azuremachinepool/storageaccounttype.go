@@ -11,7 +11,7 @@ import (
 )
 
 func checkStorageAccountTypeIsValid(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMachinePool *capzexp.AzureMachinePool) error {
-	selectedStorageAccount := azureMachinePool.Spec.Template.OSDisk.ManagedDisk.StorageAccountType
+	selectedStorageAccount := storageAccountType(azureMachinePool)
 
 	if selectedStorageAccount != string(compute.StorageAccountTypesStandardLRS) &&
 		selectedStorageAccount != string(compute.StorageAccountTypesPremiumLRS) {
@@ -4,61 +4,65 @@ import (
 	"context"
 
 	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 	"github.com/giantswarm/azure-admission-controller/pkg/key"
 )
 
+var (
+	vmSizePath                = field.NewPath("spec", "template", "vmSize")
+	acceleratedNetworkingPath = field.NewPath("spec", "template", "acceleratedNetworking")
+	storageAccountTypePath    = field.NewPath("spec", "template", "osDisk", "managedDisk", "storageAccountType")
+	osDiskPath                = field.NewPath("spec", "template", "osDisk")
+	sshPublicKeyPath          = field.NewPath("spec", "template", "sshPublicKey")
+	dataDisksPath             = field.NewPath("spec", "template", "dataDisks")
+	spotVMOptionsPath         = field.NewPath("spec", "template", "spotVMOptions")
+	locationPath              = field.NewPath("spec", "location")
+	conditionsPath            = field.NewPath("status", "conditions")
+)
+
 func (h *WebhookHandler) OnCreateValidate(ctx context.Context, object interface{}) error {
 	azureMPNewCR, err := key.ToAzureMachinePoolPtr(object)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	err = azureMPNewCR.ValidateCreate()
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = generic.ValidateOrganizationLabelMatchesCluster(ctx, h.ctrlClient, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
+	// Failing to get the VM capabilities is not a validation failure, so it is
+	// returned before running any check.
 	vmcaps, err := h.vmcapsFactory.GetClient(ctx, h.ctrlClient, azureMPNewCR.ObjectMeta)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	err = checkInstanceTypeIsValid(ctx, vmcaps, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
 
-	err = checkAcceleratedNetworking(ctx, vmcaps, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(nil, azureMPNewCR.ValidateCreate())
+	validationErrors.Add(generic.OrganizationLabelPath, generic.ValidateOrganizationLabelMatchesCluster(ctx, h.ctrlClient, azureMPNewCR))
 
-	err = checkStorageAccountTypeIsValid(ctx, vmcaps, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
+	err = h.checkInstanceTypeIsValid(ctx, vmcaps, azureMPNewCR)
+	if err == nil {
+		err = checkInstanceTypeIsAvailable(ctx, vmcaps, azureMPNewCR)
 	}
+	validationErrors.Add(vmSizePath, enforcement.Apply(ctx, enforcement.AzureMachinePoolInstanceType, err))
 
-	err = checkSSHKeyIsEmpty(ctx, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
+	// These checks look up the VM size, so they only make sense when it is a
+	// valid one.
+	if err == nil {
+		validationErrors.Add(acceleratedNetworkingPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolAcceleratedNetworking, checkAcceleratedNetworking(ctx, vmcaps, azureMPNewCR)))
+		validationErrors.Add(storageAccountTypePath, enforcement.Apply(ctx, enforcement.AzureMachinePoolStorageAccountType, checkStorageAccountTypeIsValid(ctx, vmcaps, azureMPNewCR)))
+		validationErrors.Add(osDiskPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolEphemeralOSDisk, checkEphemeralOSDisk(ctx, vmcaps, azureMPNewCR)))
+		validationErrors.Add(vmSizePath, enforcement.Apply(ctx, enforcement.AzureMachinePoolGPU, h.checkGPUOrganization(ctx, vmcaps, azureMPNewCR)))
+		validationErrors.Add(vmSizePath, enforcement.Apply(ctx, enforcement.AzureMachinePoolAzureQuota, h.checkAzureQuota(ctx, vmcaps, azureMPNewCR)))
 	}
 
-	err = checkDataDisks(ctx, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = checkLocation(*azureMPNewCR, h.location)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(sshPublicKeyPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolSSHKey, checkSSHKeyIsEmpty(ctx, azureMPNewCR)))
+	validationErrors.Add(dataDisksPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolDataDisks, checkDataDisks(ctx, azureMPNewCR)))
+	validationErrors.Add(locationPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolLocation, checkLocation(*azureMPNewCR, h.location)))
 
-	return nil
+	return microerror.Mask(validationErrors.Err())
 }
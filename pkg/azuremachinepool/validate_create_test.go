@@ -2,6 +2,7 @@ package azuremachinepool
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"testing"
 
@@ -15,8 +16,13 @@ import (
 	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/azure-admission-controller/internal/azurequota"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 	builder "github.com/giantswarm/azure-admission-controller/internal/test/azuremachinepool"
+	mpbuilder "github.com/giantswarm/azure-admission-controller/internal/test/machinepool"
 	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
@@ -31,8 +37,11 @@ func TestAzureMachinePoolCreateValidate(t *testing.T) {
 		"Standard_D2s_v3",
 	}
 	type testCase struct {
-		name         string
-		nodePool     *capzexp.AzureMachinePool
+		name     string
+		nodePool *capzexp.AzureMachinePool
+		// machinePool is the MachinePool of the node pool, when it is created
+		// before the AzureMachinePool.
+		machinePool  *capiexp.MachinePool
 		errorMatcher func(err error) bool
 	}
 
@@ -62,9 +71,13 @@ func TestAzureMachinePoolCreateValidate(t *testing.T) {
 	{
 		instanceType := "this_is_a_random_name"
 		testCases = append(testCases, testCase{
-			name:         fmt.Sprintf("case %d: instance type %s with accelerated networking enabled", len(testCases), instanceType),
-			nodePool:     builder.BuildAzureMachinePool(builder.VMSize(instanceType), builder.AcceleratedNetworking(to.BoolPtr(true))),
-			errorMatcher: vmcapabilities.IsSkuNotFoundError,
+			name:     fmt.Sprintf("case %d: instance type %s with accelerated networking enabled", len(testCases), instanceType),
+			nodePool: builder.BuildAzureMachinePool(builder.VMSize(instanceType), builder.AcceleratedNetworking(to.BoolPtr(true))),
+			// The failed SKU lookup fails the request, it is not reported
+			// as a violation.
+			errorMatcher: func(err error) bool {
+				return vmcapabilities.IsSkuNotFoundError(err) && !errors.IsValidation(err)
+			},
 		})
 	}
 
@@ -87,6 +100,12 @@ func TestAzureMachinePoolCreateValidate(t *testing.T) {
 		})
 	}
 
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: instance type not available for the subscription", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D8_v3")),
+		errorMatcher: IsVMSizeNotAvailableError,
+	})
+
 	testCases = append(testCases, testCase{
 		name:         fmt.Sprintf("case %d: invalid location", len(testCases)-1),
 		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D4_v3"), builder.Location("eastgalicia")),
@@ -99,6 +118,88 @@ func TestAzureMachinePoolCreateValidate(t *testing.T) {
 		errorMatcher: generic.IsNodepoolOrgDoesNotMatchClusterOrg,
 	})
 
+	testCases = append(testCases, testCase{
+		name:     fmt.Sprintf("case %d: invalid location and organization are both reported", len(testCases)-1),
+		nodePool: builder.BuildAzureMachinePool(builder.VMSize("Standard_D4_v3"), builder.Location("eastgalicia"), builder.Organization("wrongorg")),
+		errorMatcher: func(err error) bool {
+			var validationErrors *errors.ValidationErrors
+			if !stderrors.As(err, &validationErrors) || len(validationErrors.Causes()) != 2 {
+				return false
+			}
+
+			return generic.IsNodepoolOrgDoesNotMatchClusterOrg(err) && stderrors.Is(err, unexpectedLocationError)
+		},
+	})
+
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: GPU instance type for an allowed organization", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_NC6s_v3")),
+		errorMatcher: nil,
+	})
+
+	testCases = append(testCases, testCase{
+		name:     fmt.Sprintf("case %d: GPU instance type for an organization that is not allowed", len(testCases)-1),
+		nodePool: builder.BuildAzureMachinePool(builder.VMSize("Standard_NC6s_v3"), builder.Organization("wrongorg")),
+		errorMatcher: func(err error) bool {
+			return stderrors.Is(err, gpuNotAllowedForOrganizationError)
+		},
+	})
+
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: GPU instance type not offered in the location", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_ND40rs_v2")),
+		errorMatcher: IsGPUVMSizeNotOfferedError,
+	})
+
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: ephemeral OS disk fitting into the cache disk", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4"), builder.EphemeralOSDisk(), builder.CachingType("ReadOnly")),
+		errorMatcher: nil,
+	})
+
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: ephemeral OS disk fitting into the temp disk only", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4"), builder.EphemeralOSDisk(), builder.OSDiskSizeGB(128)),
+		errorMatcher: nil,
+	})
+
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: ephemeral OS disk larger than the cache and temp disks", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4"), builder.EphemeralOSDisk(), builder.OSDiskSizeGB(256)),
+		errorMatcher: IsEphemeralOSDiskTooLargeError,
+	})
+
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: ephemeral OS disk with read write caching", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4"), builder.EphemeralOSDisk(), builder.CachingType("ReadWrite")),
+		errorMatcher: IsInvalidOSDiskCachingTypeError,
+	})
+
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: ephemeral OS disk on a VM size not supporting it", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D4_v3"), builder.EphemeralOSDisk()),
+		errorMatcher: IsEphemeralOSDiskNotSupportedError,
+	})
+
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: VM size within the Azure family vCPU quota", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_E4s_v3")),
+		errorMatcher: nil,
+	})
+
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: VM size exceeding the Azure family vCPU quota", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_E8s_v3")),
+		errorMatcher: azurequota.IsInsufficientQuota,
+	})
+
+	testCases = append(testCases, testCase{
+		name:         fmt.Sprintf("case %d: existing MachinePool scaling beyond the Azure family vCPU quota", len(testCases)-1),
+		nodePool:     builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_E4s_v3")),
+		machinePool:  mpbuilder.BuildMachinePool(mpbuilder.AzureMachinePool("np001"), mpbuilder.Replicas(2)),
+		errorMatcher: azurequota.IsInsufficientQuota,
+	})
+
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			var err error
@@ -143,6 +244,13 @@ func TestAzureMachinePoolCreateValidate(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			if tc.machinePool != nil {
+				err = ctrlClient.Create(ctx, tc.machinePool)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
 			stubbedSKUs := map[string]compute.ResourceSku{
 				"Standard_A2_v2": {
 					Name: to.StringPtr("Standard_A2_v2"),
@@ -246,16 +354,131 @@ func TestAzureMachinePoolCreateValidate(t *testing.T) {
 						},
 					},
 				},
+				"Standard_D4ds_v4": {
+					Name: to.StringPtr("Standard_D4ds_v4"),
+					Capabilities: &[]compute.ResourceSkuCapabilities{
+						{
+							Name:  to.StringPtr("AcceleratedNetworkingEnabled"),
+							Value: to.StringPtr("True"),
+						},
+						{
+							Name:  to.StringPtr("vCPUs"),
+							Value: to.StringPtr("4"),
+						},
+						{
+							Name:  to.StringPtr("MemoryGB"),
+							Value: to.StringPtr("16"),
+						},
+						{
+							Name:  to.StringPtr("EphemeralOSDiskSupported"),
+							Value: to.StringPtr("True"),
+						},
+						{
+							Name:  to.StringPtr("CachedDiskBytes"),
+							Value: to.StringPtr("107374182400"),
+						},
+						{
+							Name:  to.StringPtr("MaxResourceVolumeMB"),
+							Value: to.StringPtr("153600"),
+						},
+					},
+				},
+				"Standard_D8_v3": {
+					Name: to.StringPtr("Standard_D8_v3"),
+					Capabilities: &[]compute.ResourceSkuCapabilities{
+						{
+							Name:  to.StringPtr("vCPUs"),
+							Value: to.StringPtr("8"),
+						},
+						{
+							Name:  to.StringPtr("MemoryGB"),
+							Value: to.StringPtr("32"),
+						},
+					},
+					Restrictions: &[]compute.ResourceSkuRestrictions{
+						{
+							Type:       compute.Location,
+							Values:     &[]string{"westeurope"},
+							ReasonCode: compute.NotAvailableForSubscription,
+						},
+					},
+				},
+				"Standard_E4s_v3": {
+					Name:   to.StringPtr("Standard_E4s_v3"),
+					Family: to.StringPtr("standardESv3Family"),
+					Capabilities: &[]compute.ResourceSkuCapabilities{
+						{
+							Name:  to.StringPtr("AcceleratedNetworkingEnabled"),
+							Value: to.StringPtr("True"),
+						},
+						{
+							Name:  to.StringPtr("vCPUs"),
+							Value: to.StringPtr("4"),
+						},
+						{
+							Name:  to.StringPtr("MemoryGB"),
+							Value: to.StringPtr("32"),
+						},
+					},
+				},
+				"Standard_E8s_v3": {
+					Name:   to.StringPtr("Standard_E8s_v3"),
+					Family: to.StringPtr("standardESv3Family"),
+					Capabilities: &[]compute.ResourceSkuCapabilities{
+						{
+							Name:  to.StringPtr("AcceleratedNetworkingEnabled"),
+							Value: to.StringPtr("True"),
+						},
+						{
+							Name:  to.StringPtr("vCPUs"),
+							Value: to.StringPtr("8"),
+						},
+						{
+							Name:  to.StringPtr("MemoryGB"),
+							Value: to.StringPtr("64"),
+						},
+					},
+				},
+				"Standard_NC6s_v3": {
+					Name: to.StringPtr("Standard_NC6s_v3"),
+					Capabilities: &[]compute.ResourceSkuCapabilities{
+						{
+							Name:  to.StringPtr("AcceleratedNetworkingEnabled"),
+							Value: to.StringPtr("True"),
+						},
+						{
+							Name:  to.StringPtr("vCPUs"),
+							Value: to.StringPtr("6"),
+						},
+						{
+							Name:  to.StringPtr("MemoryGB"),
+							Value: to.StringPtr("112"),
+						},
+						{
+							Name:  to.StringPtr("GPUs"),
+							Value: to.StringPtr("1"),
+						},
+					},
+				},
 			}
 
 			vmcapsFactory := unittest.NewVMCapsStubFactory(stubbedSKUs, newLogger)
 
+			// Only 4 vCPUs are left in the E v3 family.
+			stubbedUsages := []azurequota.Usage{
+				{Name: azurequota.UsageRegionalVCPUs, Current: 200, Limit: 1000},
+				{Name: "standardESv3Family", Current: 60, Limit: 64},
+			}
+			azureQuotaFactory := unittest.NewAzureQuotaStubFactory(stubbedUsages, newLogger)
+
 			handler, err := NewWebhookHandler(WebhookHandlerConfig{
-				CtrlClient:    ctrlClient,
-				Decoder:       unittest.NewFakeDecoder(),
-				Location:      "westeurope",
-				Logger:        newLogger,
-				VMcapsFactory: vmcapsFactory,
+				AzureQuotaFactory: azureQuotaFactory,
+				CtrlClient:        ctrlClient,
+				Decoder:           unittest.NewFakeDecoder(),
+				GPUOrganizations:  []string{"giantswarm"},
+				Location:          "westeurope",
+				Logger:            newLogger,
+				VMcapsFactory:     vmcapsFactory,
 			})
 			if err != nil {
 				t.Fatal(err)
@@ -278,3 +501,51 @@ func TestAzureMachinePoolCreateValidate(t *testing.T) {
 		})
 	}
 }
+
+// failingVMCapsFactory fails to create the VM capabilities client, like when
+// the Azure credentials of the cluster can't be looked up.
+type failingVMCapsFactory struct {
+	err error
+}
+
+func (f failingVMCapsFactory) GetClient(_ context.Context, _ client.Client, _ metav1.ObjectMeta) (*vmcapabilities.VMSKU, error) {
+	return nil, f.err
+}
+
+func TestAzureMachinePoolValidateInfrastructureError(t *testing.T) {
+	infrastructureError := stderrors.New("azure API is unavailable")
+
+	newLogger, err := micrologger.New(micrologger.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler, err := NewWebhookHandler(WebhookHandlerConfig{
+		CtrlClient:    unittest.FakeK8sClient().CtrlClient(),
+		Decoder:       unittest.NewFakeDecoder(),
+		Location:      "westeurope",
+		Logger:        newLogger,
+		VMcapsFactory: failingVMCapsFactory{err: infrastructureError},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodePool := builder.BuildAzureMachinePool(builder.VMSize("Standard_D4_v3"))
+
+	for _, validate := range []func() error{
+		func() error { return handler.OnCreateValidate(context.Background(), nodePool) },
+		func() error { return handler.OnUpdateValidate(context.Background(), nodePool, nodePool) },
+	} {
+		err = validate()
+		if !stderrors.Is(err, infrastructureError) {
+			t.Fatalf("expected %#v got %#v", infrastructureError, err)
+		}
+
+		// The error must not be reported to the user as a validation failure.
+		var validationErrors *errors.ValidationErrors
+		if stderrors.As(err, &validationErrors) {
+			t.Fatalf("expected infrastructure error not to be a validation failure, got causes %v", validationErrors.Causes())
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package azuremachinepool
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/conditions"
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/generic"
+	"github.com/giantswarm/azure-admission-controller/pkg/key"
+)
+
+// OnStatusUpdateValidate validates the conditions of the AzureMachinePool,
+// which are only changed through the status subresource.
+func (h *WebhookHandler) OnStatusUpdateValidate(ctx context.Context, oldObject interface{}, object interface{}) error {
+	azureMPNewCR, err := key.ToAzureMachinePoolPtr(object)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !azureMPNewCR.GetDeletionTimestamp().IsZero() {
+		h.logger.LogCtx(ctx, "level", "debug", "message", "The object is being deleted so we don't validate it")
+		return nil
+	}
+
+	azureMPOldCR, err := key.ToAzureMachinePoolPtr(oldObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	capi, err := generic.IsCAPIRelease(azureMPNewCR)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if capi {
+		return nil
+	}
+
+	var validationErrors errors.ValidationErrors
+
+	validationErrors.Add(conditionsPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolConditions, conditions.ValidateAzureMachinePoolConditions(azureMPOldCR, azureMPNewCR)))
+
+	return microerror.Mask(validationErrors.Err())
+}
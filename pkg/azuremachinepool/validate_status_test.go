@@ -0,0 +1,106 @@
+package azuremachinepool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	corev1 "k8s.io/api/core/v1"
+	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
+	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/azuremachinepool"
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+func TestAzureMachinePoolStatusUpdateValidate(t *testing.T) {
+	supportedInstanceType := "Standard_D4_v3"
+	type testCase struct {
+		name         string
+		oldNodePool  *capzexp.AzureMachinePool
+		newNodePool  *capzexp.AzureMachinePool
+		errorMatcher func(err error) bool
+	}
+
+	testCases := []testCase{
+		{
+			name:         "case 0: Ready condition set to False with a reason",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType), builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType), builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse, Reason: capz.ScaleSetProvisionFailedReason}})),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 1: Ready condition set to False without a reason",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType), builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType), builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse}})),
+			errorMatcher: errors.IsInvalidConditionModification,
+		},
+		{
+			name:         "case 2: Ready condition removed",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType), builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType)),
+			errorMatcher: errors.IsInvalidConditionModification,
+		},
+		{
+			name:         "case 3: Ready condition set to an invalid status",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType), builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionUnknown}})),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType), builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: "Maybe"}})),
+			errorMatcher: errors.IsInvalidConditionStatus,
+		},
+		{
+			name:         "case 4: unchanged Ready condition without a reason",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType), builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse}})),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType), builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse}})),
+			errorMatcher: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+
+			// Create a new logger that is used by all admitters.
+			var newLogger micrologger.Logger
+			{
+				newLogger, err = micrologger.New(micrologger.Config{})
+				if err != nil {
+					panic(microerror.JSON(err))
+				}
+			}
+
+			ctx := context.Background()
+			fakeK8sClient := unittest.FakeK8sClient()
+			ctrlClient := fakeK8sClient.CtrlClient()
+
+			handler, err := NewWebhookHandler(WebhookHandlerConfig{
+				CtrlClient:    ctrlClient,
+				Decoder:       unittest.NewFakeDecoder(),
+				Location:      "westeurope",
+				Logger:        newLogger,
+				VMcapsFactory: unittest.NewVMCapsStubFactory(map[string]compute.ResourceSku{}, newLogger),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Run validating webhook handler on AzureMachinePool status update.
+			err = handler.OnStatusUpdateValidate(ctx, tc.oldNodePool, tc.newNodePool)
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
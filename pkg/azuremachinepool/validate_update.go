@@ -2,10 +2,15 @@ package azuremachinepool
 
 import (
 	"context"
+	"reflect"
 
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
 	"github.com/giantswarm/microerror"
 	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/quota"
 	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 	"github.com/giantswarm/azure-admission-controller/pkg/key"
@@ -34,57 +39,57 @@ func (h *WebhookHandler) OnUpdateValidate(ctx context.Context, oldObject interfa
 		return nil
 	}
 
-	err = azureMPNewCR.ValidateUpdate(azureMPOldCR)
+	// Failing to get the VM capabilities is not a validation failure, so it is
+	// returned before running any check.
+	vmcaps, err := h.vmcapsFactory.GetClient(ctx, h.ctrlClient, azureMPNewCR.ObjectMeta)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	err = generic.ValidateOrganizationLabelUnchanged(azureMPOldCR, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
 
-	vmcaps, err := h.vmcapsFactory.GetClient(ctx, h.ctrlClient, azureMPNewCR.ObjectMeta)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(nil, azureMPNewCR.ValidateUpdate(azureMPOldCR))
+	validationErrors.Add(generic.OrganizationLabelPath, generic.ValidateOrganizationLabelUnchanged(azureMPOldCR, azureMPNewCR))
 
-	err = checkInstanceTypeIsValid(ctx, vmcaps, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	err = h.checkInstanceTypeUpdateIsValid(ctx, vmcaps, azureMPOldCR, azureMPNewCR)
+	validationErrors.Add(vmSizePath, enforcement.Apply(ctx, enforcement.AzureMachinePoolInstanceType, err))
 
-	err = h.checkAcceleratedNetworkingUpdateIsValid(ctx, vmcaps, azureMPOldCR, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
+	// These checks look up the VM size, so they only make sense when it is a
+	// valid one.
+	if err == nil {
+		validationErrors.Add(acceleratedNetworkingPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolAcceleratedNetworking, h.checkAcceleratedNetworkingUpdateIsValid(ctx, vmcaps, azureMPOldCR, azureMPNewCR)))
+		validationErrors.Add(vmSizePath, enforcement.Apply(ctx, enforcement.AzureMachinePoolInstanceType, h.checkInstanceTypeChangeIsValid(ctx, vmcaps, azureMPOldCR, azureMPNewCR)))
+		validationErrors.Add(vmSizePath, enforcement.Apply(ctx, enforcement.AzureMachinePoolGPU, h.checkGPUChangeIsValid(ctx, vmcaps, azureMPOldCR, azureMPNewCR)))
+		validationErrors.Add(osDiskPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolEphemeralOSDisk, checkEphemeralOSDiskUpdateIsValid(ctx, vmcaps, azureMPOldCR, azureMPNewCR)))
 	}
 
-	err = h.checkInstanceTypeChangeIsValid(ctx, vmcaps, azureMPOldCR, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
+	// A bigger VM size increases the vCPUs of all nodes of the node pool.
+	if azureMPOldCR.Spec.Template.VMSize != azureMPNewCR.Spec.Template.VMSize {
+		validationErrors.Add(vmSizePath, enforcement.Apply(ctx, enforcement.AzureMachinePoolQuota, h.quotaChecker.Check(ctx, azureMPNewCR.GetLabels()[label.Organization], quota.Pending{AzureMachinePool: azureMPNewCR})))
 	}
 
-	err = h.checkSpotVMOptionsUnchanged(ctx, azureMPOldCR, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(spotVMOptionsPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolSpotVMOptions, h.checkSpotVMOptionsUnchanged(ctx, azureMPOldCR, azureMPNewCR)))
+	validationErrors.Add(storageAccountTypePath, enforcement.Apply(ctx, enforcement.AzureMachinePoolStorageAccountType, h.checkStorageAccountTypeUnchanged(ctx, azureMPOldCR, azureMPNewCR)))
+	validationErrors.Add(osDiskPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolEphemeralOSDisk, checkEphemeralOSDiskUnchanged(azureMPOldCR, azureMPNewCR)))
+	validationErrors.Add(sshPublicKeyPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolSSHKey, checkSSHKeyIsEmpty(ctx, azureMPNewCR)))
+	validationErrors.Add(dataDisksPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolDataDisks, checkDataDisks(ctx, azureMPNewCR)))
+	validationErrors.Add(locationPath, enforcement.Apply(ctx, enforcement.AzureMachinePoolLocation, checkLocationUnchanged(*azureMPOldCR, *azureMPNewCR)))
 
-	err = h.checkStorageAccountTypeUnchanged(ctx, azureMPOldCR, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	return microerror.Mask(validationErrors.Err())
+}
 
-	err = checkSSHKeyIsEmpty(ctx, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
+func (h *WebhookHandler) checkAcceleratedNetworkingUpdateIsValid(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMPOldCR *capzexp.AzureMachinePool, azureMPNewCR *capzexp.AzureMachinePool) error {
+	if hasAcceleratedNetworkingPropertyChanged(ctx, azureMPOldCR, azureMPNewCR) {
+		return microerror.Maskf(acceleratedNetworkingWasChangedError, "It is not possible to change the AcceleratedNetworking on an existing node pool")
 	}
 
-	err = checkDataDisks(ctx, azureMPNewCR)
-	if err != nil {
-		return microerror.Mask(err)
+	if azureMPOldCR.Spec.Template.VMSize == azureMPNewCR.Spec.Template.VMSize {
+		return nil
 	}
 
-	err = checkLocationUnchanged(*azureMPOldCR, *azureMPNewCR)
+	err := checkAcceleratedNetworking(ctx, vmcaps, azureMPNewCR)
 	if err != nil {
 		return microerror.Mask(err)
 	}
@@ -92,16 +97,17 @@ func (h *WebhookHandler) OnUpdateValidate(ctx context.Context, oldObject interfa
 	return nil
 }
 
-func (h *WebhookHandler) checkAcceleratedNetworkingUpdateIsValid(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMPOldCR *capzexp.AzureMachinePool, azureMPNewCR *capzexp.AzureMachinePool) error {
-	if hasAcceleratedNetworkingPropertyChanged(ctx, azureMPOldCR, azureMPNewCR) {
-		return microerror.Maskf(acceleratedNetworkingWasChangedError, "It is not possible to change the AcceleratedNetworking on an existing node pool")
-	}
-
-	if azureMPOldCR.Spec.Template.VMSize == azureMPNewCR.Spec.Template.VMSize {
+// checkInstanceTypeUpdateIsValid checks the VM size and the sizing policy when
+// the VM size or the data disks are changed. Existing node pools are not
+// checked otherwise, so they can still be updated when the VM size is not
+// offered anymore or the sizing policy is changed later.
+func (h *WebhookHandler) checkInstanceTypeUpdateIsValid(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMPOldCR *capzexp.AzureMachinePool, azureMPNewCR *capzexp.AzureMachinePool) error {
+	if azureMPOldCR.Spec.Template.VMSize == azureMPNewCR.Spec.Template.VMSize &&
+		reflect.DeepEqual(azureMPOldCR.Spec.Template.DataDisks, azureMPNewCR.Spec.Template.DataDisks) {
 		return nil
 	}
 
-	err := checkAcceleratedNetworking(ctx, vmcaps, azureMPNewCR)
+	err := h.checkInstanceTypeIsValid(ctx, vmcaps, azureMPNewCR)
 	if err != nil {
 		return microerror.Mask(err)
 	}
@@ -112,6 +118,13 @@ func (h *WebhookHandler) checkAcceleratedNetworkingUpdateIsValid(ctx context.Con
 func (h *WebhookHandler) checkInstanceTypeChangeIsValid(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMPOldCR *capzexp.AzureMachinePool, azureMPNewCR *capzexp.AzureMachinePool) error {
 	// Check if the instance type has changed.
 	if azureMPOldCR.Spec.Template.VMSize != azureMPNewCR.Spec.Template.VMSize {
+		// The restrictions are only checked for the new VM size, so that node
+		// pools with a VM size that got restricted later can still be updated.
+		err := checkInstanceTypeIsAvailable(ctx, vmcaps, azureMPNewCR)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
 		oldPremium, err := vmcaps.HasCapability(ctx, azureMPOldCR.Spec.Location, azureMPOldCR.Spec.Template.VMSize, vmcapabilities.CapabilityPremiumIO)
 		if err != nil {
 			return microerror.Mask(err)
@@ -131,6 +144,27 @@ func (h *WebhookHandler) checkInstanceTypeChangeIsValid(ctx context.Context, vmc
 	return nil
 }
 
+// checkGPUChangeIsValid checks the GPU rules when the VM size is changed.
+// Existing node pools are not checked otherwise, so they can still be updated
+// when the rules are changed later.
+func (h *WebhookHandler) checkGPUChangeIsValid(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMPOldCR *capzexp.AzureMachinePool, azureMPNewCR *capzexp.AzureMachinePool) error {
+	if azureMPOldCR.Spec.Template.VMSize == azureMPNewCR.Spec.Template.VMSize {
+		return nil
+	}
+
+	err := h.checkGPUOrganization(ctx, vmcaps, azureMPNewCR)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = h.checkMachinePoolGPUConventions(ctx, vmcaps, azureMPNewCR)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
 func (h *WebhookHandler) checkSpotVMOptionsUnchanged(_ context.Context, azureMPOldCR *capzexp.AzureMachinePool, azureMPNewCR *capzexp.AzureMachinePool) error {
 
 	switch {
@@ -155,7 +189,7 @@ func (h *WebhookHandler) checkSpotVMOptionsUnchanged(_ context.Context, azureMPO
 
 // Checks if the storage account type of the osDisk is changed. This is never allowed.
 func (h *WebhookHandler) checkStorageAccountTypeUnchanged(_ context.Context, azureMPOldCR *capzexp.AzureMachinePool, azureMPNewCR *capzexp.AzureMachinePool) error {
-	if azureMPOldCR.Spec.Template.OSDisk.ManagedDisk.StorageAccountType != azureMPNewCR.Spec.Template.OSDisk.ManagedDisk.StorageAccountType {
+	if storageAccountType(azureMPOldCR) != storageAccountType(azureMPNewCR) {
 		return microerror.Maskf(storageAccountWasChangedError, "Changing the storage account type of the OS disk is not allowed.")
 	}
 
@@ -11,8 +11,12 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	capz "sigs.k8s.io/cluster-api-provider-azure/api/v1beta1"
 	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 
 	builder "github.com/giantswarm/azure-admission-controller/internal/test/azuremachinepool"
+	mpbuilder "github.com/giantswarm/azure-admission-controller/internal/test/machinepool"
+	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
+	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
 )
 
@@ -27,9 +31,11 @@ func TestAzureMachinePoolUpdateValidate(t *testing.T) {
 	premiumStorageInstanceType := "Standard_D4s_v3"
 	standardStorageInstanceType := "Standard_D4_v3"
 	type testCase struct {
-		name         string
-		oldNodePool  *capzexp.AzureMachinePool
-		newNodePool  *capzexp.AzureMachinePool
+		name        string
+		oldNodePool *capzexp.AzureMachinePool
+		newNodePool *capzexp.AzureMachinePool
+		// machinePool is the MachinePool of the node pool.
+		machinePool  *capiexp.MachinePool
 		errorMatcher func(err error) bool
 	}
 
@@ -177,6 +183,82 @@ func TestAzureMachinePoolUpdateValidate(t *testing.T) {
 			newNodePool:  builder.BuildAzureMachinePool(builder.Location("northeastitaly"), builder.WithDeletionTimestamp()),
 			errorMatcher: nil,
 		},
+		{
+			name:         "case 22: switch from a managed to an ephemeral OS disk",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4")),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4"), builder.EphemeralOSDisk()),
+			errorMatcher: IsEphemeralOSDiskWasChangedError,
+		},
+		{
+			name:         "case 23: switch from an ephemeral to a managed OS disk",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4"), builder.EphemeralOSDisk()),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4")),
+			errorMatcher: IsEphemeralOSDiskWasChangedError,
+		},
+		{
+			name:         "case 24: keep the ephemeral OS disk",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4"), builder.EphemeralOSDisk()),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4"), builder.EphemeralOSDisk()),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 25: change to a VM size not supporting the ephemeral OS disk",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_D4ds_v4"), builder.EphemeralOSDisk()),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_D8_v3"), builder.EphemeralOSDisk()),
+			errorMatcher: IsEphemeralOSDiskNotSupportedError,
+		},
+		{
+			name:         "case 26: change to a GPU VM size before the MachinePool is created",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_D4_v3")),
+			newNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_NC6s_v3")),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 27: change to a GPU VM size with a MachinePool following the GPU conventions",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_D4_v3")),
+			newNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_NC6s_v3")),
+			machinePool:  mpbuilder.BuildMachinePool(mpbuilder.AzureMachinePool("np001"), mpbuilder.Label(generic.GPULabel, "true"), mpbuilder.Annotation(generic.NodeTaintsAnnotation, "nvidia.com/gpu=present:NoSchedule")),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 28: change to a GPU VM size with a MachinePool without the GPU label",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_D4_v3")),
+			newNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_NC6s_v3")),
+			machinePool:  mpbuilder.BuildMachinePool(mpbuilder.AzureMachinePool("np001"), mpbuilder.Annotation(generic.NodeTaintsAnnotation, "nvidia.com/gpu=present:NoSchedule")),
+			errorMatcher: generic.IsGPUNodePoolConventionError,
+		},
+		{
+			name:         "case 29: change to a GPU VM size with a MachinePool without the GPU taint",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_D4_v3")),
+			newNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_NC6s_v3")),
+			machinePool:  mpbuilder.BuildMachinePool(mpbuilder.AzureMachinePool("np001"), mpbuilder.Label(generic.GPULabel, "true")),
+			errorMatcher: generic.IsGPUNodePoolConventionError,
+		},
+		{
+			name:         "case 30: change to a GPU VM size for an organization that is not allowed",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_D4_v3"), builder.Organization("wrongorg")),
+			newNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_NC6s_v3"), builder.Organization("wrongorg")),
+			errorMatcher: IsGPUNotAllowedForOrganizationError,
+		},
+		{
+			name:         "case 31: keep the GPU VM size with a MachinePool not following the GPU conventions",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_NC6s_v3")),
+			newNodePool:  builder.BuildAzureMachinePool(builder.Name("np001"), builder.VMSize("Standard_NC6s_v3")),
+			machinePool:  mpbuilder.BuildMachinePool(mpbuilder.AzureMachinePool("np001")),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 32: keep a VM size that is not offered anymore",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_A2_v2")),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_A2_v2")),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 33: change to a VM size that is not offered",
+			oldNodePool:  builder.BuildAzureMachinePool(builder.VMSize(supportedInstanceType[0])),
+			newNodePool:  builder.BuildAzureMachinePool(builder.VMSize("Standard_A2_v2")),
+			errorMatcher: vmcapabilities.IsSkuNotFoundError,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -239,6 +321,35 @@ func TestAzureMachinePoolUpdateValidate(t *testing.T) {
 						},
 					},
 				},
+				"Standard_D4ds_v4": {
+					Name: to.StringPtr("Standard_D4ds_v4"),
+					Capabilities: &[]compute.ResourceSkuCapabilities{
+						{
+							Name:  to.StringPtr("AcceleratedNetworkingEnabled"),
+							Value: to.StringPtr("True"),
+						},
+						{
+							Name:  to.StringPtr("vCPUs"),
+							Value: to.StringPtr("4"),
+						},
+						{
+							Name:  to.StringPtr("MemoryGB"),
+							Value: to.StringPtr("16"),
+						},
+						{
+							Name:  to.StringPtr("PremiumIO"),
+							Value: to.StringPtr("False"),
+						},
+						{
+							Name:  to.StringPtr("EphemeralOSDiskSupported"),
+							Value: to.StringPtr("True"),
+						},
+						{
+							Name:  to.StringPtr("CachedDiskBytes"),
+							Value: to.StringPtr("107374182400"),
+						},
+					},
+				},
 				"Standard_D8_v3": {
 					Name: to.StringPtr("Standard_D8_v3"),
 					Capabilities: &[]compute.ResourceSkuCapabilities{
@@ -260,6 +371,31 @@ func TestAzureMachinePoolUpdateValidate(t *testing.T) {
 						},
 					},
 				},
+				"Standard_NC6s_v3": {
+					Name: to.StringPtr("Standard_NC6s_v3"),
+					Capabilities: &[]compute.ResourceSkuCapabilities{
+						{
+							Name:  to.StringPtr("AcceleratedNetworkingEnabled"),
+							Value: to.StringPtr("True"),
+						},
+						{
+							Name:  to.StringPtr("vCPUs"),
+							Value: to.StringPtr("6"),
+						},
+						{
+							Name:  to.StringPtr("MemoryGB"),
+							Value: to.StringPtr("112"),
+						},
+						{
+							Name:  to.StringPtr("PremiumIO"),
+							Value: to.StringPtr("True"),
+						},
+						{
+							Name:  to.StringPtr("GPUs"),
+							Value: to.StringPtr("1"),
+						},
+					},
+				},
 				"Standard_D16_v3": {
 					Name: to.StringPtr("Standard_D16_v3"),
 					Capabilities: &[]compute.ResourceSkuCapabilities{
@@ -287,12 +423,20 @@ func TestAzureMachinePoolUpdateValidate(t *testing.T) {
 				panic(microerror.JSON(err))
 			}
 
+			if tc.machinePool != nil {
+				err = ctrlClient.Create(ctx, tc.machinePool)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
 			handler, err := NewWebhookHandler(WebhookHandlerConfig{
-				CtrlClient:    ctrlClient,
-				Decoder:       unittest.NewFakeDecoder(),
-				Location:      "westeurope",
-				Logger:        newLogger,
-				VMcapsFactory: vmcaps,
+				CtrlClient:       ctrlClient,
+				Decoder:          unittest.NewFakeDecoder(),
+				GPUOrganizations: []string{"giantswarm"},
+				Location:         "westeurope",
+				Logger:           newLogger,
+				VMcapsFactory:    vmcaps,
 			})
 			if err != nil {
 				t.Fatal(err)
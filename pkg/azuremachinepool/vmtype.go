@@ -2,21 +2,22 @@ package azuremachinepool
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
 	"github.com/giantswarm/microerror"
 	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 
+	"github.com/giantswarm/azure-admission-controller/internal/sizingpolicy"
 	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
 )
 
-const (
-	minMemory = 16
-	minCPUs   = 4
-)
-
-func checkInstanceTypeIsValid(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMachinePool *capzexp.AzureMachinePool) error {
+func (h *WebhookHandler) checkInstanceTypeIsValid(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMachinePool *capzexp.AzureMachinePool) error {
 	memory, err := vmcaps.Memory(ctx, azureMachinePool.Spec.Location, azureMachinePool.Spec.Template.VMSize)
-	if err != nil {
+	if vmcapabilities.IsSkuNotFoundError(err) && vmcapabilities.IsGPUVMSize(azureMachinePool.Spec.Template.VMSize) {
+		return microerror.Mask(gpuVMSizeNotOffered(ctx, vmcaps, azureMachinePool))
+	} else if err != nil {
 		return microerror.Mask(err)
 	}
 
@@ -25,13 +26,78 @@ func checkInstanceTypeIsValid(ctx context.Context, vmcaps *vmcapabilities.VMSKU,
 		return microerror.Mask(err)
 	}
 
-	if memory < minMemory {
-		return microerror.Maskf(insufficientMemoryError, "Memory has to be greater than %d GBs", minMemory)
+	family, err := vmcaps.Family(ctx, azureMachinePool.Spec.Location, azureMachinePool.Spec.Template.VMSize)
+	if err != nil {
+		return microerror.Mask(err)
 	}
 
-	if cpu < minCPUs {
-		return microerror.Maskf(insufficientCPUError, "Number of cores has to be greater than %d", minCPUs)
+	policy := h.sizingPolicy.Effective(azureMachinePool.GetLabels()[label.Organization])
+	violation := policy.Check(sizingpolicy.NodePool{
+		VMSize:    azureMachinePool.Spec.Template.VMSize,
+		Family:    family,
+		CPUs:      cpu,
+		MemoryGB:  memory,
+		DataDisks: len(azureMachinePool.Spec.Template.DataDisks),
+	})
+	if violation != nil {
+		switch violation.Rule {
+		case sizingpolicy.RuleMinMemoryGB:
+			return microerror.Maskf(insufficientMemoryError, "%s", violation.Error())
+		case sizingpolicy.RuleMinCPUs:
+			return microerror.Maskf(insufficientCPUError, "%s", violation.Error())
+		default:
+			return microerror.Maskf(sizingPolicyViolationError, "%s", violation.Error())
+		}
 	}
 
 	return nil
 }
+
+func checkInstanceTypeIsAvailable(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMachinePool *capzexp.AzureMachinePool) error {
+	restrictions, err := vmcaps.Restrictions(ctx, azureMachinePool.Spec.Location, azureMachinePool.Spec.Template.VMSize)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if restrictions.Location {
+		return microerror.Maskf(vmSizeNotAvailableError, "VM size %s is not available for the cluster's subscription in %s (%s)", azureMachinePool.Spec.Template.VMSize, azureMachinePool.Spec.Location, strings.Join(restrictions.Reasons, ", "))
+	}
+
+	return nil
+}
+
+// warnInstanceTypeIsCloseToMinimum warns about VM sizes that are below twice
+// the minimum cores or memory of the sizing policy. They are still allowed,
+// but they are close to the minimum.
+func (h *WebhookHandler) warnInstanceTypeIsCloseToMinimum(ctx context.Context, vmcaps *vmcapabilities.VMSKU, azureMachinePool *capzexp.AzureMachinePool) ([]string, error) {
+	memory, err := vmcaps.Memory(ctx, azureMachinePool.Spec.Location, azureMachinePool.Spec.Template.VMSize)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	cpu, err := vmcaps.CPUs(ctx, azureMachinePool.Spec.Location, azureMachinePool.Spec.Template.VMSize)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	policy := h.sizingPolicy.Effective(azureMachinePool.GetLabels()[label.Organization])
+
+	var minCPUs, minMemory int
+	if policy.MinCPUs != nil {
+		minCPUs = *policy.MinCPUs
+	}
+	if policy.MinMemoryGB != nil {
+		minMemory = *policy.MinMemoryGB
+	}
+	recommendedCPUs := 2 * minCPUs
+	recommendedMemory := 2 * minMemory
+
+	if memory < recommendedMemory || cpu < recommendedCPUs {
+		return []string{
+			fmt.Sprintf("VM size %s has %d cores and %d GBs of memory, which is close to the minimum of %d cores and %d GBs. Consider using a VM size with at least %d cores and %d GBs of memory.",
+				azureMachinePool.Spec.Template.VMSize, cpu, memory, minCPUs, minMemory, recommendedCPUs, recommendedMemory),
+		}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,128 @@
+package azuremachinepool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/giantswarm/micrologger"
+	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/sizingpolicy"
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/azuremachinepool"
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+func TestCheckInstanceTypeIsValidSizingPolicy(t *testing.T) {
+	policy := `
+installation:
+  deniedFamilies: [standardLSv2Family]
+organizations:
+  acme:
+    minCPUs: 8
+`
+
+	testCases := []struct {
+		name         string
+		policy       string
+		nodePool     *capzexp.AzureMachinePool
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: built-in minimum cores without a policy file",
+			policy:       "",
+			nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D2s_v3")),
+			errorMatcher: IsInsufficientCPUError,
+		},
+		{
+			name:         "case 1: big enough VM size",
+			policy:       policy,
+			nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D4s_v3")),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 2: denied family",
+			policy:       policy,
+			nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_L8s_v2")),
+			errorMatcher: IsSizingPolicyViolationError,
+		},
+		{
+			name:         "case 3: minimum cores of the organization",
+			policy:       policy,
+			nodePool:     builder.BuildAzureMachinePool(builder.VMSize("Standard_D4s_v3"), builder.Organization("acme")),
+			errorMatcher: IsInsufficientCPUError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, _ := micrologger.New(micrologger.Config{})
+			ctx := context.Background()
+
+			var registry *sizingpolicy.Registry
+			if tc.policy != "" {
+				file := filepath.Join(t.TempDir(), "sizing-policy.yaml")
+				err := os.WriteFile(file, []byte(tc.policy), 0600)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				registry, err = sizingpolicy.NewRegistry(sizingpolicy.RegistryConfig{
+					Logger: logger,
+					File:   file,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stubbedSKUs := map[string]compute.ResourceSku{
+				"Standard_D2s_v3": testSKU("Standard_D2s_v3", "standardDSv3Family", "2", "8"),
+				"Standard_D4s_v3": testSKU("Standard_D4s_v3", "standardDSv3Family", "4", "16"),
+				"Standard_L8s_v2": testSKU("Standard_L8s_v2", "standardLSv2Family", "8", "64"),
+			}
+			vmcaps, err := unittest.NewVMCapsStubFactory(stubbedSKUs, logger).GetClient(ctx, nil, tc.nodePool.ObjectMeta)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler := &WebhookHandler{
+				logger:       logger,
+				sizingPolicy: registry,
+			}
+
+			err = handler.checkInstanceTypeIsValid(ctx, vmcaps, tc.nodePool)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
+
+func testSKU(name string, family string, cpus string, memory string) compute.ResourceSku {
+	return compute.ResourceSku{
+		Name:   to.StringPtr(name),
+		Family: to.StringPtr(family),
+		Capabilities: &[]compute.ResourceSkuCapabilities{
+			{
+				Name:  to.StringPtr("vCPUs"),
+				Value: to.StringPtr(cpus),
+			},
+			{
+				Name:  to.StringPtr("MemoryGB"),
+				Value: to.StringPtr(memory),
+			},
+		},
+	}
+}
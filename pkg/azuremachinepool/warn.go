@@ -0,0 +1,57 @@
+package azuremachinepool
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/pkg/key"
+)
+
+func (h *WebhookHandler) OnCreateWarn(ctx context.Context, object interface{}) ([]string, error) {
+	azureMPNewCR, err := key.ToAzureMachinePoolPtr(object)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	vmcaps, err := h.vmcapsFactory.GetClient(ctx, h.ctrlClient, azureMPNewCR.ObjectMeta)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	warnings, err := h.warnInstanceTypeIsCloseToMinimum(ctx, vmcaps, azureMPNewCR)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return warnings, nil
+}
+
+func (h *WebhookHandler) OnUpdateWarn(ctx context.Context, oldObject interface{}, object interface{}) ([]string, error) {
+	azureMPNewCR, err := key.ToAzureMachinePoolPtr(object)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	azureMPOldCR, err := key.ToAzureMachinePoolPtr(oldObject)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	// Only warn when the VM size is changed, otherwise every update of an
+	// existing node pool would repeat the same warning.
+	if azureMPOldCR.Spec.Template.VMSize == azureMPNewCR.Spec.Template.VMSize {
+		return nil, nil
+	}
+
+	vmcaps, err := h.vmcapsFactory.GetClient(ctx, h.ctrlClient, azureMPNewCR.ObjectMeta)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	warnings, err := h.warnInstanceTypeIsCloseToMinimum(ctx, vmcaps, azureMPNewCR)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return warnings, nil
+}
@@ -8,23 +8,43 @@ import (
 	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/azure-admission-controller/internal/azurequota"
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/quota"
+	"github.com/giantswarm/azure-admission-controller/internal/sizingpolicy"
 	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
 )
 
 type WebhookHandler struct {
-	ctrlClient    client.Client
-	decoder       runtime.Decoder
-	location      string
-	logger        micrologger.Logger
-	vmcapsFactory vmcapabilities.Factory
+	azureQuotaFactory azurequota.Factory
+	ctrlClient        client.Client
+	decoder           runtime.Decoder
+	gpuOrganizations  []string
+	location          string
+	logger            micrologger.Logger
+	quotaChecker      *quota.Checker
+	sizingPolicy      *sizingpolicy.Registry
+	vmcapsFactory     vmcapabilities.Factory
 }
 
 type WebhookHandlerConfig struct {
-	CtrlClient    client.Client
-	Decoder       runtime.Decoder
-	Location      string
-	Logger        micrologger.Logger
+	// AzureQuotaFactory creates the clients checking the vCPU quota of the
+	// Azure subscriptions. It is optional, when nil the Azure quota is not
+	// checked.
+	AzureQuotaFactory azurequota.Factory
+	CtrlClient        client.Client
+	Decoder           runtime.Decoder
+	// GPUOrganizations are the organizations allowed to use GPU VM sizes.
+	// It is optional, when empty all organizations are allowed.
+	GPUOrganizations []string
+	Location         string
+	Logger           micrologger.Logger
+	// QuotaChecker checks the node pools against the organization quotas. It
+	// is optional, when nil no quota is enforced.
+	QuotaChecker *quota.Checker
+	// SizingPolicy holds the VM size rules of the node pools. It is
+	// optional, when nil only the built-in policy applies.
+	SizingPolicy  *sizingpolicy.Registry
 	VMcapsFactory vmcapabilities.Factory
 }
 
@@ -46,11 +66,15 @@ func NewWebhookHandler(config WebhookHandlerConfig) (*WebhookHandler, error) {
 	}
 
 	handler := &WebhookHandler{
-		ctrlClient:    config.CtrlClient,
-		decoder:       config.Decoder,
-		location:      config.Location,
-		logger:        config.Logger,
-		vmcapsFactory: config.VMcapsFactory,
+		azureQuotaFactory: config.AzureQuotaFactory,
+		ctrlClient:        config.CtrlClient,
+		decoder:           config.Decoder,
+		gpuOrganizations:  config.GPUOrganizations,
+		location:          config.Location,
+		logger:            config.Logger,
+		quotaChecker:      config.QuotaChecker,
+		sizingPolicy:      config.SizingPolicy,
+		vmcapsFactory:     config.VMcapsFactory,
 	}
 
 	return handler, nil
@@ -2,6 +2,8 @@ package azureupdate
 
 import (
 	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
 var availabilityZonesChangeError = &microerror.Error{
@@ -30,3 +32,10 @@ var masterCIDRChangeError = &microerror.Error{
 func IsMasterCIDRChange(err error) bool {
 	return microerror.Cause(err) == masterCIDRChangeError
 }
+
+func init() {
+	errors.RegisterValidationErrors(
+		availabilityZonesChangeError,
+		masterCIDRChangeError,
+	)
+}
@@ -17,15 +17,21 @@ import (
 )
 
 type AzureClusterConfigWebhookHandler struct {
-	ctrlClient client.Client
-	decoder    runtime.Decoder
-	logger     micrologger.Logger
+	ctrlClient    client.Client
+	decoder       runtime.Decoder
+	logger        micrologger.Logger
+	releasePolicy *releaseversion.PolicyRegistry
 }
 
 type AzureClusterConfigWebhookHandlerConfig struct {
 	CtrlClient client.Client
 	Decoder    runtime.Decoder
 	Logger     micrologger.Logger
+
+	// ReleasePolicy is the release policy of the installation, used to plan
+	// upgrade paths. It is optional, when nil any patch release can be
+	// upgraded.
+	ReleasePolicy *releaseversion.PolicyRegistry
 }
 
 func NewAzureClusterConfigWebhookHandler(config AzureClusterConfigWebhookHandlerConfig) (*AzureClusterConfigWebhookHandler, error) {
@@ -40,9 +46,10 @@ func NewAzureClusterConfigWebhookHandler(config AzureClusterConfigWebhookHandler
 	}
 
 	webhookHandler := &AzureClusterConfigWebhookHandler{
-		ctrlClient: config.CtrlClient,
-		decoder:    config.Decoder,
-		logger:     config.Logger,
+		ctrlClient:    config.CtrlClient,
+		decoder:       config.Decoder,
+		logger:        config.Logger,
+		releasePolicy: config.ReleasePolicy,
 	}
 
 	return webhookHandler, nil
@@ -82,7 +89,7 @@ func (h *AzureClusterConfigWebhookHandler) OnUpdateValidate(ctx context.Context,
 	}
 
 	if !oldVersion.Equals(newVersion) {
-		return releaseversion.Validate(ctx, h.ctrlClient, oldVersion, newVersion)
+		return releaseversion.Validate(ctx, h.ctrlClient, h.releasePolicy.Policy(), oldVersion, newVersion)
 	}
 
 	return nil
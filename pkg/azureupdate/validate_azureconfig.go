@@ -19,15 +19,21 @@ import (
 )
 
 type AzureConfigWebhookHandler struct {
-	ctrlClient client.Client
-	decoder    runtime.Decoder
-	logger     micrologger.Logger
+	ctrlClient    client.Client
+	decoder       runtime.Decoder
+	logger        micrologger.Logger
+	releasePolicy *releaseversion.PolicyRegistry
 }
 
 type AzureConfigWebhookHandlerConfig struct {
 	CtrlClient client.Client
 	Decoder    runtime.Decoder
 	Logger     micrologger.Logger
+
+	// ReleasePolicy is the release policy of the installation, used to plan
+	// upgrade paths. It is optional, when nil any patch release can be
+	// upgraded.
+	ReleasePolicy *releaseversion.PolicyRegistry
 }
 
 func NewAzureConfigWebhookHandler(config AzureConfigWebhookHandlerConfig) (*AzureConfigWebhookHandler, error) {
@@ -42,9 +48,10 @@ func NewAzureConfigWebhookHandler(config AzureConfigWebhookHandlerConfig) (*Azur
 	}
 
 	webhookHandler := &AzureConfigWebhookHandler{
-		ctrlClient: config.CtrlClient,
-		decoder:    config.Decoder,
-		logger:     config.Logger,
+		ctrlClient:    config.CtrlClient,
+		decoder:       config.Decoder,
+		logger:        config.Logger,
+		releasePolicy: config.ReleasePolicy,
 	}
 
 	return webhookHandler, nil
@@ -84,7 +91,7 @@ func (h *AzureConfigWebhookHandler) OnUpdateValidate(ctx context.Context, oldObj
 	}
 
 	if !oldVersion.Equals(newVersion) {
-		return releaseversion.Validate(ctx, h.ctrlClient, oldVersion, newVersion)
+		return releaseversion.Validate(ctx, h.ctrlClient, h.releasePolicy.Policy(), oldVersion, newVersion)
 	}
 
 	// Don't allow change of Master CIDR.
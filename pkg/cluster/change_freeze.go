@@ -0,0 +1,26 @@
+package cluster
+
+import (
+	"strings"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/maintenance"
+)
+
+// validateChangeFreeze denies changing the release of a cluster during a
+// change freeze of its organization, unless the change freeze override
+// annotation is set to "true".
+func (h *WebhookHandler) validateChangeFreeze(old *capi.Cluster, new *capi.Cluster) error {
+	if old.GetLabels()[label.ReleaseVersion] == new.GetLabels()[label.ReleaseVersion] {
+		return nil
+	}
+
+	if strings.EqualFold(new.GetAnnotations()[maintenance.FreezeOverrideAnnotation], "true") {
+		return nil
+	}
+
+	return h.maintenance.Schedule(new.GetLabels()[label.Organization]).CheckChange(time.Now())
+}
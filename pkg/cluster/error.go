@@ -2,6 +2,8 @@ package cluster
 
 import (
 	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
 var invalidConfigError = &microerror.Error{
@@ -102,3 +104,17 @@ var clusterNetworkWasChangedError = &microerror.Error{
 func IsClusterNetworkWasChangedError(err error) bool {
 	return microerror.Cause(err) == clusterNetworkWasChangedError
 }
+
+func init() {
+	errors.RegisterValidationErrors(
+		emptyClusterNetworkError,
+		emptyClusterNetworkServicesError,
+		unexpectedAPIServerPortError,
+		unexpectedServiceDomainError,
+		unexpectedCIDRBlocksError,
+		invalidControlPlaneEndpointHostError,
+		invalidControlPlaneEndpointPortError,
+		controlPlaneEndpointWasChangedError,
+		clusterNetworkWasChangedError,
+	)
+}
@@ -2,18 +2,21 @@ package cluster
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/giantswarm/apiextensions/v6/pkg/annotation"
 	"github.com/giantswarm/microerror"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 
 	"github.com/giantswarm/azure-admission-controller/internal/patches"
+	"github.com/giantswarm/azure-admission-controller/internal/scheduledupgrades"
 	"github.com/giantswarm/azure-admission-controller/pkg/key"
 	"github.com/giantswarm/azure-admission-controller/pkg/mutator"
 )
 
 func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{}) ([]mutator.PatchOperation, error) {
-	var result []mutator.PatchOperation
+	var builder mutator.PatchBuilder
 	clusterCR, err := key.ToClusterPtr(object)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
@@ -24,25 +27,25 @@ func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{})
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	patch, err = h.ensureControlPlaneEndpointHost(ctx, clusterCR)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	patch, err = h.ensureControlPlaneEndpointPort(ctx, clusterCR)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
+	builder.Add(patch)
+
+	patch, err = h.ensureUpgradeTimeFormat(ctx, clusterCR)
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
+	builder.Add(patch)
 
 	clusterCR.Default()
 	{
@@ -52,7 +55,15 @@ func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{})
 			return []mutator.PatchOperation{}, microerror.Mask(err)
 		}
 
-		result = append(result, capiPatches...)
+		builder.AddDefaults(capiPatches)
+	}
+
+	result, err := builder.Build()
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
+	}
+	for _, conflict := range builder.Conflicts() {
+		h.logger.LogCtx(ctx, "level", "warning", "message", conflict)
 	}
 
 	return result, nil
@@ -92,3 +103,28 @@ func (h *WebhookHandler) ensureControlPlaneEndpointPort(ctx context.Context, clu
 
 	return nil, nil
 }
+
+// ensureUpgradeTimeFormat rewrites the scheduled upgrade time to RFC822 in
+// UTC, which is the format the upgrade scheduler expects. Invalid values are
+// left to the validating webhook.
+func (h *WebhookHandler) ensureUpgradeTimeFormat(ctx context.Context, clusterCR *capi.Cluster) (*mutator.PatchOperation, error) {
+	updateTime, ok := clusterCR.GetAnnotations()[annotation.UpdateScheduleTargetTime]
+	if !ok {
+		return nil, nil
+	}
+
+	normalized, err := scheduledupgrades.NormalizeUpgradeScheduleTime(updateTime)
+	if scheduledupgrades.IsInvalidTime(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	if normalized == updateTime {
+		return nil, nil
+	}
+
+	patch := mutator.PatchReplace(fmt.Sprintf("/metadata/annotations/%s", mutator.EscapeJSONPatchString(annotation.UpdateScheduleTargetTime)), normalized)
+
+	return &patch, nil
+}
@@ -12,7 +12,7 @@ import (
 )
 
 func (h *WebhookHandler) OnUpdateMutate(ctx context.Context, _ interface{}, object interface{}) ([]mutator.PatchOperation, error) {
-	var result []mutator.PatchOperation
+	var builder mutator.PatchBuilder
 	clusterCR, err := key.ToClusterPtr(object)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
@@ -23,17 +23,19 @@ func (h *WebhookHandler) OnUpdateMutate(ctx context.Context, _ interface{}, obje
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
-	}
+	builder.Add(patch)
 
 	patch, err = mutator.EnsureComponentVersionLabelFromRelease(ctx, h.ctrlReader, clusterCR.GetObjectMeta(), "cluster-operator", label.ClusterOperatorVersion)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
-	if patch != nil {
-		result = append(result, *patch)
+	builder.Add(patch)
+
+	patch, err = h.ensureUpgradeTimeFormat(ctx, clusterCR)
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
 	}
+	builder.Add(patch)
 
 	clusterCR.Default()
 	{
@@ -43,7 +45,15 @@ func (h *WebhookHandler) OnUpdateMutate(ctx context.Context, _ interface{}, obje
 			return []mutator.PatchOperation{}, microerror.Mask(err)
 		}
 
-		result = append(result, capiPatches...)
+		builder.AddDefaults(capiPatches)
+	}
+
+	result, err := builder.Build()
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
+	}
+	for _, conflict := range builder.Conflicts() {
+		h.logger.LogCtx(ctx, "level", "warning", "message", conflict)
 	}
 
 	return result, nil
@@ -49,6 +49,22 @@ func TestClusterUpdateMutate(t *testing.T) {
 			},
 			errorMatcher: nil,
 		},
+		{
+			name: "case 2: upgrade time normalized to RFC822 in UTC",
+			cluster: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{"release.giantswarm.io/version": "v13.1.0", "azure-operator.giantswarm.io/version": "5.1.0", "cluster-operator.giantswarm.io/version": "0.23.11"}),
+				builder.Annotations(map[string]string{"alpha.giantswarm.io/update-schedule-target-time": "2021-01-30T16:04:00+01:00"}),
+			),
+			patches: []mutator.PatchOperation{
+				{
+					Operation: "replace",
+					Path:      "/metadata/annotations/alpha.giantswarm.io~1update-schedule-target-time",
+					Value:     "30 Jan 21 15:04 UTC",
+				},
+			},
+			errorMatcher: nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -3,53 +3,64 @@ package cluster
 import (
 	"context"
 
+	"github.com/giantswarm/apiextensions/v6/pkg/annotation"
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
 	"github.com/giantswarm/microerror"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
 	"github.com/giantswarm/azure-admission-controller/internal/scheduledupgrades"
+	"github.com/giantswarm/azure-admission-controller/internal/semverhelper"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 	"github.com/giantswarm/azure-admission-controller/pkg/key"
 )
 
+var (
+	clusterNetworkPath           = field.NewPath("spec", "clusterNetwork")
+	controlPlaneEndpointPath     = field.NewPath("spec", "controlPlaneEndpoint")
+	conditionsPath               = field.NewPath("status", "conditions")
+	upgradeTimeAnnotationPath    = field.NewPath("metadata", "annotations").Key(annotation.UpdateScheduleTargetTime)
+	upgradeReleaseAnnotationPath = field.NewPath("metadata", "annotations").Key(annotation.UpdateScheduleTargetRelease)
+)
+
 func (h *WebhookHandler) OnCreateValidate(ctx context.Context, object interface{}) error {
 	clusterCR, err := key.ToClusterPtr(object)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	err = clusterCR.ValidateCreate()
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
 
+	validationErrors.Add(nil, clusterCR.ValidateCreate())
+	// Only an existing cluster is a validation failure, failing to list the
+	// clusters is returned as is.
 	err = generic.ClusterExists(ctx, h.ctrlClient, clusterCR)
-	if err != nil {
+	if generic.IsNotAllowed(err) {
+		validationErrors.Add(nil, err)
+	} else if err != nil {
 		return microerror.Mask(err)
 	}
+	validationErrors.Add(generic.OrganizationLabelPath, generic.ValidateOrganizationLabelContainsExistingOrganization(ctx, h.ctrlClient, clusterCR))
+	validationErrors.Add(clusterNetworkPath, enforcement.Apply(ctx, enforcement.ClusterClusterNetwork, validateClusterNetwork(*clusterCR)))
+	validationErrors.Add(controlPlaneEndpointPath, enforcement.Apply(ctx, enforcement.ClusterControlPlaneEndpoint, validateControlPlaneEndpoint(*clusterCR, h.baseDomain)))
+	validationErrors.Add(upgradeTimeAnnotationPath, enforcement.Apply(ctx, enforcement.ClusterUpgradeTime, scheduledupgrades.ValidateClusterAnnotationUpgradeTime(nil, clusterCR)))
+	validationErrors.Add(upgradeTimeAnnotationPath, enforcement.Apply(ctx, enforcement.ClusterMaintenanceWindow, scheduledupgrades.ValidateClusterAnnotationUpgradeTimeSchedule(h.maintenance.Schedule(clusterCR.GetLabels()[label.Organization]), nil, clusterCR)))
+	validationErrors.Add(upgradeReleaseAnnotationPath, enforcement.Apply(ctx, enforcement.ClusterUpgradeRelease, scheduledupgrades.ValidateClusterAnnotationUpgradeRelease(ctx, h.ctrlClient, h.releasePolicy.Policy(), clusterCR)))
+	validationErrors.Add(generic.ReleaseVersionLabelPath, h.validateReleaseOnCreate(ctx, clusterCR))
 
-	err = generic.ValidateOrganizationLabelContainsExistingOrganization(ctx, h.ctrlClient, clusterCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = validateClusterNetwork(*clusterCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = validateControlPlaneEndpoint(*clusterCR, h.baseDomain)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = scheduledupgrades.ValidateClusterAnnotationUpgradeTime(nil, clusterCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	return microerror.Mask(validationErrors.Err())
+}
 
-	err = scheduledupgrades.ValidateClusterAnnotationUpgradeRelease(ctx, h.ctrlClient, clusterCR)
+func (h *WebhookHandler) validateReleaseOnCreate(ctx context.Context, clusterCR *capi.Cluster) error {
+	version, err := semverhelper.GetSemverFromLabels(clusterCR.Labels)
 	if err != nil {
-		return microerror.Mask(err)
+		return microerror.Maskf(errors.ParsingFailedError, "unable to parse version from the Cluster being created")
 	}
 
-	return nil
+	return releaseversion.ValidatePolicyOnCreate(ctx, h.ctrlClient, h.releasePolicy.Policy(), version)
 }
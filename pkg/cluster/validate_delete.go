@@ -0,0 +1,26 @@
+package cluster
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/conditions"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/generic"
+	"github.com/giantswarm/azure-admission-controller/pkg/key"
+)
+
+func (h *WebhookHandler) OnDeleteValidate(ctx context.Context, object interface{}) error {
+	clusterCR, err := key.ToClusterPtr(object)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var validationErrors errors.ValidationErrors
+
+	validationErrors.Add(generic.DeletionProtectionAnnotationPath, generic.ValidateDeletionProtectionRemoved(clusterCR))
+	validationErrors.Add(conditionsPath, conditions.ValidateClusterDeletion(clusterCR))
+
+	return microerror.Mask(validationErrors.Err())
+}
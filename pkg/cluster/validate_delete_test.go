@@ -0,0 +1,103 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	aeconditions "github.com/giantswarm/apiextensions/v6/pkg/conditions"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/cluster"
+	"github.com/giantswarm/azure-admission-controller/pkg/generic"
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+func TestClusterDeleteValidate(t *testing.T) {
+	type testCase struct {
+		name         string
+		cluster      *capi.Cluster
+		errorMatcher func(err error) bool
+	}
+
+	var testCases = []testCase{
+		{
+			name:         "case 0: unprotected cluster",
+			cluster:      builder.BuildCluster(),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 1: protected cluster",
+			cluster:      builder.BuildCluster(builder.Annotations(map[string]string{generic.DeletionProtectionAnnotation: "true"})),
+			errorMatcher: generic.IsDeletionProtectedError,
+		},
+		{
+			name: "case 2: cluster is being created",
+			cluster: builder.BuildCluster(builder.Conditions(capi.Conditions{
+				{Type: aeconditions.CreatingCondition, Status: corev1.ConditionTrue},
+			})),
+			errorMatcher: errors.IsInvalidOperationError,
+		},
+		{
+			name: "case 3: cluster is being upgraded",
+			cluster: builder.BuildCluster(builder.Conditions(capi.Conditions{
+				{Type: aeconditions.CreatingCondition, Status: corev1.ConditionFalse},
+				{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionTrue},
+			})),
+			errorMatcher: errors.IsInvalidOperationError,
+		},
+		{
+			name: "case 4: cluster is created and upgraded",
+			cluster: builder.BuildCluster(builder.Conditions(capi.Conditions{
+				{Type: aeconditions.CreatingCondition, Status: corev1.ConditionFalse},
+				{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionFalse},
+			})),
+			errorMatcher: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+
+			// Create a new logger that is used by all admitters.
+			var newLogger micrologger.Logger
+			{
+				newLogger, err = micrologger.New(micrologger.Config{})
+				if err != nil {
+					panic(microerror.JSON(err))
+				}
+			}
+
+			fakeK8sClient := unittest.FakeK8sClient()
+			handler, err := NewWebhookHandler(WebhookHandlerConfig{
+				BaseDomain: "k8s.test.westeurope.azure.gigantic.io",
+				CtrlClient: fakeK8sClient.CtrlClient(),
+				CtrlReader: fakeK8sClient.CtrlClient(),
+				Decoder:    unittest.NewFakeDecoder(),
+				Logger:     newLogger,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Run validating webhook handler on Cluster delete.
+			err = handler.OnDeleteValidate(context.Background(), tc.cluster)
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
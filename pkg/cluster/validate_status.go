@@ -0,0 +1,36 @@
+package cluster
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/conditions"
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/key"
+)
+
+// OnStatusUpdateValidate validates the conditions of the Cluster, which are
+// only changed through the status subresource.
+func (h *WebhookHandler) OnStatusUpdateValidate(ctx context.Context, oldObject interface{}, object interface{}) error {
+	clusterNewCR, err := key.ToClusterPtr(object)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !clusterNewCR.GetDeletionTimestamp().IsZero() {
+		h.logger.LogCtx(ctx, "level", "debug", "message", "The object is being deleted so we don't validate it")
+		return nil
+	}
+
+	clusterOldCR, err := key.ToClusterPtr(oldObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var validationErrors errors.ValidationErrors
+
+	validationErrors.Add(conditionsPath, enforcement.Apply(ctx, enforcement.ClusterConditions, conditions.ValidateClusterConditions(ctx, h.ctrlReader, clusterOldCR, clusterNewCR)))
+
+	return microerror.Mask(validationErrors.Err())
+}
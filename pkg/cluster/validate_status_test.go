@@ -0,0 +1,160 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	aeconditions "github.com/giantswarm/apiextensions/v6/pkg/conditions"
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	releasev1alpha1 "github.com/giantswarm/release-operator/v3/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/cluster"
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+func TestClusterStatusUpdateValidate(t *testing.T) {
+	type testCase struct {
+		name          string
+		oldConditions capi.Conditions
+		newConditions capi.Conditions
+		errorMatcher  func(err error) bool
+	}
+
+	upgradingTo := func(version string) capi.Condition {
+		message, err := aeconditions.SerializeUpgradingConditionMessage(aeconditions.UpgradingConditionMessage{
+			Message:        "Upgrade has been started",
+			ReleaseVersion: version,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return capi.Condition{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionTrue, Message: message}
+	}
+
+	var testCases = []testCase{
+		{
+			name:          "case 0: upgrade started to an existing release",
+			newConditions: capi.Conditions{upgradingTo("15.1.0")},
+		},
+		{
+			name:          "case 1: upgrade started to a release that does not exist",
+			newConditions: capi.Conditions{upgradingTo("15.2.0")},
+			errorMatcher:  errors.IsInvalidReleaseVersionInUpgradingConditionMessage,
+		},
+		{
+			name:          "case 2: upgrade started to an invalid release version",
+			newConditions: capi.Conditions{upgradingTo("latest")},
+			errorMatcher:  errors.IsInvalidReleaseVersionInUpgradingConditionMessage,
+		},
+		{
+			name:          "case 3: upgrade started with a message in an unknown format",
+			newConditions: capi.Conditions{{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionTrue, Message: "upgrading to 15.1.0"}},
+			errorMatcher:  errors.IsInvalidUpgradingConditionMessageFormat,
+		},
+		{
+			name:          "case 4: upgrade completed",
+			oldConditions: capi.Conditions{upgradingTo("15.1.0")},
+			newConditions: capi.Conditions{{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionFalse, Reason: aeconditions.UpgradeCompletedReason}},
+		},
+		{
+			name:          "case 5: upgrade finished with an unknown reason",
+			oldConditions: capi.Conditions{upgradingTo("15.1.0")},
+			newConditions: capi.Conditions{{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionFalse, Reason: "Done"}},
+			errorMatcher:  errors.IsInvalidConditionModification,
+		},
+		{
+			name:          "case 6: unchanged message in an unknown format",
+			oldConditions: capi.Conditions{{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionTrue, Message: "upgrading to 15.1.0"}},
+			newConditions: capi.Conditions{{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionTrue, Message: "upgrading to 15.1.0"}},
+		},
+		{
+			name:          "case 7: Ready condition set to Unknown",
+			oldConditions: capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}},
+			newConditions: capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionUnknown}},
+		},
+		{
+			name:          "case 8: Ready condition set to False without a reason",
+			oldConditions: capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}},
+			newConditions: capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse}},
+			errorMatcher:  errors.IsInvalidConditionModification,
+		},
+		{
+			name:          "case 9: Ready condition removed",
+			oldConditions: capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}},
+			errorMatcher:  errors.IsInvalidConditionModification,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+
+			// Create a new logger that is used by all admitters.
+			var newLogger micrologger.Logger
+			{
+				newLogger, err = micrologger.New(micrologger.Config{})
+				if err != nil {
+					panic(microerror.JSON(err))
+				}
+			}
+
+			ctx := context.Background()
+			fakeK8sClient := unittest.FakeK8sClient()
+			ctrlClient := fakeK8sClient.CtrlClient()
+
+			for _, name := range []string{"v15.0.0", "v15.1.0"} {
+				release := &releasev1alpha1.Release{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name,
+					},
+					Spec: releasev1alpha1.ReleaseSpec{
+						State: releasev1alpha1.StateActive,
+					},
+				}
+				err = ctrlClient.Create(ctx, release)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			handler, err := NewWebhookHandler(WebhookHandlerConfig{
+				BaseDomain: "k8s.test.westeurope.azure.gigantic.io",
+				CtrlClient: ctrlClient,
+				CtrlReader: ctrlClient,
+				Decoder:    unittest.NewFakeDecoder(),
+				Logger:     newLogger,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			oldCluster := builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"}),
+				builder.Conditions(tc.oldConditions),
+			)
+			newCluster := builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"}),
+				builder.Conditions(tc.newConditions),
+			)
+			err = handler.OnStatusUpdateValidate(ctx, oldCluster, newCluster)
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
@@ -4,12 +4,12 @@ import (
 	"context"
 	"reflect"
 
-	aeconditions "github.com/giantswarm/apiextensions/v6/pkg/conditions"
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
 	"github.com/giantswarm/microerror"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
-	capiconditions "sigs.k8s.io/cluster-api/util/conditions"
 
 	"github.com/giantswarm/azure-admission-controller/internal/conditions"
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
 	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
 	"github.com/giantswarm/azure-admission-controller/internal/scheduledupgrades"
@@ -33,42 +33,21 @@ func (h *WebhookHandler) OnUpdateValidate(ctx context.Context, oldObject interfa
 		return microerror.Mask(err)
 	}
 
-	err = clusterNewCR.ValidateUpdate(clusterOldCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = generic.ValidateOrganizationLabelUnchanged(clusterOldCR, clusterNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = validateClusterNetworkUnchanged(*clusterOldCR, *clusterNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = validateControlPlaneEndpointUnchanged(*clusterOldCR, *clusterNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	err = conditions.ValidateClusterConditions(clusterOldCR, clusterNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
 
-	err = scheduledupgrades.ValidateClusterAnnotationUpgradeTime(clusterOldCR, clusterNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	validationErrors.Add(nil, clusterNewCR.ValidateUpdate(clusterOldCR))
+	validationErrors.Add(generic.OrganizationLabelPath, generic.ValidateOrganizationLabelUnchanged(clusterOldCR, clusterNewCR))
+	validationErrors.Add(clusterNetworkPath, enforcement.Apply(ctx, enforcement.ClusterClusterNetwork, validateClusterNetworkUnchanged(*clusterOldCR, *clusterNewCR)))
+	validationErrors.Add(controlPlaneEndpointPath, enforcement.Apply(ctx, enforcement.ClusterControlPlaneEndpoint, validateControlPlaneEndpointUnchanged(*clusterOldCR, *clusterNewCR)))
+	validationErrors.Add(upgradeTimeAnnotationPath, enforcement.Apply(ctx, enforcement.ClusterUpgradeTime, scheduledupgrades.ValidateClusterAnnotationUpgradeTime(clusterOldCR, clusterNewCR)))
+	validationErrors.Add(upgradeTimeAnnotationPath, enforcement.Apply(ctx, enforcement.ClusterMaintenanceWindow, scheduledupgrades.ValidateClusterAnnotationUpgradeTimeSchedule(h.maintenance.Schedule(clusterNewCR.GetLabels()[label.Organization]), clusterOldCR, clusterNewCR)))
+	validationErrors.Add(upgradeReleaseAnnotationPath, enforcement.Apply(ctx, enforcement.ClusterUpgradeRelease, scheduledupgrades.ValidateClusterAnnotationUpgradeRelease(ctx, h.ctrlClient, h.releasePolicy.Policy(), clusterNewCR)))
+	validationErrors.Add(generic.ReleaseVersionLabelPath, h.validateRelease(ctx, clusterOldCR, clusterNewCR))
+	validationErrors.Add(generic.ReleaseVersionLabelPath, enforcement.Apply(ctx, enforcement.ClusterChangeFreeze, h.validateChangeFreeze(clusterOldCR, clusterNewCR)))
 
-	err = scheduledupgrades.ValidateClusterAnnotationUpgradeRelease(ctx, h.ctrlClient, clusterNewCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	return h.validateRelease(ctx, clusterOldCR, clusterNewCR)
+	return microerror.Mask(validationErrors.Err())
 }
 
 func validateClusterNetworkUnchanged(old capi.Cluster, new capi.Cluster) error {
@@ -120,12 +99,23 @@ func (h *WebhookHandler) validateRelease(ctx context.Context, clusterOldCR *capi
 
 	if !newClusterVersion.Equals(oldClusterVersion) {
 		// Upgrade is triggered, let's check if we allow it
-		if capiconditions.IsTrue(clusterOldCR, aeconditions.CreatingCondition) {
-			return microerror.Maskf(errors.InvalidOperationError, "upgrade cannot be initiated now, Cluster condition %s is set to True, cluster is currently being created", aeconditions.CreatingCondition)
-		} else if capiconditions.IsTrue(clusterOldCR, aeconditions.UpgradingCondition) {
-			return microerror.Maskf(errors.InvalidOperationError, "upgrade cannot be initiated now, Cluster condition %s is set to True, cluster is already being upgraded", aeconditions.UpgradingCondition)
+		err = conditions.ValidateUpgradeCanBeInitiated(clusterOldCR)
+		if err != nil {
+			return microerror.Mask(err)
 		}
 	}
 
-	return releaseversion.Validate(ctx, h.ctrlClient, oldClusterVersion, newClusterVersion)
+	policy := h.releasePolicy.Policy()
+
+	err = releaseversion.Validate(ctx, h.ctrlClient, policy, oldClusterVersion, newClusterVersion)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = releaseversion.ValidatePolicyOnUpgrade(ctx, h.ctrlClient, policy, oldClusterVersion, newClusterVersion)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
 }
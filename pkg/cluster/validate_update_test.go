@@ -2,13 +2,26 @@ package cluster
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/giantswarm/apiextensions/v6/pkg/annotation"
+	aeconditions "github.com/giantswarm/apiextensions/v6/pkg/conditions"
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
+	releasev1alpha1 "github.com/giantswarm/release-operator/v3/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 
+	"github.com/giantswarm/azure-admission-controller/internal/maintenance"
+	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
+	"github.com/giantswarm/azure-admission-controller/internal/scheduledupgrades"
 	builder "github.com/giantswarm/azure-admission-controller/internal/test/cluster"
 	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
 )
@@ -163,3 +176,415 @@ func TestClusterUpdateValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterValidateReleasePolicy(t *testing.T) {
+	type testCase struct {
+		name         string
+		oldCluster   *capi.Cluster
+		newCluster   *capi.Cluster
+		errorMatcher func(err error) bool
+	}
+
+	withRelease := func(version string) map[string]string {
+		return map[string]string{label.ReleaseVersion: version}
+	}
+
+	var testCases = []testCase{
+		{
+			name:         "case 0: create with a deprecated release",
+			newCluster:   clusterObject("ab123", nil, "", 0, withRelease("15.0.1")),
+			errorMatcher: releaseversion.IsDeprecatedReleaseError,
+		},
+		{
+			name:         "case 1: create with a release below the minimum",
+			newCluster:   clusterObject("ab123", nil, "", 0, withRelease("14.0.0")),
+			errorMatcher: releaseversion.IsBelowMinimumReleaseError,
+		},
+		{
+			name:         "case 2: upgrade from an older patch release",
+			oldCluster:   builder.BuildCluster(builder.Labels(withRelease("15.0.0"))),
+			newCluster:   builder.BuildCluster(builder.Labels(withRelease("15.1.0"))),
+			errorMatcher: releaseversion.IsPatchSkippedError,
+		},
+		{
+			name:       "case 3: upgrade from the latest patch release",
+			oldCluster: builder.BuildCluster(builder.Labels(withRelease("15.0.2"))),
+			newCluster: builder.BuildCluster(builder.Labels(withRelease("15.1.0"))),
+		},
+		{
+			name:       "case 4: unchanged release below the minimum",
+			oldCluster: builder.BuildCluster(builder.Labels(withRelease("14.0.0"))),
+			newCluster: builder.BuildCluster(builder.Labels(withRelease("14.0.0"))),
+		},
+		{
+			name:       "case 5: scheduled upgrade from an older patch release",
+			oldCluster: builder.BuildCluster(builder.Labels(withRelease("15.0.0"))),
+			newCluster: builder.BuildCluster(
+				builder.Labels(withRelease("15.0.0")),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetRelease: "15.1.0"}),
+			),
+			errorMatcher: scheduledupgrades.IsNotAllowed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+
+			// Create a new logger that is used by all admitters.
+			var newLogger micrologger.Logger
+			{
+				newLogger, err = micrologger.New(micrologger.Config{})
+				if err != nil {
+					panic(microerror.JSON(err))
+				}
+			}
+
+			ctx := context.Background()
+			fakeK8sClient := unittest.FakeK8sClient()
+			ctrlClient := fakeK8sClient.CtrlClient()
+
+			releases := map[string]releasev1alpha1.ReleaseState{
+				"v14.0.0": releasev1alpha1.StateActive,
+				"v15.0.0": releasev1alpha1.StateActive,
+				"v15.0.1": releasev1alpha1.StateDeprecated,
+				"v15.0.2": releasev1alpha1.StateActive,
+				"v15.1.0": releasev1alpha1.StateActive,
+			}
+			for name, state := range releases {
+				release := &releasev1alpha1.Release{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name,
+					},
+					Spec: releasev1alpha1.ReleaseSpec{
+						State: state,
+					},
+				}
+				err = ctrlClient.Create(ctx, release)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			policyFile := filepath.Join(t.TempDir(), "release-policy.yaml")
+			err = os.WriteFile(policyFile, []byte("minimumRelease: \"15\"\npatchPolicy: latest\n"), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+			releasePolicy, err := releaseversion.NewPolicyRegistry(releaseversion.PolicyRegistryConfig{
+				Logger: newLogger,
+				File:   policyFile,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler, err := NewWebhookHandler(WebhookHandlerConfig{
+				BaseDomain:    "k8s.test.westeurope.azure.gigantic.io",
+				CtrlClient:    ctrlClient,
+				CtrlReader:    ctrlClient,
+				Decoder:       unittest.NewFakeDecoder(),
+				Logger:        newLogger,
+				ReleasePolicy: releasePolicy,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.oldCluster == nil {
+				err = handler.validateReleaseOnCreate(ctx, tc.newCluster)
+			} else {
+				err = handler.OnUpdateValidate(ctx, tc.oldCluster, tc.newCluster)
+			}
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
+
+func TestClusterValidateScheduledUpgradeRelease(t *testing.T) {
+	type testCase struct {
+		name          string
+		conditions    capi.Conditions
+		release       string
+		targetRelease string
+		errorMatcher  func(err error) bool
+	}
+
+	upgradingTo := func(version string) capi.Condition {
+		message, err := aeconditions.SerializeUpgradingConditionMessage(aeconditions.UpgradingConditionMessage{
+			Message:        "Upgrade in progress",
+			ReleaseVersion: version,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return capi.Condition{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionTrue, Message: message}
+	}
+
+	var testCases = []testCase{
+		{
+			name:          "case 0: schedule an upgrade of a running cluster",
+			release:       "15.0.0",
+			targetRelease: "15.1.0",
+		},
+		{
+			name:          "case 1: schedule an upgrade while the cluster is being created",
+			conditions:    capi.Conditions{{Type: aeconditions.CreatingCondition, Status: corev1.ConditionTrue}},
+			release:       "15.0.0",
+			targetRelease: "15.1.0",
+			errorMatcher:  scheduledupgrades.IsNotAllowed,
+		},
+		{
+			name:          "case 2: schedule an upgrade to the release being rolled out",
+			conditions:    capi.Conditions{upgradingTo("15.1.0")},
+			release:       "15.0.0",
+			targetRelease: "15.1.0",
+			errorMatcher:  scheduledupgrades.IsNotAllowed,
+		},
+		{
+			name:          "case 3: schedule an upgrade to a release older than the one being rolled out",
+			conditions:    capi.Conditions{upgradingTo("16.0.0")},
+			release:       "15.0.0",
+			targetRelease: "15.1.0",
+			errorMatcher:  scheduledupgrades.IsNotAllowed,
+		},
+		{
+			name:          "case 4: schedule an upgrade to a release newer than the one being rolled out",
+			conditions:    capi.Conditions{upgradingTo("15.1.0")},
+			release:       "15.1.0",
+			targetRelease: "16.0.0",
+		},
+		{
+			name:          "case 5: finished upgrades don't limit the target release",
+			conditions:    capi.Conditions{{Type: aeconditions.UpgradingCondition, Status: corev1.ConditionFalse, Reason: aeconditions.UpgradeCompletedReason}},
+			release:       "15.0.0",
+			targetRelease: "15.1.0",
+		},
+		{
+			name:          "case 6: schedule an upgrade to a release without azure-operator",
+			release:       "16.0.0",
+			targetRelease: "20.0.0",
+			errorMatcher:  scheduledupgrades.IsNotAllowed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+
+			// Create a new logger that is used by all admitters.
+			var newLogger micrologger.Logger
+			{
+				newLogger, err = micrologger.New(micrologger.Config{})
+				if err != nil {
+					panic(microerror.JSON(err))
+				}
+			}
+
+			ctx := context.Background()
+			fakeK8sClient := unittest.FakeK8sClient()
+			ctrlClient := fakeK8sClient.CtrlClient()
+
+			releases := map[string]string{
+				"v15.0.0": "5.0.0",
+				"v15.1.0": "5.1.0",
+				"v16.0.0": "6.0.0",
+				"v20.0.0": "",
+			}
+			for name, azureOperatorVersion := range releases {
+				release := &releasev1alpha1.Release{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name,
+					},
+					Spec: releasev1alpha1.ReleaseSpec{
+						Components: []releasev1alpha1.ReleaseSpecComponent{
+							{Name: "kubernetes", Version: "1.22.0"},
+						},
+						State: releasev1alpha1.StateActive,
+					},
+				}
+				if azureOperatorVersion != "" {
+					release.Spec.Components = append(release.Spec.Components, releasev1alpha1.ReleaseSpecComponent{Name: "azure-operator", Version: azureOperatorVersion})
+				}
+				err = ctrlClient.Create(ctx, release)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			handler, err := NewWebhookHandler(WebhookHandlerConfig{
+				BaseDomain: "k8s.test.westeurope.azure.gigantic.io",
+				CtrlClient: ctrlClient,
+				CtrlReader: ctrlClient,
+				Decoder:    unittest.NewFakeDecoder(),
+				Logger:     newLogger,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			oldCluster := builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: tc.release}),
+				builder.Conditions(tc.conditions),
+			)
+			newCluster := builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: tc.release}),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetRelease: tc.targetRelease}),
+				builder.Conditions(tc.conditions),
+			)
+			err = handler.OnUpdateValidate(ctx, oldCluster, newCluster)
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
+
+func TestClusterValidateMaintenance(t *testing.T) {
+	type testCase struct {
+		name         string
+		oldCluster   *capi.Cluster
+		newCluster   *capi.Cluster
+		errorMatcher func(err error) bool
+	}
+
+	withRelease := func(version string) map[string]string {
+		return map[string]string{label.ReleaseVersion: version, label.Organization: "acme"}
+	}
+
+	var testCases = []testCase{
+		{
+			name:         "case 0: release changed during a change freeze",
+			oldCluster:   builder.BuildCluster(builder.Labels(withRelease("15.0.0"))),
+			newCluster:   builder.BuildCluster(builder.Labels(withRelease("15.0.1"))),
+			errorMatcher: maintenance.IsChangeFreeze,
+		},
+		{
+			name:       "case 1: release changed during a change freeze with override",
+			oldCluster: builder.BuildCluster(builder.Labels(withRelease("15.0.0"))),
+			newCluster: builder.BuildCluster(
+				builder.Labels(withRelease("15.0.1")),
+				builder.Annotations(map[string]string{maintenance.FreezeOverrideAnnotation: "true"}),
+			),
+		},
+		{
+			name:       "case 2: release unchanged during a change freeze",
+			oldCluster: builder.BuildCluster(builder.Labels(withRelease("15.0.0"))),
+			newCluster: builder.BuildCluster(builder.Labels(withRelease("15.0.0"))),
+		},
+		{
+			name:       "case 3: upgrade scheduled during a change freeze",
+			oldCluster: builder.BuildCluster(builder.Labels(withRelease("15.0.0"))),
+			newCluster: builder.BuildCluster(
+				builder.Labels(withRelease("15.0.0")),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetTime: time.Now().UTC().Add(time.Hour).Format(time.RFC822)}),
+			),
+			errorMatcher: scheduledupgrades.IsNotAllowed,
+		},
+		{
+			name:       "case 4: upgrade scheduled after the change freeze",
+			oldCluster: builder.BuildCluster(builder.Labels(withRelease("15.0.0"))),
+			newCluster: builder.BuildCluster(
+				builder.Labels(withRelease("15.0.0")),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetTime: time.Now().UTC().Add(72 * time.Hour).Format(time.RFC822)}),
+			),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+
+			// Create a new logger that is used by all admitters.
+			var newLogger micrologger.Logger
+			{
+				newLogger, err = micrologger.New(micrologger.Config{})
+				if err != nil {
+					panic(microerror.JSON(err))
+				}
+			}
+
+			ctx := context.Background()
+			fakeK8sClient := unittest.FakeK8sClient()
+			ctrlClient := fakeK8sClient.CtrlClient()
+
+			for _, name := range []string{"v15.0.0", "v15.0.1"} {
+				release := &releasev1alpha1.Release{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name,
+					},
+					Spec: releasev1alpha1.ReleaseSpec{
+						State: releasev1alpha1.StateActive,
+					},
+				}
+				err = ctrlClient.Create(ctx, release)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// The change freeze of the organization lasts from yesterday
+			// until tomorrow.
+			maintenanceFile := filepath.Join(t.TempDir(), "maintenance.yaml")
+			config := fmt.Sprintf("organizations:\n  acme:\n    freezes:\n      - start: %s\n        end: %s\n",
+				time.Now().UTC().Add(-24*time.Hour).Format(time.RFC3339),
+				time.Now().UTC().Add(24*time.Hour).Format(time.RFC3339),
+			)
+			err = os.WriteFile(maintenanceFile, []byte(config), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+			maintenanceRegistry, err := maintenance.NewRegistry(maintenance.RegistryConfig{
+				Logger: newLogger,
+				File:   maintenanceFile,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler, err := NewWebhookHandler(WebhookHandlerConfig{
+				BaseDomain:  "k8s.test.westeurope.azure.gigantic.io",
+				CtrlClient:  ctrlClient,
+				CtrlReader:  ctrlClient,
+				Decoder:     unittest.NewFakeDecoder(),
+				Logger:      newLogger,
+				Maintenance: maintenanceRegistry,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = handler.OnUpdateValidate(ctx, tc.oldCluster, tc.newCluster)
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
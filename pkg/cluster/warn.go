@@ -0,0 +1,76 @@
+package cluster
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
+	"github.com/giantswarm/azure-admission-controller/internal/scheduledupgrades"
+	"github.com/giantswarm/azure-admission-controller/internal/semverhelper"
+	"github.com/giantswarm/azure-admission-controller/pkg/key"
+)
+
+func (h *WebhookHandler) OnCreateWarn(ctx context.Context, object interface{}) ([]string, error) {
+	clusterCR, err := key.ToClusterPtr(object)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var warnings []string
+
+	version, err := semverhelper.GetSemverFromLabels(clusterCR.Labels)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	releaseWarnings, err := releaseversion.DeprecationWarnings(ctx, h.ctrlClient, version)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	warnings = append(warnings, releaseWarnings...)
+
+	warnings = append(warnings, scheduledupgrades.WarnClusterAnnotationUpgradeTime(h.maintenance.Schedule(clusterCR.GetLabels()[label.Organization]), nil, clusterCR)...)
+
+	return warnings, nil
+}
+
+func (h *WebhookHandler) OnUpdateWarn(ctx context.Context, oldObject interface{}, object interface{}) ([]string, error) {
+	clusterNewCR, err := key.ToClusterPtr(object)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	clusterOldCR, err := key.ToClusterPtr(oldObject)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var warnings []string
+
+	oldVersion, err := semverhelper.GetSemverFromLabels(clusterOldCR.Labels)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	newVersion, err := semverhelper.GetSemverFromLabels(clusterNewCR.Labels)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	// Only warn about deprecated releases when upgrading to one.
+	if !newVersion.Equals(oldVersion) {
+		releaseWarnings, err := releaseversion.DeprecationWarnings(ctx, h.ctrlClient, newVersion)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		warnings = append(warnings, releaseWarnings...)
+	}
+
+	// Clusters staying on a release below the minimum supported release are
+	// not denied, so that they can still be changed, but warned about.
+	warnings = append(warnings, releaseversion.MinimumReleaseWarnings(h.releasePolicy.Policy(), newVersion)...)
+
+	warnings = append(warnings, scheduledupgrades.WarnClusterAnnotationUpgradeTime(h.maintenance.Schedule(clusterNewCR.GetLabels()[label.Organization]), clusterOldCR, clusterNewCR)...)
+
+	return warnings, nil
+}
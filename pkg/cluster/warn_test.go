@@ -0,0 +1,187 @@
+package cluster
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v6/pkg/annotation"
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	releasev1alpha1 "github.com/giantswarm/release-operator/v3/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/maintenance"
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/cluster"
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+func TestClusterUpdateWarn(t *testing.T) {
+	type testCase struct {
+		name       string
+		oldCluster *capi.Cluster
+		newCluster *capi.Cluster
+		// maintenance is the content of the maintenance file, if any.
+		maintenance      string
+		expectedWarnings int
+	}
+
+	var testCases = []testCase{
+		{
+			name:             "case 0: nothing changed",
+			oldCluster:       builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"})),
+			newCluster:       builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"})),
+			expectedWarnings: 0,
+		},
+		{
+			name:             "case 1: upgrade to a supported release",
+			oldCluster:       builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"})),
+			newCluster:       builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.1.0"})),
+			expectedWarnings: 0,
+		},
+		{
+			name:             "case 2: upgrade to a deprecated release",
+			oldCluster:       builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"})),
+			newCluster:       builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.0.1"})),
+			expectedWarnings: 1,
+		},
+		{
+			name:       "case 3: upgrade scheduled within business hours",
+			oldCluster: builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"})),
+			newCluster: builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"}),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetTime: "01 Jan 30 10:00 UTC"}),
+			),
+			expectedWarnings: 0,
+		},
+		{
+			name:       "case 4: upgrade scheduled at night",
+			oldCluster: builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"})),
+			newCluster: builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"}),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetTime: "01 Jan 30 03:00 UTC"}),
+			),
+			expectedWarnings: 1,
+		},
+		{
+			name:       "case 5: upgrade scheduled on a weekend",
+			oldCluster: builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"})),
+			newCluster: builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"}),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetTime: "05 Jan 30 10:00 UTC"}),
+			),
+			expectedWarnings: 1,
+		},
+		{
+			name: "case 6: unchanged upgrade schedule is not repeated",
+			oldCluster: builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"}),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetTime: "05 Jan 30 10:00 UTC"}),
+			),
+			newCluster: builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"}),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetTime: "05 Jan 30 10:00 UTC"}),
+			),
+			expectedWarnings: 0,
+		},
+		{
+			name:       "case 7: upgrade scheduled on a weekend within a maintenance window",
+			oldCluster: builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"})),
+			newCluster: builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"}),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetTime: "05 Jan 30 10:00 UTC"}),
+			),
+			maintenance:      "installation:\n  windows:\n    - days: [Saturday, Sunday]\n      start: \"00:00\"\n      end: \"24:00\"\n",
+			expectedWarnings: 0,
+		},
+		{
+			name:       "case 8: upgrade scheduled at night within a maintenance window",
+			oldCluster: builder.BuildCluster(builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"})),
+			newCluster: builder.BuildCluster(
+				builder.Labels(map[string]string{label.ReleaseVersion: "15.0.0"}),
+				builder.Annotations(map[string]string{annotation.UpdateScheduleTargetTime: "01 Jan 30 03:00 UTC"}),
+			),
+			maintenance:      "installation:\n  windows:\n    - start: \"00:00\"\n      end: \"06:00\"\n",
+			expectedWarnings: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+
+			// Create a new logger that is used by all admitters.
+			var newLogger micrologger.Logger
+			{
+				newLogger, err = micrologger.New(micrologger.Config{})
+				if err != nil {
+					panic(microerror.JSON(err))
+				}
+			}
+
+			ctx := context.Background()
+			fakeK8sClient := unittest.FakeK8sClient()
+			ctrlClient := fakeK8sClient.CtrlClient()
+
+			releases := map[string]releasev1alpha1.ReleaseState{
+				"v15.0.0": releasev1alpha1.StateActive,
+				"v15.0.1": releasev1alpha1.StateDeprecated,
+				"v15.1.0": releasev1alpha1.StateActive,
+			}
+			for name, state := range releases {
+				release := &releasev1alpha1.Release{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name,
+					},
+					Spec: releasev1alpha1.ReleaseSpec{
+						State: state,
+					},
+				}
+				err = ctrlClient.Create(ctx, release)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var maintenanceRegistry *maintenance.Registry
+			if tc.maintenance != "" {
+				maintenanceFile := filepath.Join(t.TempDir(), "maintenance.yaml")
+				err = os.WriteFile(maintenanceFile, []byte(tc.maintenance), 0600)
+				if err != nil {
+					t.Fatal(err)
+				}
+				maintenanceRegistry, err = maintenance.NewRegistry(maintenance.RegistryConfig{
+					Logger: newLogger,
+					File:   maintenanceFile,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			handler, err := NewWebhookHandler(WebhookHandlerConfig{
+				BaseDomain:  "k8s.test.westeurope.azure.gigantic.io",
+				CtrlClient:  ctrlClient,
+				CtrlReader:  ctrlClient,
+				Decoder:     unittest.NewFakeDecoder(),
+				Logger:      newLogger,
+				Maintenance: maintenanceRegistry,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			warnings, err := handler.OnUpdateWarn(ctx, tc.oldCluster, tc.newCluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(warnings) != tc.expectedWarnings {
+				t.Fatalf("expected %d warnings, got %d: %v", tc.expectedWarnings, len(warnings), warnings)
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/maintenance"
+	"github.com/giantswarm/azure-admission-controller/internal/releaseversion"
 )
 
 type WebhookHandler struct {
@@ -17,6 +19,9 @@ type WebhookHandler struct {
 	ctrlReader client.Reader
 	ctrlClient client.Client
 	logger     micrologger.Logger
+
+	maintenance   *maintenance.Registry
+	releasePolicy *releaseversion.PolicyRegistry
 }
 
 type WebhookHandlerConfig struct {
@@ -25,6 +30,15 @@ type WebhookHandlerConfig struct {
 	CtrlReader client.Reader
 	CtrlClient client.Client
 	Logger     micrologger.Logger
+
+	// Maintenance holds the maintenance windows and change freezes of the
+	// organizations. It is optional, when nil upgrades can happen at any
+	// time.
+	Maintenance *maintenance.Registry
+	// ReleasePolicy is the release policy of the installation. It is
+	// optional, when nil every release is supported and any patch release
+	// can be upgraded.
+	ReleasePolicy *releaseversion.PolicyRegistry
 }
 
 func NewWebhookHandler(config WebhookHandlerConfig) (*WebhookHandler, error) {
@@ -50,6 +64,9 @@ func NewWebhookHandler(config WebhookHandlerConfig) (*WebhookHandler, error) {
 		ctrlReader: config.CtrlReader,
 		ctrlClient: config.CtrlClient,
 		logger:     config.Logger,
+
+		maintenance:   config.Maintenance,
+		releasePolicy: config.ReleasePolicy,
 	}
 
 	return v, nil
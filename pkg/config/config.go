@@ -1,31 +1,80 @@
 package config
 
 import (
+	"time"
+
 	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
+)
+
+const (
+	// CommandServe runs the webhooks. It is the default command.
+	CommandServe = "serve"
+	// CommandExportSKUCatalog exports the VM SKUs of some locations from the
+	// Azure API into a catalog file, see vmcapabilities.Catalog.
+	CommandExportSKUCatalog = "export-sku-catalog"
 )
 
 const (
-	defaultAddress = ":8080"
+	defaultAddress       = ":8080"
+	defaultVMSKUCacheTTL = "1h"
 )
 
 type Config struct {
-	BaseDomain        string
-	CertFile          string
-	KeyFile           string
-	Address           string
-	AvailabilityZones string
-	Location          string
+	Command string
+
+	BaseDomain            string
+	CertFile              string
+	KeyFile               string
+	Address               string
+	AvailabilityZones     string
+	AzureQuotaCheck       bool
+	EnforcementConfigFile string
+	GPUOrganizations      []string
+	Location              string
+	MaintenanceFile       string
+	QuotaFile             string
+	ReleasePolicyFile     string
+	SizingPolicyFile      string
+	VMSKUCacheTTL         time.Duration
+	VMSKUCatalogFile      string
+	VMSKUSource           string
+
+	ExportSKUCatalog ExportSKUCatalogConfig
+}
+
+type ExportSKUCatalogConfig struct {
+	Locations      []string
+	Output         string
+	SubscriptionID string
 }
 
 func Parse() (Config, error) {
 	var result Config
 
-	kingpin.Flag("tls-cert-file", "File containing the certificate for HTTPS").Required().StringVar(&result.CertFile)
-	kingpin.Flag("tls-key-file", "File containing the private key for HTTPS").Required().StringVar(&result.KeyFile)
-	kingpin.Flag("address", "The address to listen on").Default(defaultAddress).StringVar(&result.Address)
-	kingpin.Flag("base-domain", "The base domain of the installation").Required().StringVar(&result.BaseDomain)
-	kingpin.Flag("location", "The azure region of the installation").Required().StringVar(&result.Location)
+	serve := kingpin.Command(CommandServe, "Run the admission webhooks").Default()
+	serve.Flag("tls-cert-file", "File containing the certificate for HTTPS").Required().StringVar(&result.CertFile)
+	serve.Flag("tls-key-file", "File containing the private key for HTTPS").Required().StringVar(&result.KeyFile)
+	serve.Flag("address", "The address to listen on").Default(defaultAddress).StringVar(&result.Address)
+	serve.Flag("base-domain", "The base domain of the installation").Required().StringVar(&result.BaseDomain)
+	serve.Flag("location", "The azure region of the installation").Required().StringVar(&result.Location)
+	serve.Flag("vm-sku-cache-ttl", "How long the Azure VM SKUs are cached before they are refreshed").Default(defaultVMSKUCacheTTL).DurationVar(&result.VMSKUCacheTTL)
+	serve.Flag("vm-sku-source", "Where the Azure VM SKUs are listed from").Default(vmcapabilities.SourceLive).EnumVar(&result.VMSKUSource, vmcapabilities.Sources()...)
+	serve.Flag("vm-sku-catalog-file", "File containing the Azure VM SKU catalog, required with the file and live-with-file-fallback sources").StringVar(&result.VMSKUCatalogFile)
+	serve.Flag("gpu-organization", "An organization allowed to use GPU VM sizes, can be repeated. All organizations are allowed when not set").StringsVar(&result.GPUOrganizations)
+	serve.Flag("sizing-policy-file", "File containing the node pool sizing policies, only the built-in minimums apply when empty").StringVar(&result.SizingPolicyFile)
+	serve.Flag("quota-file", "File containing the node pool quotas of the organizations, no quota is enforced when empty").StringVar(&result.QuotaFile)
+	serve.Flag("release-policy-file", "File containing the release policy, every release is supported and any patch release can be upgraded when empty").StringVar(&result.ReleasePolicyFile)
+	serve.Flag("maintenance-file", "File containing the maintenance windows and change freezes of the organizations, upgrades can happen at any time when empty").StringVar(&result.MaintenanceFile)
+	serve.Flag("azure-quota-check", "Check the vCPU quota of the Azure subscription before node pools are created or scaled up").BoolVar(&result.AzureQuotaCheck)
+	serve.Flag("enforcement-config-file", "File containing the enforcement mode of the checks, all checks are enforced when empty").StringVar(&result.EnforcementConfigFile)
+
+	export := kingpin.Command(CommandExportSKUCatalog, "Export the Azure VM SKUs of some locations into a catalog file. Azure credentials are read from the AZURE_* environment variables")
+	export.Flag("location", "The azure region to export, can be repeated").Required().StringsVar(&result.ExportSKUCatalog.Locations)
+	export.Flag("subscription-id", "The azure subscription to list the VM SKUs of").Envar("AZURE_SUBSCRIPTION_ID").Required().StringVar(&result.ExportSKUCatalog.SubscriptionID)
+	export.Flag("output", "The catalog file to write, JSON when it ends with .json and YAML otherwise. Standard output when empty").StringVar(&result.ExportSKUCatalog.Output)
 
-	kingpin.Parse()
+	result.Command = kingpin.Parse()
 	return result, nil
 }
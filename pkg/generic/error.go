@@ -2,6 +2,8 @@ package generic
 
 import (
 	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
 var clusterNotFoundError = &microerror.Error{
@@ -102,3 +104,38 @@ var notAllowedError = &microerror.Error{
 func IsNotAllowed(err error) bool {
 	return microerror.Cause(err) == notAllowedError
 }
+
+var deletionProtectedError = &microerror.Error{
+	Kind: "deletionProtectedError",
+}
+
+// IsDeletionProtectedError asserts deletionProtectedError.
+func IsDeletionProtectedError(err error) bool {
+	return microerror.Cause(err) == deletionProtectedError
+}
+
+var gpuNodePoolConventionError = &microerror.Error{
+	Kind: "gpuNodePoolConventionError",
+}
+
+// IsGPUNodePoolConventionError asserts gpuNodePoolConventionError.
+func IsGPUNodePoolConventionError(err error) bool {
+	return microerror.Cause(err) == gpuNodePoolConventionError
+}
+
+func init() {
+	errors.RegisterValidationErrors(
+		clusterNotFoundError,
+		nodepoolOrgDoesNotMatchClusterOrgError,
+		organizationLabelNotFoundError,
+		organizationNotFoundError,
+		organizationLabelWasChangedError,
+		clusterLabelNotFoundError,
+		releaseLabelNotFoundError,
+		azureOperatorVersionLabelNotFoundError,
+		componentNotFoundInReleaseError,
+		notAllowedError,
+		deletionProtectedError,
+		gpuNodePoolConventionError,
+	)
+}
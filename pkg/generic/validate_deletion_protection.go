@@ -0,0 +1,23 @@
+package generic
+
+import (
+	"github.com/giantswarm/microerror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// DeletionProtectionAnnotation protects a resource from being deleted by
+// mistake. It has to be removed before the resource can be deleted.
+const DeletionProtectionAnnotation = "giantswarm.io/deletion-protection"
+
+// DeletionProtectionAnnotationPath is the field path of the deletion protection
+// annotation, used when reporting violations of the deletion validations.
+var DeletionProtectionAnnotationPath = field.NewPath("metadata", "annotations").Key(DeletionProtectionAnnotation)
+
+func ValidateDeletionProtectionRemoved(obj metav1.Object) error {
+	if _, exists := obj.GetAnnotations()[DeletionProtectionAnnotation]; exists {
+		return microerror.Maskf(deletionProtectedError, "%#q is protected from deletion, annotation %#q must be removed first", obj.GetName(), DeletionProtectionAnnotation)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package generic
+
+import (
+	"strings"
+
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// GPULabel marks the MachinePool of a node pool with a GPU VM size, so
+	// that GPU workloads can select its nodes. Its value must be "true".
+	GPULabel = "giantswarm.io/gpu"
+
+	// NodeTaintsAnnotation lists the taints of the nodes of a MachinePool,
+	// comma separated, in the format used by kubectl taint, e.g.
+	// "nvidia.com/gpu=present:NoSchedule".
+	NodeTaintsAnnotation = "giantswarm.io/node-taints"
+
+	// GPUTaintKey is the taint key node pools with a GPU VM size must have
+	// with the NoSchedule effect, so that only workloads tolerating it end
+	// up on the expensive GPU nodes.
+	GPUTaintKey = "nvidia.com/gpu"
+)
+
+// GPULabelPath and NodeTaintsAnnotationPath are the field paths used when
+// reporting violations of the GPU node pool conventions.
+var (
+	GPULabelPath             = field.NewPath("metadata", "labels").Key(GPULabel)
+	NodeTaintsAnnotationPath = field.NewPath("metadata", "annotations").Key(NodeTaintsAnnotation)
+)
+
+// ValidateGPULabel checks that the MachinePool of a node pool with a GPU VM
+// size has the GPU label.
+func ValidateGPULabel(obj metav1.Object) error {
+	if obj.GetLabels()[GPULabel] != "true" {
+		return microerror.Maskf(gpuNodePoolConventionError, "node pools with a GPU VM size must have the label %s=true", GPULabel)
+	}
+
+	return nil
+}
+
+// ValidateGPUTaint checks that the MachinePool of a node pool with a GPU VM
+// size taints its nodes with the GPU taint.
+func ValidateGPUTaint(obj metav1.Object) error {
+	annotation, exists := obj.GetAnnotations()[NodeTaintsAnnotation]
+	if !exists {
+		return microerror.Maskf(gpuNodePoolConventionError, "node pools with a GPU VM size must have the annotation %#q with the taint %s:%s", NodeTaintsAnnotation, GPUTaintKey, corev1.TaintEffectNoSchedule)
+	}
+
+	for _, taint := range strings.Split(annotation, ",") {
+		taint = strings.TrimSpace(taint)
+
+		keyValue, effect, ok := strings.Cut(taint, ":")
+		if !ok {
+			return microerror.Maskf(gpuNodePoolConventionError, "taint %#q in annotation %#q must have the format key[=value]:effect", taint, NodeTaintsAnnotation)
+		}
+		key, _, _ := strings.Cut(keyValue, "=")
+
+		if key == GPUTaintKey && corev1.TaintEffect(effect) == corev1.TaintEffectNoSchedule {
+			return nil
+		}
+	}
+
+	return microerror.Maskf(gpuNodePoolConventionError, "node pools with a GPU VM size must have the taint %s:%s in the annotation %#q", GPUTaintKey, corev1.TaintEffectNoSchedule, NodeTaintsAnnotation)
+}
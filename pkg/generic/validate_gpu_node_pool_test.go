@@ -0,0 +1,84 @@
+package generic
+
+import (
+	"strconv"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ValidateGPUTaint(t *testing.T) {
+	testCases := []struct {
+		name         string
+		annotations  map[string]string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:         "case 0: GPU taint with a value",
+			annotations:  map[string]string{NodeTaintsAnnotation: "nvidia.com/gpu=present:NoSchedule"},
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 1: GPU taint without a value",
+			annotations:  map[string]string{NodeTaintsAnnotation: "nvidia.com/gpu:NoSchedule"},
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 2: GPU taint after other taints, with spaces",
+			annotations:  map[string]string{NodeTaintsAnnotation: "dedicated=ml:NoExecute, nvidia.com/gpu=present:NoSchedule"},
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 3: annotation missing",
+			annotations:  nil,
+			errorMatcher: IsGPUNodePoolConventionError,
+		},
+		{
+			name:         "case 4: GPU taint with another effect",
+			annotations:  map[string]string{NodeTaintsAnnotation: "nvidia.com/gpu=present:PreferNoSchedule"},
+			errorMatcher: IsGPUNodePoolConventionError,
+		},
+		{
+			name:         "case 5: only other taints",
+			annotations:  map[string]string{NodeTaintsAnnotation: "dedicated=ml:NoSchedule"},
+			errorMatcher: IsGPUNodePoolConventionError,
+		},
+		{
+			name:         "case 6: taint without effect",
+			annotations:  map[string]string{NodeTaintsAnnotation: "nvidia.com/gpu=present"},
+			errorMatcher: IsGPUNodePoolConventionError,
+		},
+		{
+			name:         "case 7: empty annotation",
+			annotations:  map[string]string{NodeTaintsAnnotation: ""},
+			errorMatcher: IsGPUNodePoolConventionError,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			obj := &GenericObject{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "ab123",
+					Namespace:   "default",
+					Annotations: tc.annotations,
+				},
+			}
+
+			err := ValidateGPUTaint(obj)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
@@ -8,12 +8,21 @@ import (
 	securityv1alpha1 "github.com/giantswarm/organization-operator/api/v1alpha1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/giantswarm/azure-admission-controller/internal/normalize"
 )
 
+// OrganizationLabelPath is the field path of the organization label, used when
+// reporting violations of the organization label validations.
+var OrganizationLabelPath = field.NewPath("metadata", "labels").Key(label.Organization)
+
+// ReleaseVersionLabelPath is the field path of the release version label, used
+// when reporting violations of the release validations.
+var ReleaseVersionLabelPath = field.NewPath("metadata", "labels").Key(label.ReleaseVersion)
+
 func ValidateOrganizationLabelUnchanged(old, new metav1.Object) error {
 	if _, exists := old.GetLabels()[label.Organization]; !exists {
 		return microerror.Maskf(organizationLabelNotFoundError, "meta CR doesn't contain Organization label %#q", label.Organization)
@@ -90,6 +90,19 @@ func ToMachinePoolPtr(v interface{}) (*capiexp.MachinePool, error) {
 	return customObjectPointer, nil
 }
 
+func ToAzureClusterIdentityPtr(v interface{}) (*capz.AzureClusterIdentity, error) {
+	if v == nil {
+		return nil, microerror.Maskf(errors.WrongTypeError, "expected '%T', got '%T'", &capz.AzureClusterIdentity{}, v)
+	}
+
+	customObjectPointer, ok := v.(*capz.AzureClusterIdentity)
+	if !ok {
+		return nil, microerror.Maskf(errors.WrongTypeError, "expected '%T', got '%T'", &capz.AzureClusterIdentity{}, v)
+	}
+
+	return customObjectPointer, nil
+}
+
 func ToAzureMachinePoolPtr(v interface{}) (*capzexp.AzureMachinePool, error) {
 	if v == nil {
 		return nil, microerror.Maskf(errors.WrongTypeError, "expected '%T', got '%T'", &capzexp.AzureMachinePool{}, v)
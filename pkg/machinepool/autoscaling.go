@@ -3,7 +3,6 @@ package machinepool
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/giantswarm/apiextensions/v6/pkg/annotation"
 	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
@@ -11,14 +10,6 @@ import (
 	"github.com/giantswarm/azure-admission-controller/pkg/mutator"
 )
 
-// Ensure the needed escapes are in place. See https://tools.ietf.org/html/rfc6901#section-3 .
-func escapeJSONPatchString(input string) string {
-	input = strings.ReplaceAll(input, "~", "~0")
-	input = strings.ReplaceAll(input, "/", "~1")
-
-	return input
-}
-
 // ensureAutoscalingAnnotations ensures the custom annotations used to determine the min and max replicas for
 // the cluster autoscaler are set in the Machinepool CR.
 func ensureAutoscalingAnnotations(h *WebhookHandler, machinePool *capiexp.MachinePool) []mutator.PatchOperation {
@@ -33,14 +24,14 @@ func ensureAutoscalingAnnotations(h *WebhookHandler, machinePool *capiexp.Machin
 	currentMin := clusterReplicas
 	if machinePool.Annotations[annotation.NodePoolMinSize] == "" {
 		h.Log("level", "debug", "message", fmt.Sprintf("setting MachinePool Annotation %s to %d", annotation.NodePoolMinSize, clusterReplicas))
-		patches = append(patches, *mutator.PatchAdd(fmt.Sprintf("/metadata/annotations/%s", escapeJSONPatchString(annotation.NodePoolMinSize)), fmt.Sprintf("%d", clusterReplicas)))
+		patches = append(patches, *mutator.PatchAdd(fmt.Sprintf("/metadata/annotations/%s", mutator.EscapeJSONPatchString(annotation.NodePoolMinSize)), fmt.Sprintf("%d", clusterReplicas)))
 	} else {
 		// Parse current value of min Size.
 		min, err := strconv.ParseInt(machinePool.Annotations[annotation.NodePoolMinSize], 10, 32)
 		if err != nil || min < 0 {
 			// Invalid annotation value, set it to the default.
 			h.Log("level", "debug", "message", fmt.Sprintf("setting MachinePool Annotation %s to %d", annotation.NodePoolMinSize, clusterReplicas))
-			patches = append(patches, mutator.PatchReplace(fmt.Sprintf("/metadata/annotations/%s", escapeJSONPatchString(annotation.NodePoolMinSize)), fmt.Sprintf("%d", clusterReplicas)))
+			patches = append(patches, mutator.PatchReplace(fmt.Sprintf("/metadata/annotations/%s", mutator.EscapeJSONPatchString(annotation.NodePoolMinSize)), fmt.Sprintf("%d", clusterReplicas)))
 			currentMin = clusterReplicas
 		} else {
 			currentMin = int32(min)
@@ -50,13 +41,13 @@ func ensureAutoscalingAnnotations(h *WebhookHandler, machinePool *capiexp.Machin
 	if machinePool.Annotations[annotation.NodePoolMaxSize] == "" {
 		// By default set the max same value as the min.
 		h.Log("level", "debug", "message", fmt.Sprintf("setting MachinePool Annotation %s to %d", annotation.NodePoolMaxSize, currentMin))
-		patches = append(patches, *mutator.PatchAdd(fmt.Sprintf("/metadata/annotations/%s", escapeJSONPatchString(annotation.NodePoolMaxSize)), fmt.Sprintf("%d", currentMin)))
+		patches = append(patches, *mutator.PatchAdd(fmt.Sprintf("/metadata/annotations/%s", mutator.EscapeJSONPatchString(annotation.NodePoolMaxSize)), fmt.Sprintf("%d", currentMin)))
 	} else {
 		// Check current value is valid.
 		max, err := strconv.ParseInt(machinePool.Annotations[annotation.NodePoolMaxSize], 10, 32)
 		if err != nil || int32(max) < currentMin {
 			h.Log("level", "debug", "message", fmt.Sprintf("setting MachinePool Annotation %s to %d", annotation.NodePoolMaxSize, currentMin))
-			patches = append(patches, mutator.PatchReplace(fmt.Sprintf("/metadata/annotations/%s", escapeJSONPatchString(annotation.NodePoolMaxSize)), fmt.Sprintf("%d", currentMin)))
+			patches = append(patches, mutator.PatchReplace(fmt.Sprintf("/metadata/annotations/%s", mutator.EscapeJSONPatchString(annotation.NodePoolMaxSize)), fmt.Sprintf("%d", currentMin)))
 		}
 	}
 
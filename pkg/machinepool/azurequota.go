@@ -0,0 +1,56 @@
+package machinepool
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
+)
+
+// checkAzureQuota checks that the subscription has enough vCPU quota left in
+// the location for the given number of additional nodes of the MachinePool.
+// It is not checked when no Azure quota factory is configured.
+func (h *WebhookHandler) checkAzureQuota(ctx context.Context, mp *capiexp.MachinePool, nodes int) error {
+	if h.azureQuotaFactory == nil || nodes <= 0 {
+		return nil
+	}
+
+	location, vmSize, err := h.getVMSize(ctx, mp)
+	if IsAzureMachinePoolNotFound(err) {
+		// Already reported by the availability zones check.
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	vmcaps, err := h.vmcapsFactory.GetClient(ctx, h.ctrlClient, mp.ObjectMeta)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	cpus, err := vmcaps.CPUs(ctx, location, vmSize)
+	if vmcapabilities.IsSkuNotFoundError(err) {
+		// Unknown VM sizes are rejected by the AzureMachinePool validation.
+		return nil
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+	family, err := vmcaps.Family(ctx, location, vmSize)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	vcpuQuota, err := h.azureQuotaFactory.GetClient(ctx, h.ctrlClient, mp.ObjectMeta)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	err = vcpuQuota.CheckVCPUs(ctx, location, family, nodes*cpus)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package machinepool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/giantswarm/apiextensions/v6/pkg/annotation"
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	securityv1alpha1 "github.com/giantswarm/organization-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/azurequota"
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/machinepool"
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+func TestMachinePoolAzureQuota(t *testing.T) {
+	type testCase struct {
+		name        string
+		oldNodePool *capiexp.MachinePool
+		newNodePool *capiexp.MachinePool
+		// usages are the Azure vCPU usages, nil when the check is disabled.
+		usages       []azurequota.Usage
+		errorMatcher func(err error) bool
+	}
+
+	// 40 vCPUs are left in the family, i.e. 10 nodes of 4 vCPUs.
+	usages := []azurequota.Usage{
+		{Name: azurequota.UsageRegionalVCPUs, Current: 60, Limit: 200},
+		{Name: "standardDSv3Family", Current: 8, Limit: 48},
+	}
+
+	testCases := []testCase{
+		{
+			name:         "case 0: create within the quota",
+			newNodePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.Replicas(5)),
+			usages:       usages,
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 1: create with an autoscaler max size exceeding the quota",
+			newNodePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.Annotation(annotation.NodePoolMaxSize, "11")),
+			usages:       usages,
+			errorMatcher: azurequota.IsInsufficientQuota,
+		},
+		{
+			name:         "case 2: create exceeding the quota with the check disabled",
+			newNodePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.Replicas(11)),
+			usages:       nil,
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 3: scale up within the quota",
+			oldNodePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.Replicas(5)),
+			newNodePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.Replicas(15)),
+			usages:       usages,
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 4: scale up exceeding the quota",
+			oldNodePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.Replicas(5)),
+			newNodePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.Replicas(16)),
+			usages:       usages,
+			errorMatcher: azurequota.IsInsufficientQuota,
+		},
+		{
+			name:         "case 5: scale down of a node pool larger than the quota",
+			oldNodePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.Replicas(20)),
+			newNodePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.Replicas(15)),
+			usages:       usages,
+			errorMatcher: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+
+			// Create a new logger that is used by all admitters.
+			var newLogger micrologger.Logger
+			{
+				newLogger, err = micrologger.New(micrologger.Config{})
+				if err != nil {
+					panic(microerror.JSON(err))
+				}
+			}
+			stubbedSKUs := map[string]compute.ResourceSku{
+				"Standard_D4s_v3": {
+					Name:   to.StringPtr("Standard_D4s_v3"),
+					Family: to.StringPtr("standardDSv3Family"),
+					Capabilities: &[]compute.ResourceSkuCapabilities{
+						{
+							Name:  to.StringPtr("vCPUs"),
+							Value: to.StringPtr("4"),
+						},
+						{
+							Name:  to.StringPtr("MemoryGB"),
+							Value: to.StringPtr("16"),
+						},
+					},
+					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+						{
+							Location: to.StringPtr("westeurope"),
+							Zones:    &[]string{"1", "2", "3"},
+						},
+					},
+				},
+			}
+			vmcaps := unittest.NewVMCapsStubFactory(stubbedSKUs, newLogger)
+
+			var azureQuota azurequota.Factory
+			if tc.usages != nil {
+				azureQuota = unittest.NewAzureQuotaStubFactory(tc.usages, newLogger)
+			}
+
+			ctx := context.Background()
+			fakeK8sClient := unittest.FakeK8sClient()
+			ctrlClient := fakeK8sClient.CtrlClient()
+
+			amp := &capzexp.AzureMachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      machinePoolName,
+					Namespace: machinePoolNamespace,
+				},
+				Spec: capzexp.AzureMachinePoolSpec{
+					Location: "westeurope",
+					Template: capzexp.AzureMachinePoolMachineTemplate{
+						VMSize: "Standard_D4s_v3",
+					},
+				},
+			}
+			err = ctrlClient.Create(ctx, amp)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			organization := &securityv1alpha1.Organization{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "giantswarm",
+				},
+			}
+			err = ctrlClient.Create(ctx, organization)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cluster := &capi.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "ab123",
+					Labels: map[string]string{
+						label.Cluster:      "ab123",
+						label.Organization: "giantswarm",
+					},
+				},
+			}
+			err = ctrlClient.Create(ctx, cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler, err := NewWebhookHandler(WebhookHandlerConfig{
+				AzureQuotaFactory: azureQuota,
+				CtrlClient:        ctrlClient,
+				Decoder:           unittest.NewFakeDecoder(),
+				Logger:            newLogger,
+				VMcapsFactory:     vmcaps,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.oldNodePool == nil {
+				err = handler.OnCreateValidate(ctx, tc.newNodePool)
+			} else {
+				err = handler.OnUpdateValidate(ctx, tc.oldNodePool, tc.newNodePool)
+			}
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
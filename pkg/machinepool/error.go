@@ -2,6 +2,8 @@ package machinepool
 
 import (
 	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
 var azureMachinePoolNotFoundError = &microerror.Error{
@@ -57,3 +59,22 @@ var failureDomainWasChangedError = &microerror.Error{
 func IsFailureDomainWasChangedError(err error) bool {
 	return microerror.Cause(err) == failureDomainWasChangedError
 }
+
+var restrictedFailureDomainError = &microerror.Error{
+	Kind: "restrictedFailureDomainError",
+}
+
+// IsRestrictedFailureDomainError asserts restrictedFailureDomainError.
+func IsRestrictedFailureDomainError(err error) bool {
+	return microerror.Cause(err) == restrictedFailureDomainError
+}
+
+func init() {
+	errors.RegisterValidationErrors(
+		azureMachinePoolNotFoundError,
+		unsupportedFailureDomainError,
+		locationWithNoFailureDomainSupportError,
+		failureDomainWasChangedError,
+		restrictedFailureDomainError,
+	)
+}
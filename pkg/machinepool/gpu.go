@@ -0,0 +1,46 @@
+package machinepool
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
+	"github.com/giantswarm/azure-admission-controller/pkg/generic"
+)
+
+// isGPUNodePool returns true when the VM size of the AzureMachinePool CR
+// related to the MachinePool has GPUs. A missing AzureMachinePool or an
+// unknown VM size are reported by the other checks, so here the node pool
+// just doesn't have GPUs.
+func (h *WebhookHandler) isGPUNodePool(ctx context.Context, mp *capiexp.MachinePool) (bool, error) {
+	location, vmsize, err := h.getVMSize(ctx, mp)
+	if IsAzureMachinePoolNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	vmcaps, err := h.vmcapsFactory.GetClient(ctx, h.ctrlClient, mp.ObjectMeta)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	gpus, err := vmcaps.GPUs(ctx, location, vmsize)
+	if vmcapabilities.IsSkuNotFoundError(err) {
+		return false, nil
+	} else if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return gpus > 0, nil
+}
+
+// hasGPUConventionsChanged returns true when the GPU label or the node taints
+// annotation is changed. Only then the GPU conventions are checked on update,
+// so existing node pools can still be updated.
+func hasGPUConventionsChanged(oldMP *capiexp.MachinePool, newMP *capiexp.MachinePool) bool {
+	return oldMP.GetLabels()[generic.GPULabel] != newMP.GetLabels()[generic.GPULabel] ||
+		oldMP.GetAnnotations()[generic.NodeTaintsAnnotation] != newMP.GetAnnotations()[generic.NodeTaintsAnnotation]
+}
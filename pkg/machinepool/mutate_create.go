@@ -11,7 +11,7 @@ import (
 )
 
 func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{}) ([]mutator.PatchOperation, error) {
-	var result []mutator.PatchOperation
+	var builder mutator.PatchBuilder
 	machinePoolCR, err := key.ToMachinePoolPtr(object)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
@@ -19,9 +19,7 @@ func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{})
 	machinePoolCROriginal := machinePoolCR.DeepCopy()
 
 	autoscalingPatches := ensureAutoscalingAnnotations(h, machinePoolCR)
-	if autoscalingPatches != nil {
-		result = append(result, autoscalingPatches...)
-	}
+	builder.AddAll(autoscalingPatches)
 
 	machinePoolCR.Default()
 	{
@@ -31,7 +29,15 @@ func (h *WebhookHandler) OnCreateMutate(ctx context.Context, object interface{})
 			return []mutator.PatchOperation{}, microerror.Mask(err)
 		}
 
-		result = append(result, capiPatches...)
+		builder.AddDefaults(capiPatches)
+	}
+
+	result, err := builder.Build()
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
+	}
+	for _, conflict := range builder.Conflicts() {
+		h.logger.LogCtx(ctx, "level", "warning", "message", conflict)
 	}
 
 	return result, nil
@@ -206,7 +206,7 @@ func TestMachinePoolCreateMutate(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			vmcapsFactory, err := vmcapabilities.NewFactory(newLogger)
+			vmcapsFactory, err := vmcapabilities.NewFactory(vmcapabilities.FactoryConfig{Logger: newLogger})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -10,9 +10,9 @@ import (
 	"github.com/giantswarm/azure-admission-controller/pkg/mutator"
 )
 
-func (h *WebhookHandler) OnUpdateMutate(_ context.Context, _ interface{}, object interface{}) ([]mutator.PatchOperation, error) {
+func (h *WebhookHandler) OnUpdateMutate(ctx context.Context, _ interface{}, object interface{}) ([]mutator.PatchOperation, error) {
 	var err error
-	var result []mutator.PatchOperation
+	var builder mutator.PatchBuilder
 	machinePoolCR, err := key.ToMachinePoolPtr(object)
 	if err != nil {
 		return []mutator.PatchOperation{}, microerror.Mask(err)
@@ -21,9 +21,7 @@ func (h *WebhookHandler) OnUpdateMutate(_ context.Context, _ interface{}, object
 
 	// Ensure autoscaling annotations are set.
 	patch := ensureAutoscalingAnnotations(h, machinePoolCR)
-	if patch != nil {
-		result = append(result, patch...)
-	}
+	builder.AddAll(patch)
 
 	machinePoolCR.Default()
 	{
@@ -33,7 +31,15 @@ func (h *WebhookHandler) OnUpdateMutate(_ context.Context, _ interface{}, object
 			return []mutator.PatchOperation{}, microerror.Mask(err)
 		}
 
-		result = append(result, capiPatches...)
+		builder.AddDefaults(capiPatches)
+	}
+
+	result, err := builder.Build()
+	if err != nil {
+		return []mutator.PatchOperation{}, microerror.Mask(err)
+	}
+	for _, conflict := range builder.Conflicts() {
+		h.logger.LogCtx(ctx, "level", "warning", "message", conflict)
 	}
 
 	return result, nil
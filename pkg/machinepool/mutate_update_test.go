@@ -260,7 +260,7 @@ func TestMachinePoolUpdateMutate(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			vmcaps, err := vmcapabilities.NewFactory(newLogger)
+			vmcaps, err := vmcapabilities.NewFactory(vmcapabilities.FactoryConfig{Logger: newLogger})
 			if err != nil {
 				t.Fatal(microerror.JSON(err))
 			}
@@ -0,0 +1,22 @@
+package machinepool
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v6/pkg/label"
+	"github.com/giantswarm/microerror"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/quota"
+)
+
+// checkQuota checks that the node pools of the organization stay within its
+// quota with the MachinePool scaled up to its maximum size.
+func (h *WebhookHandler) checkQuota(ctx context.Context, mp *capiexp.MachinePool) error {
+	err := h.quotaChecker.Check(ctx, mp.GetLabels()[label.Organization], quota.Pending{MachinePool: mp})
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
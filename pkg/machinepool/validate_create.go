@@ -2,46 +2,108 @@ package machinepool
 
 import (
 	"context"
+	"strings"
 
 	"github.com/giantswarm/apiextensions/v6/pkg/apis/capzexp/v1alpha3"
 	"github.com/giantswarm/microerror"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	capzexp "sigs.k8s.io/cluster-api-provider-azure/exp/api/v1beta1"
 	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	internalerrors "github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/quota"
+	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 	"github.com/giantswarm/azure-admission-controller/pkg/key"
 )
 
+var (
+	conditionsPath     = field.NewPath("status", "conditions")
+	failureDomainsPath = field.NewPath("spec", "failureDomains")
+	replicasPath       = field.NewPath("spec", "replicas")
+)
+
 func (h *WebhookHandler) OnCreateValidate(ctx context.Context, object interface{}) error {
 	machinePoolNewCR, err := key.ToMachinePoolPtr(object)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	err = machinePoolNewCR.ValidateCreate()
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors internalerrors.ValidationErrors
+
+	validationErrors.Add(nil, machinePoolNewCR.ValidateCreate())
+	validationErrors.Add(generic.OrganizationLabelPath, generic.ValidateOrganizationLabelMatchesCluster(ctx, h.ctrlClient, machinePoolNewCR))
+	validationErrors.Add(failureDomainsPath, enforcement.Apply(ctx, enforcement.MachinePoolFailureDomains, h.checkAvailabilityZones(ctx, machinePoolNewCR)))
+	validationErrors.Add(replicasPath, enforcement.Apply(ctx, enforcement.MachinePoolQuota, h.checkQuota(ctx, machinePoolNewCR)))
+	validationErrors.Add(replicasPath, enforcement.Apply(ctx, enforcement.MachinePoolAzureQuota, h.checkAzureQuota(ctx, machinePoolNewCR, quota.MaxNodes(machinePoolNewCR))))
+
+	gpu, err := h.isGPUNodePool(ctx, machinePoolNewCR)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if gpu {
+		validationErrors.Add(generic.GPULabelPath, enforcement.Apply(ctx, enforcement.MachinePoolGPU, generic.ValidateGPULabel(machinePoolNewCR)))
+		validationErrors.Add(generic.NodeTaintsAnnotationPath, enforcement.Apply(ctx, enforcement.MachinePoolGPU, generic.ValidateGPUTaint(machinePoolNewCR)))
+	}
+
+	return microerror.Mask(validationErrors.Err())
+}
+
+func (h *WebhookHandler) checkAvailabilityZones(ctx context.Context, mp *capiexp.MachinePool) error {
+	// Get the AzureMachinePool CR related to this MachinePool (we need it to get the VM type).
+	location, vmsize, err := h.getVMSize(ctx, mp)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	err = generic.ValidateOrganizationLabelMatchesCluster(ctx, h.ctrlClient, machinePoolNewCR)
+	vmcaps, err := h.vmcapsFactory.GetClient(ctx, h.ctrlClient, mp.ObjectMeta)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
-	err = h.checkAvailabilityZones(ctx, machinePoolNewCR)
+	restrictions, err := vmcaps.Restrictions(ctx, location, vmsize)
+	if vmcapabilities.IsSkuNotFoundError(err) {
+		// Unknown VM sizes are rejected by the AzureMachinePool validation,
+		// here they just don't support any availability zone.
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if restrictions.Location {
+		return microerror.Maskf(restrictedFailureDomainError, "The Machine Pool VM type %s is not available for the cluster's subscription in %s (%s)", vmsize, location, strings.Join(restrictions.Reasons, ", "))
+	}
+
+	for _, zone := range mp.Spec.FailureDomains {
+		if restrictions.IsZoneRestricted(zone) {
+			return microerror.Maskf(restrictedFailureDomainError, "You requested the Machine Pool with type %s to be placed in the following FailureDomains (aka Availability zones): %v but the VM type is restricted for the cluster's subscription in %v in %s (%s)", vmsize, mp.Spec.FailureDomains, restrictions.Zones, location, strings.Join(restrictions.Reasons, ", "))
+		}
+	}
+
+	supportedZones, err := vmcaps.SupportedAZs(ctx, location, vmsize)
 	if err != nil {
 		return microerror.Mask(err)
 	}
 
+	for _, zone := range mp.Spec.FailureDomains {
+		if !inSlice(zone, supportedZones) {
+			// Found one unsupported availability zone requested.
+			return microerror.Maskf(unsupportedFailureDomainError, "You requested the Machine Pool with type %s to be placed in the following FailureDomains (aka Availability zones): %v but the VM type only supports %v in %s", vmsize, mp.Spec.FailureDomains, supportedZones, location)
+		}
+	}
+
 	return nil
 }
 
-func (h *WebhookHandler) checkAvailabilityZones(ctx context.Context, mp *capiexp.MachinePool) error {
-	// Get the AzureMachinePool CR related to this MachinePool (we need it to get the VM type).
+// getVMSize returns the location and VM size of the AzureMachinePool CR related
+// to the MachinePool.
+func (h *WebhookHandler) getVMSize(ctx context.Context, mp *capiexp.MachinePool) (string, string, error) {
 	if mp.Spec.Template.Spec.InfrastructureRef.Namespace == "" || mp.Spec.Template.Spec.InfrastructureRef.Name == "" {
-		return microerror.Maskf(azureMachinePoolNotFoundError, "MachinePool's InfrastructureRef has to be set")
+		return "", "", microerror.Maskf(azureMachinePoolNotFoundError, "MachinePool's InfrastructureRef has to be set")
 	}
 
 	var location string
@@ -53,7 +115,7 @@ func (h *WebhookHandler) checkAvailabilityZones(ctx context.Context, mp *capiexp
 		if errors.IsNotFound(err) {
 			// Did not find, we fallback to the exp AMP.
 		} else if err != nil {
-			return microerror.Mask(err)
+			return "", "", microerror.Mask(err)
 		} else {
 			location = amp.Spec.Location
 			vmsize = amp.Spec.Template.VMSize
@@ -65,33 +127,16 @@ func (h *WebhookHandler) checkAvailabilityZones(ctx context.Context, mp *capiexp
 		amp := v1alpha3.AzureMachinePool{}
 		err := h.ctrlClient.Get(ctx, client.ObjectKey{Namespace: mp.Spec.Template.Spec.InfrastructureRef.Namespace, Name: mp.Spec.Template.Spec.InfrastructureRef.Name}, &amp)
 		if errors.IsNotFound(err) {
-			return microerror.Maskf(azureMachinePoolNotFoundError, "AzureMachinePool has to be created before the related MachinePool")
+			return "", "", microerror.Maskf(azureMachinePoolNotFoundError, "AzureMachinePool has to be created before the related MachinePool")
 		} else if err != nil {
-			return microerror.Mask(err)
+			return "", "", microerror.Mask(err)
 		}
 
 		location = amp.Spec.Location
 		vmsize = amp.Spec.Template.VMSize
 	}
 
-	vmcaps, err := h.vmcapsFactory.GetClient(ctx, h.ctrlClient, mp.ObjectMeta)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	supportedZones, err := vmcaps.SupportedAZs(ctx, location, vmsize)
-	if err != nil {
-		return microerror.Mask(err)
-	}
-
-	for _, zone := range mp.Spec.FailureDomains {
-		if !inSlice(zone, supportedZones) {
-			// Found one unsupported availability zone requested.
-			return microerror.Maskf(unsupportedFailureDomainError, "You requested the Machine Pool with type %s to be placed in the following FailureDomains (aka Availability zones): %v but the VM type only supports %v in %s", vmsize, mp.Spec.FailureDomains, supportedZones, location)
-		}
-	}
-
-	return nil
+	return location, vmsize, nil
 }
 
 func inSlice(needle string, haystack []string) bool {
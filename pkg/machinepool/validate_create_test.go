@@ -76,6 +76,50 @@ func TestMachinePoolCreateValidate(t *testing.T) {
 			vmType:       "",
 			errorMatcher: generic.IsNodepoolOrgDoesNotMatchClusterOrg,
 		},
+		{
+			name:         "case 7: GPU instance type without GPU label and taint",
+			machinePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName)),
+			vmType:       "Standard_NC6s_v3",
+			errorMatcher: generic.IsGPUNodePoolConventionError,
+		},
+		{
+			name: "case 8: GPU instance type with GPU label and taint",
+			machinePool: builder.BuildMachinePool(
+				builder.AzureMachinePool(machinePoolName),
+				builder.Label(generic.GPULabel, "true"),
+				builder.Annotation(generic.NodeTaintsAnnotation, "dedicated=ml:NoExecute, nvidia.com/gpu=present:NoSchedule"),
+			),
+			vmType:       "Standard_NC6s_v3",
+			errorMatcher: nil,
+		},
+		{
+			name: "case 9: GPU instance type with GPU taint of the wrong effect",
+			machinePool: builder.BuildMachinePool(
+				builder.AzureMachinePool(machinePoolName),
+				builder.Label(generic.GPULabel, "true"),
+				builder.Annotation(generic.NodeTaintsAnnotation, "nvidia.com/gpu=present:PreferNoSchedule"),
+			),
+			vmType:       "Standard_NC6s_v3",
+			errorMatcher: generic.IsGPUNodePoolConventionError,
+		},
+		{
+			name:         "case 10: non GPU instance type without GPU label and taint",
+			machinePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName)),
+			vmType:       "Standard_A2_v2",
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 11: instance type restricted for the subscription in [3], requested [2,3]",
+			machinePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.FailureDomains([]string{"2", "3"})),
+			vmType:       "Standard_D4s_v3",
+			errorMatcher: IsRestrictedFailureDomainError,
+		},
+		{
+			name:         "case 12: instance type restricted for the subscription in [3], requested [1,2]",
+			machinePool:  builder.BuildMachinePool(builder.AzureMachinePool(machinePoolName), builder.FailureDomains([]string{"1", "2"})),
+			vmType:       "Standard_D4s_v3",
+			errorMatcher: nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -160,6 +204,59 @@ func TestMachinePoolCreateValidate(t *testing.T) {
 						},
 					},
 				},
+				"Standard_D4s_v3": {
+					Name: to.StringPtr("Standard_D4s_v3"),
+					Capabilities: &[]compute.ResourceSkuCapabilities{
+						{
+							Name:  to.StringPtr("vCPUs"),
+							Value: to.StringPtr("4"),
+						},
+						{
+							Name:  to.StringPtr("MemoryGB"),
+							Value: to.StringPtr("16"),
+						},
+					},
+					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+						{
+							Location: to.StringPtr("westeurope"),
+							Zones:    &[]string{"1", "2", "3"},
+						},
+					},
+					Restrictions: &[]compute.ResourceSkuRestrictions{
+						{
+							Type:       compute.Zone,
+							Values:     &[]string{"westeurope"},
+							ReasonCode: compute.NotAvailableForSubscription,
+							RestrictionInfo: &compute.ResourceSkuRestrictionInfo{
+								Locations: &[]string{"westeurope"},
+								Zones:     &[]string{"3"},
+							},
+						},
+					},
+				},
+				"Standard_NC6s_v3": {
+					Name: to.StringPtr("Standard_NC6s_v3"),
+					Capabilities: &[]compute.ResourceSkuCapabilities{
+						{
+							Name:  to.StringPtr("vCPUs"),
+							Value: to.StringPtr("6"),
+						},
+						{
+							Name:  to.StringPtr("MemoryGB"),
+							Value: to.StringPtr("112"),
+						},
+						{
+							Name:  to.StringPtr("GPUs"),
+							Value: to.StringPtr("1"),
+						},
+					},
+					LocationInfo: &[]compute.ResourceSkuLocationInfo{
+						{
+							Location: to.StringPtr("westeurope"),
+							Zones:    &[]string{"1", "2", "3"},
+						},
+					},
+				},
 			}
 			vmcaps := unittest.NewVMCapsStubFactory(stubbedSKUs, newLogger)
 
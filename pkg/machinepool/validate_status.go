@@ -0,0 +1,36 @@
+package machinepool
+
+import (
+	"context"
+
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/conditions"
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/key"
+)
+
+// OnStatusUpdateValidate validates the conditions of the MachinePool, which
+// are only changed through the status subresource.
+func (h *WebhookHandler) OnStatusUpdateValidate(ctx context.Context, oldObject interface{}, object interface{}) error {
+	machinePoolNewCR, err := key.ToMachinePoolPtr(object)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+	if !machinePoolNewCR.GetDeletionTimestamp().IsZero() {
+		h.logger.LogCtx(ctx, "level", "debug", "message", "The object is being deleted so we don't validate it")
+		return nil
+	}
+
+	machinePoolOldCR, err := key.ToMachinePoolPtr(oldObject)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var validationErrors errors.ValidationErrors
+
+	validationErrors.Add(conditionsPath, enforcement.Apply(ctx, enforcement.MachinePoolConditions, conditions.ValidateMachinePoolConditions(machinePoolOldCR, machinePoolNewCR)))
+
+	return microerror.Mask(validationErrors.Err())
+}
@@ -0,0 +1,150 @@
+package machinepool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger"
+	corev1 "k8s.io/api/core/v1"
+	capi "sigs.k8s.io/cluster-api/api/v1beta1"
+	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	builder "github.com/giantswarm/azure-admission-controller/internal/test/machinepool"
+	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
+	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
+)
+
+func TestMachinePoolStatusUpdateValidate(t *testing.T) {
+	type testCase struct {
+		name         string
+		oldNodePool  *capiexp.MachinePool
+		newNodePool  *capiexp.MachinePool
+		errorMatcher func(err error) bool
+	}
+
+	testCases := []testCase{
+		{
+			name:         "case 0: Ready condition set to False with a reason",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse, Reason: capiexp.WaitingForReplicasReadyReason}})),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 1: Ready condition set to False without a reason",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse}})),
+			errorMatcher: errors.IsInvalidConditionModification,
+		},
+		{
+			name:         "case 2: ReplicasReady condition removed",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildMachinePool(),
+			errorMatcher: errors.IsInvalidConditionModification,
+		},
+		{
+			name:         "case 3: InfrastructureReady condition set to an invalid status",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.InfrastructureReadyCondition, Status: corev1.ConditionUnknown}})),
+			newNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.InfrastructureReadyCondition, Status: "Maybe"}})),
+			errorMatcher: errors.IsInvalidConditionStatus,
+		},
+		{
+			name:         "case 4: unchanged Ready condition without a reason",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse}})),
+			newNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.ReadyCondition, Status: corev1.ConditionFalse}})),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 5: BootstrapReady condition set to False with a reason",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.BootstrapReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.BootstrapReadyCondition, Status: corev1.ConditionFalse, Reason: capi.WaitingForDataSecretFallbackReason}})),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 6: BootstrapReady condition set to False without a reason",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.BootstrapReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.BootstrapReadyCondition, Status: corev1.ConditionFalse}})),
+			errorMatcher: errors.IsInvalidConditionModification,
+		},
+		{
+			name:         "case 7: BootstrapReady condition removed",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.BootstrapReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildMachinePool(),
+			errorMatcher: errors.IsInvalidConditionModification,
+		},
+		{
+			name:         "case 8: InfrastructureReady condition set to False without a reason",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.InfrastructureReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.InfrastructureReadyCondition, Status: corev1.ConditionFalse}})),
+			errorMatcher: errors.IsInvalidConditionModification,
+		},
+		{
+			name:         "case 9: InfrastructureReady condition set to True",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.InfrastructureReadyCondition, Status: corev1.ConditionFalse, Reason: capiexp.WaitingForReplicasReadyReason}})),
+			newNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capi.InfrastructureReadyCondition, Status: corev1.ConditionTrue}})),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 10: ReplicasReady condition set to False with a reason",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionFalse, Reason: capiexp.WaitingForReplicasReadyReason}})),
+			errorMatcher: nil,
+		},
+		{
+			name:         "case 11: ReplicasReady condition set to an invalid status",
+			oldNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capiexp.ReplicasReadyCondition, Status: corev1.ConditionTrue}})),
+			newNodePool:  builder.BuildMachinePool(builder.Conditions(capi.Conditions{{Type: capiexp.ReplicasReadyCondition, Status: "Maybe"}})),
+			errorMatcher: errors.IsInvalidConditionStatus,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var err error
+
+			// Create a new logger that is used by all admitters.
+			var newLogger micrologger.Logger
+			{
+				newLogger, err = micrologger.New(micrologger.Config{})
+				if err != nil {
+					panic(microerror.JSON(err))
+				}
+			}
+
+			ctx := context.Background()
+			fakeK8sClient := unittest.FakeK8sClient()
+			ctrlClient := fakeK8sClient.CtrlClient()
+
+			vmcaps, err := vmcapabilities.NewFactory(vmcapabilities.FactoryConfig{Logger: newLogger})
+			if err != nil {
+				t.Fatal(microerror.JSON(err))
+			}
+
+			handler, err := NewWebhookHandler(WebhookHandlerConfig{
+				CtrlClient:    ctrlClient,
+				Decoder:       unittest.NewFakeDecoder(),
+				Logger:        newLogger,
+				VMcapsFactory: vmcaps,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Run validating webhook handler on MachinePool status update.
+			err = handler.OnStatusUpdateValidate(ctx, tc.oldNodePool, tc.newNodePool)
+
+			// Check if the error is the expected one.
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// fall through
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("expected %#v got %#v", nil, err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected %#v got %#v", "error", nil)
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+		})
+	}
+}
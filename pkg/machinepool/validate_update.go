@@ -7,6 +7,9 @@ import (
 	"github.com/giantswarm/microerror"
 	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/quota"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 	"github.com/giantswarm/azure-admission-controller/pkg/key"
 )
@@ -26,22 +29,33 @@ func (h *WebhookHandler) OnUpdateValidate(ctx context.Context, oldObject interfa
 		return microerror.Mask(err)
 	}
 
-	err = machinePoolNewCR.ValidateUpdate(machinePoolOldCR)
-	if err != nil {
-		return microerror.Mask(err)
-	}
+	// All checks below are independent, so we run all of them and report
+	// every violation at once.
+	var validationErrors errors.ValidationErrors
 
-	err = generic.ValidateOrganizationLabelUnchanged(machinePoolOldCR, machinePoolNewCR)
-	if err != nil {
-		return microerror.Mask(err)
+	validationErrors.Add(nil, machinePoolNewCR.ValidateUpdate(machinePoolOldCR))
+	validationErrors.Add(generic.OrganizationLabelPath, generic.ValidateOrganizationLabelUnchanged(machinePoolOldCR, machinePoolNewCR))
+	validationErrors.Add(failureDomainsPath, enforcement.Apply(ctx, enforcement.MachinePoolFailureDomains, checkAvailabilityZonesUnchanged(ctx, machinePoolOldCR, machinePoolNewCR)))
+
+	// Only scaling up is checked, so that organizations over their quota can
+	// still scale down and update their node pools otherwise.
+	if quota.MaxNodesIncreased(machinePoolOldCR, machinePoolNewCR) {
+		validationErrors.Add(replicasPath, enforcement.Apply(ctx, enforcement.MachinePoolQuota, h.checkQuota(ctx, machinePoolNewCR)))
+		validationErrors.Add(replicasPath, enforcement.Apply(ctx, enforcement.MachinePoolAzureQuota, h.checkAzureQuota(ctx, machinePoolNewCR, quota.MaxNodes(machinePoolNewCR)-quota.MaxNodes(machinePoolOldCR))))
 	}
 
-	err = checkAvailabilityZonesUnchanged(ctx, machinePoolOldCR, machinePoolNewCR)
-	if err != nil {
-		return microerror.Mask(err)
+	if hasGPUConventionsChanged(machinePoolOldCR, machinePoolNewCR) {
+		gpu, err := h.isGPUNodePool(ctx, machinePoolNewCR)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if gpu {
+			validationErrors.Add(generic.GPULabelPath, enforcement.Apply(ctx, enforcement.MachinePoolGPU, generic.ValidateGPULabel(machinePoolNewCR)))
+			validationErrors.Add(generic.NodeTaintsAnnotationPath, enforcement.Apply(ctx, enforcement.MachinePoolGPU, generic.ValidateGPUTaint(machinePoolNewCR)))
+		}
 	}
 
-	return nil
+	return microerror.Mask(validationErrors.Err())
 }
 
 func checkAvailabilityZonesUnchanged(_ context.Context, oldMP *capiexp.MachinePool, newMP *capiexp.MachinePool) error {
@@ -59,7 +59,7 @@ func TestMachinePoolUpdateValidate(t *testing.T) {
 			fakeK8sClient := unittest.FakeK8sClient()
 			ctrlClient := fakeK8sClient.CtrlClient()
 
-			vmcaps, err := vmcapabilities.NewFactory(newLogger)
+			vmcaps, err := vmcapabilities.NewFactory(vmcapabilities.FactoryConfig{Logger: newLogger})
 			if err != nil {
 				t.Fatal(microerror.JSON(err))
 			}
@@ -8,21 +8,32 @@ import (
 	capiexp "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/azure-admission-controller/internal/azurequota"
 	"github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/internal/quota"
 	"github.com/giantswarm/azure-admission-controller/internal/vmcapabilities"
 )
 
 type WebhookHandler struct {
-	ctrlClient    client.Client
-	decoder       runtime.Decoder
-	logger        micrologger.Logger
-	vmcapsFactory vmcapabilities.Factory
+	azureQuotaFactory azurequota.Factory
+	ctrlClient        client.Client
+	decoder           runtime.Decoder
+	logger            micrologger.Logger
+	quotaChecker      *quota.Checker
+	vmcapsFactory     vmcapabilities.Factory
 }
 
 type WebhookHandlerConfig struct {
-	CtrlClient    client.Client
-	Decoder       runtime.Decoder
-	Logger        micrologger.Logger
+	// AzureQuotaFactory creates the clients checking the vCPU quota of the
+	// Azure subscriptions. It is optional, when nil the Azure quota is not
+	// checked.
+	AzureQuotaFactory azurequota.Factory
+	CtrlClient        client.Client
+	Decoder           runtime.Decoder
+	Logger            micrologger.Logger
+	// QuotaChecker checks the node pools against the organization quotas. It
+	// is optional, when nil no quota is enforced.
+	QuotaChecker  *quota.Checker
 	VMcapsFactory vmcapabilities.Factory
 }
 
@@ -41,10 +52,12 @@ func NewWebhookHandler(config WebhookHandlerConfig) (*WebhookHandler, error) {
 	}
 
 	handler := &WebhookHandler{
-		ctrlClient:    config.CtrlClient,
-		decoder:       config.Decoder,
-		logger:        config.Logger,
-		vmcapsFactory: config.VMcapsFactory,
+		azureQuotaFactory: config.AzureQuotaFactory,
+		ctrlClient:        config.CtrlClient,
+		decoder:           config.Decoder,
+		logger:            config.Logger,
+		quotaChecker:      config.QuotaChecker,
+		vmcapsFactory:     config.VMcapsFactory,
 	}
 
 	return handler, nil
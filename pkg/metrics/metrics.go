@@ -0,0 +1,286 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	internalerrors "github.com/giantswarm/azure-admission-controller/internal/errors"
+)
+
+const (
+	namespace = "azure_admission_controller"
+
+	// WebhookMutate and WebhookValidate are the values of the webhook label.
+	WebhookMutate   = "mutate"
+	WebhookValidate = "validate"
+
+	// OperationCreate, OperationUpdate, OperationUpdateStatus and
+	// OperationDelete are the values of the operation label.
+	OperationCreate       = "create"
+	OperationUpdate       = "update"
+	OperationUpdateStatus = "update_status"
+	OperationDelete       = "delete"
+
+	// CredentialsSourceCAPZ and CredentialsSourceLegacy are the values of the
+	// source label of the credentials lookup metric.
+	CredentialsSourceCAPZ   = "capz"
+	CredentialsSourceLegacy = "legacy"
+
+	// CacheHit and CacheMiss are the values of the result label of the
+	// credentials cache metric.
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+
+	ResultAllowed = "allowed"
+	ResultDenied  = "denied"
+	ResultErrored = "errored"
+
+	// KindNone is used as the kind label for allowed requests.
+	KindNone = ""
+	// KindTimeout is used as the kind label when the request context expired
+	// before the webhook was able to handle it.
+	KindTimeout = "timeout"
+	// KindUnknown is used as the kind label for errors that are neither
+	// microerror errors nor Kubernetes API status errors.
+	KindUnknown = "unknown"
+)
+
+var (
+	webhookRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "webhook",
+			Name:      "requests_total",
+			Help:      "Number of admission requests received by the webhooks.",
+		},
+		[]string{"webhook", "resource", "operation"},
+	)
+	webhookResponses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "webhook",
+			Name:      "responses_total",
+			Help:      "Number of admission responses sent by the webhooks, by result and microerror kind.",
+		},
+		[]string{"webhook", "resource", "operation", "result", "kind"},
+	)
+	webhookPatches = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "webhook",
+			Name:      "patches_total",
+			Help:      "Number of JSON patch operations returned by the mutating webhooks.",
+		},
+		[]string{"resource", "operation"},
+	)
+	webhookDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "webhook",
+			Name:      "request_duration_seconds",
+			Help:      "Time spent handling admission requests.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"webhook", "resource", "operation"},
+	)
+
+	azureSKUListDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "azure",
+			Name:      "resource_skus_list_duration_seconds",
+			Help:      "Time spent listing resource SKUs from the Azure API.",
+			Buckets:   []float64{0.25, 0.5, 1, 2.5, 5, 10, 20, 30},
+		},
+		[]string{"location", "result"},
+	)
+	azureSKUCatalogFallbacks = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "azure",
+			Name:      "resource_skus_catalog_fallbacks_total",
+			Help:      "Number of times the resource SKUs were read from the catalog file because the Azure API failed.",
+		},
+		[]string{"location"},
+	)
+
+	checkViolations = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "check",
+			Name:      "violations_total",
+			Help:      "Number of failed checks, by check name and enforcement mode.",
+		},
+		[]string{"check", "mode"},
+	)
+
+	credentialsLookupDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "credentials",
+			Name:      "lookup_duration_seconds",
+			Help:      "Time spent looking up Azure credentials for a cluster.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"source", "result"},
+	)
+	credentialsCacheLookups = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "credentials",
+			Name:      "cache_lookups_total",
+			Help:      "Number of Azure credentials lookups served from the credentials cache, by hit or miss.",
+		},
+		[]string{"result"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(webhookRequests)
+	prometheus.MustRegister(webhookResponses)
+	prometheus.MustRegister(webhookPatches)
+	prometheus.MustRegister(webhookDuration)
+	prometheus.MustRegister(azureSKUListDuration)
+	prometheus.MustRegister(azureSKUCatalogFallbacks)
+	prometheus.MustRegister(checkViolations)
+	prometheus.MustRegister(credentialsLookupDuration)
+	prometheus.MustRegister(credentialsCacheLookups)
+}
+
+// WebhookRequest keeps track of a single admission request. It must be
+// created with NewWebhookRequest when the request is received, and finished
+// by calling Allowed, Denied or Errored.
+type WebhookRequest struct {
+	webhook   string
+	resource  string
+	operation string
+	start     time.Time
+}
+
+func NewWebhookRequest(webhook, resource, operation string) *WebhookRequest {
+	webhookRequests.WithLabelValues(webhook, resource, operation).Inc()
+
+	return &WebhookRequest{
+		webhook:   webhook,
+		resource:  resource,
+		operation: operation,
+		start:     time.Now(),
+	}
+}
+
+// Allowed records an allowed request together with the number of patch
+// operations that were returned for it.
+func (r *WebhookRequest) Allowed(patches int) {
+	if r.webhook == WebhookMutate {
+		webhookPatches.WithLabelValues(r.resource, r.operation).Add(float64(patches))
+	}
+
+	r.finish(ResultAllowed, KindNone)
+}
+
+// Denied records a request that was rejected by the webhook handler.
+func (r *WebhookRequest) Denied(err error) {
+	r.finish(resultFromError(err), kindFromError(err))
+}
+
+// Errored records a request that could not be handled at all, e.g. because
+// the admission review could not be parsed. err may be nil when there is no
+// error value describing the failure.
+func (r *WebhookRequest) Errored(err error) {
+	r.finish(ResultErrored, kindFromError(err))
+}
+
+func (r *WebhookRequest) finish(result, kind string) {
+	webhookResponses.WithLabelValues(r.webhook, r.resource, r.operation, result, kind).Inc()
+	webhookDuration.WithLabelValues(r.webhook, r.resource, r.operation).Observe(time.Since(r.start).Seconds())
+}
+
+// CheckViolation records a failed check in the given enforcement mode.
+func CheckViolation(check, mode string) {
+	checkViolations.WithLabelValues(check, mode).Inc()
+}
+
+// ObserveAzureSKUList records the duration of listing resource SKUs from the
+// Azure API for the given location.
+func ObserveAzureSKUList(location string, start time.Time, err error) {
+	azureSKUListDuration.WithLabelValues(location, resultFromCall(err)).Observe(time.Since(start).Seconds())
+}
+
+// SKUCatalogFallback records that the resource SKUs of the given location
+// were read from the catalog file because the Azure API failed.
+func SKUCatalogFallback(location string) {
+	azureSKUCatalogFallbacks.WithLabelValues(location).Inc()
+}
+
+// ObserveCredentialsLookup records the duration of looking up Azure
+// credentials from the given source, see CredentialsSourceCAPZ and
+// CredentialsSourceLegacy.
+func ObserveCredentialsLookup(source string, start time.Time, err error) {
+	credentialsLookupDuration.WithLabelValues(source, resultFromCall(err)).Observe(time.Since(start).Seconds())
+}
+
+// CredentialsCacheLookup records whether the Azure credentials of a cluster
+// were found in the credentials cache.
+func CredentialsCacheLookup(hit bool) {
+	result := CacheMiss
+	if hit {
+		result = CacheHit
+	}
+	credentialsCacheLookups.WithLabelValues(result).Inc()
+}
+
+func resultFromCall(err error) string {
+	if err != nil {
+		return ResultErrored
+	}
+
+	return "success"
+}
+
+// resultFromError tells apart requests denied by a validation (which return
+// validation errors or CAPI Invalid status errors, see
+// internalerrors.IsValidation) from requests that failed because of
+// infrastructure problems like timeouts, failing API calls or broken
+// configuration.
+func resultFromError(err error) string {
+	if isTimeout(err) {
+		return ResultErrored
+	}
+
+	if internalerrors.IsValidation(err) {
+		return ResultDenied
+	}
+
+	return ResultErrored
+}
+
+func kindFromError(err error) string {
+	if err == nil {
+		return KindUnknown
+	}
+
+	if isTimeout(err) {
+		return KindTimeout
+	}
+
+	var microErr *microerror.Error
+	if errors.As(err, &microErr) {
+		return microErr.Kind
+	}
+
+	if reason := apierrors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+
+	return KindUnknown
+}
+
+func isTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/giantswarm/microerror"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	internalerrors "github.com/giantswarm/azure-admission-controller/internal/errors"
+)
+
+var testError = &microerror.Error{
+	Kind: "testError",
+}
+
+var testConfigError = &microerror.Error{
+	Kind: "testConfigError",
+}
+
+func init() {
+	internalerrors.RegisterValidationErrors(testError)
+}
+
+func TestResultAndKindFromError(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            error
+		expectedResult string
+		expectedKind   string
+	}{
+		{
+			name:           "case 0: validation error is denied with its kind",
+			err:            microerror.Maskf(testError, "something is wrong"),
+			expectedResult: ResultDenied,
+			expectedKind:   "testError",
+		},
+		{
+			name:           "case 1: other microerror error is errored with its kind",
+			err:            microerror.Maskf(testConfigError, "something is broken"),
+			expectedResult: ResultErrored,
+			expectedKind:   "testConfigError",
+		},
+		{
+			name: "case 2: kubernetes Invalid error is denied with its reason",
+			err: apierrors.NewInvalid(
+				schema.GroupKind{Group: "testing.x-k8s.io", Kind: "Test"},
+				"test",
+				field.ErrorList{field.Invalid(field.NewPath("spec"), "x", "wrong")},
+			),
+			expectedResult: ResultDenied,
+			expectedKind:   "Invalid",
+		},
+		{
+			name:           "case 3: kubernetes NotFound error is errored with its reason",
+			err:            apierrors.NewNotFound(schema.GroupResource{Resource: "clusters"}, "test"),
+			expectedResult: ResultErrored,
+			expectedKind:   "NotFound",
+		},
+		{
+			name:           "case 4: expired context is errored with timeout kind",
+			err:            microerror.Mask(context.DeadlineExceeded),
+			expectedResult: ResultErrored,
+			expectedKind:   KindTimeout,
+		},
+		{
+			name:           "case 5: unknown error is errored with unknown kind",
+			err:            errors.New("boom"),
+			expectedResult: ResultErrored,
+			expectedKind:   KindUnknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := resultFromError(tc.err)
+			if result != tc.expectedResult {
+				t.Fatalf("expected result %q, got %q", tc.expectedResult, result)
+			}
+
+			kind := kindFromError(tc.err)
+			if kind != tc.expectedKind {
+				t.Fatalf("expected kind %q, got %q", tc.expectedKind, kind)
+			}
+		})
+	}
+}
@@ -23,7 +23,7 @@ func CopyAzureOperatorVersionLabelFromAzureClusterCR(ctx context.Context, ctrlCl
 			return nil, microerror.Maskf(azureOperatorVersionLabelNotFoundError, "Cannot find label %#q in AzureCluster CR. Can't continue.", label.AzureOperatorVersion)
 		}
 
-		return PatchAdd(fmt.Sprintf("/metadata/labels/%s", escapeJSONPatchString(label.AzureOperatorVersion)), azureOperatorVersion), nil
+		return PatchAdd(fmt.Sprintf("/metadata/labels/%s", EscapeJSONPatchString(label.AzureOperatorVersion)), azureOperatorVersion), nil
 	}
 
 	return nil, nil
@@ -45,7 +45,7 @@ func EnsureComponentVersionLabelFromRelease(ctx context.Context, ctrlReader clie
 	}
 
 	if meta.GetLabels()[labelName] != componentVersions[componentName] {
-		return PatchAdd(fmt.Sprintf("/metadata/labels/%s", escapeJSONPatchString(labelName)), componentVersions[componentName]), nil
+		return PatchAdd(fmt.Sprintf("/metadata/labels/%s", EscapeJSONPatchString(labelName)), componentVersions[componentName]), nil
 	}
 
 	return nil, nil
@@ -3,7 +3,6 @@ package mutator
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/giantswarm/apiextensions/v6/pkg/label"
 	"github.com/giantswarm/microerror"
@@ -25,7 +24,7 @@ func EnsureReleaseVersionLabel(ctx context.Context, ctrlClient client.Client, me
 			return nil, microerror.Maskf(releaseLabelNotFoundError, "AzureCluster did not have the %#q label set. Can't continue.", label.ReleaseVersion)
 		}
 
-		return PatchAdd(fmt.Sprintf("/metadata/labels/%s", escapeJSONPatchString(label.ReleaseVersion)), release), nil
+		return PatchAdd(fmt.Sprintf("/metadata/labels/%s", EscapeJSONPatchString(label.ReleaseVersion)), release), nil
 	}
 
 	return nil, nil
@@ -57,11 +56,3 @@ func getLabelValueFromAzureCluster(ctx context.Context, ctrlClient client.Client
 
 	return release, nil
 }
-
-// Ensure the needed escapes are in place. See https://tools.ietf.org/html/rfc6901#section-3 .
-func escapeJSONPatchString(input string) string {
-	input = strings.ReplaceAll(input, "~", "~0")
-	input = strings.ReplaceAll(input, "/", "~1")
-
-	return input
-}
@@ -2,6 +2,8 @@ package mutator
 
 import (
 	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
 var invalidConfigError = &microerror.Error{
@@ -48,3 +50,30 @@ var releaseLabelNotFoundError = &microerror.Error{
 func IsReleaseLabelNotFoundError(err error) bool {
 	return microerror.Cause(err) == releaseLabelNotFoundError
 }
+
+var conflictingPatchError = &microerror.Error{
+	Kind: "conflictingPatchError",
+}
+
+// IsConflictingPatch asserts conflictingPatchError.
+func IsConflictingPatch(err error) bool {
+	return microerror.Cause(err) == conflictingPatchError
+}
+
+var invalidPatchError = &microerror.Error{
+	Kind: "invalidPatchError",
+}
+
+// IsInvalidPatch asserts invalidPatchError.
+func IsInvalidPatch(err error) bool {
+	return microerror.Cause(err) == invalidPatchError
+}
+
+func init() {
+	errors.RegisterValidationErrors(
+		azureOperatorVersionLabelNotFoundError,
+		clusterLabelNotFoundError,
+		componentNotFoundInReleaseError,
+		releaseLabelNotFoundError,
+	)
+}
@@ -9,19 +9,20 @@ import (
 	"net/http"
 
 	"github.com/giantswarm/microerror"
-	"k8s.io/api/admission/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/giantswarm/azure-admission-controller/pkg/admission"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 )
 
 type Mutator interface {
 	Log(keyVals ...interface{})
-	Mutate(ctx context.Context, review *v1beta1.AdmissionRequest) ([]PatchOperation, error)
+	Mutate(ctx context.Context, review *admissionv1.AdmissionRequest) ([]PatchOperation, error)
 	Resource() string
 }
 
@@ -47,8 +48,8 @@ func Handler(mutator Mutator) http.HandlerFunc {
 			return
 		}
 
-		review := v1beta1.AdmissionReview{}
-		if _, _, err := Deserializer.Decode(data, nil, &review); err != nil {
+		review, err := admission.DecodeReview(data)
+		if err != nil {
 			mutator.Log("level", "error", "message", "unable to parse admission review request", "stack", microerror.JSON(err))
 			writer.WriteHeader(http.StatusBadRequest)
 			return
@@ -57,21 +58,21 @@ func Handler(mutator Mutator) http.HandlerFunc {
 
 		patch, err := mutator.Mutate(request.Context(), review.Request)
 		if err != nil {
-			writeResponse(mutator, writer, errorResponse(review.Request.UID, microerror.Mask(err)))
+			writeResponse(mutator, writer, review, errorResponse(review.Request.UID, microerror.Mask(err)))
 			return
 		}
 
 		patchData, err := json.Marshal(patch)
 		if err != nil {
 			mutator.Log("level", "error", "message", fmt.Sprintf("unable to serialize patch for %s", resourceName), "stack", microerror.JSON(err))
-			writeResponse(mutator, writer, errorResponse(review.Request.UID, InternalError))
+			writeResponse(mutator, writer, review, errorResponse(review.Request.UID, InternalError))
 			return
 		}
 
 		mutator.Log("level", "debug", "message", fmt.Sprintf("admitted %s (with %d patches)", resourceName, len(patch)))
 
-		pt := v1beta1.PatchTypeJSONPatch
-		writeResponse(mutator, writer, &v1beta1.AdmissionResponse{
+		pt := admissionv1.PatchTypeJSONPatch
+		writeResponse(mutator, writer, review, &admissionv1.AdmissionResponse{
 			Allowed:   true,
 			UID:       review.Request.UID,
 			Patch:     patchData,
@@ -80,7 +81,7 @@ func Handler(mutator Mutator) http.HandlerFunc {
 	}
 }
 
-func extractName(request *v1beta1.AdmissionRequest) string {
+func extractName(request *admissionv1.AdmissionRequest) string {
 	if request.Name != "" {
 		return request.Name
 	}
@@ -99,14 +100,8 @@ func extractName(request *v1beta1.AdmissionRequest) string {
 	return "<unknown>"
 }
 
-func writeResponse(logger generic.Logger, writer http.ResponseWriter, response *v1beta1.AdmissionResponse) {
-	resp, err := json.Marshal(v1beta1.AdmissionReview{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "AdmissionReview",
-			APIVersion: "admission.k8s.io/v1",
-		},
-		Response: response,
-	})
+func writeResponse(logger generic.Logger, writer http.ResponseWriter, review *admission.Review, response *admissionv1.AdmissionResponse) {
+	resp, err := review.EncodeResponse(response)
 	if err != nil {
 		logger.Log("level", "error", "message", "unable to serialize response", "stack", microerror.JSON(err))
 		writer.WriteHeader(http.StatusInternalServerError)
@@ -116,8 +111,8 @@ func writeResponse(logger generic.Logger, writer http.ResponseWriter, response *
 	}
 }
 
-func errorResponse(uid types.UID, err error) *v1beta1.AdmissionResponse {
-	return &v1beta1.AdmissionResponse{
+func errorResponse(uid types.UID, err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
 		Allowed: false,
 		UID:     uid,
 		Result: &metav1.Status{
@@ -9,13 +9,16 @@ import (
 
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
-	"k8s.io/api/admission/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/azure-admission-controller/pkg/admission"
 	"github.com/giantswarm/azure-admission-controller/pkg/filter"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
+	"github.com/giantswarm/azure-admission-controller/pkg/metrics"
 )
 
 type HttpHandlerFactoryConfig struct {
@@ -53,9 +56,9 @@ func NewHttpHandlerFactory(config HttpHandlerFactoryConfig) (*HttpHandlerFactory
 
 // NewCreateHandler returns a HTTP handler for mutating create requests.
 func (h *HttpHandlerFactory) NewCreateHandler(mutator WebhookCreateHandler) http.HandlerFunc {
-	mutateFunc := func(ctx context.Context, review v1beta1.AdmissionReview) ([]PatchOperation, error) {
+	mutateFunc := func(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
 		// Decode the new CR from the request.
-		object, err := mutator.Decode(review.Request.Object)
+		object, err := mutator.Decode(admissionRequest.Object)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -88,14 +91,14 @@ func (h *HttpHandlerFactory) NewCreateHandler(mutator WebhookCreateHandler) http
 		return patch, nil
 	}
 
-	return h.newHttpHandler(mutator, mutateFunc)
+	return h.newHttpHandler(mutator, metrics.OperationCreate, mutateFunc)
 }
 
 // NewUpdateHandler returns a HTTP handler for mutating update requests.
 func (h *HttpHandlerFactory) NewUpdateHandler(mutator WebhookUpdateHandler) http.HandlerFunc {
-	mutateFunc := func(ctx context.Context, review v1beta1.AdmissionReview) ([]PatchOperation, error) {
+	mutateFunc := func(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) ([]PatchOperation, error) {
 		// Decode the new updated CR from the request.
-		object, err := mutator.Decode(review.Request.Object)
+		object, err := mutator.Decode(admissionRequest.Object)
 		if err != nil {
 			return nil, microerror.Mask(err)
 		}
@@ -119,7 +122,7 @@ func (h *HttpHandlerFactory) NewUpdateHandler(mutator WebhookUpdateHandler) http
 
 		if ok {
 			// Decode the old CR from the request (before the update).
-			oldObject, err := mutator.Decode(review.Request.OldObject)
+			oldObject, err := mutator.Decode(admissionRequest.OldObject)
 			if err != nil {
 				return nil, microerror.Mask(err)
 			}
@@ -134,7 +137,7 @@ func (h *HttpHandlerFactory) NewUpdateHandler(mutator WebhookUpdateHandler) http
 		return patch, nil
 	}
 
-	return h.newHttpHandler(mutator, mutateFunc)
+	return h.newHttpHandler(mutator, metrics.OperationUpdate, mutateFunc)
 }
 
 // newHttpHandler returns a HTTP handler for mutating a request with the specified mutation
@@ -142,10 +145,13 @@ func (h *HttpHandlerFactory) NewUpdateHandler(mutator WebhookUpdateHandler) http
 // This function is basically the same as the existing Handler func, with the only difference that
 // it is now wrapped into the New...Handler funcs above in order to first decode the CR and check
 // if it should be mutated by azure-admission-controller.
-func (h *HttpHandlerFactory) newHttpHandler(webhookHandler WebhookHandlerBase, mutateFunc func(ctx context.Context, review v1beta1.AdmissionReview) ([]PatchOperation, error)) http.HandlerFunc {
+func (h *HttpHandlerFactory) newHttpHandler(webhookHandler WebhookHandlerBase, operation string, mutateFunc func(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) ([]PatchOperation, error)) http.HandlerFunc {
 	return func(writer http.ResponseWriter, request *http.Request) {
+		requestMetrics := metrics.NewWebhookRequest(metrics.WebhookMutate, webhookHandler.Resource(), operation)
+
 		if request.Header.Get("Content-Type") != "application/json" {
 			webhookHandler.Log("level", "error", "message", fmt.Sprintf("invalid content-type: %q", request.Header.Get("Content-Type")))
+			requestMetrics.Errored(nil)
 			writer.WriteHeader(http.StatusBadRequest)
 			return
 		}
@@ -153,40 +159,55 @@ func (h *HttpHandlerFactory) newHttpHandler(webhookHandler WebhookHandlerBase, m
 		data, err := ioutil.ReadAll(request.Body)
 		if err != nil {
 			webhookHandler.Log("level", "error", "message", "unable to read request", "stack", microerror.JSON(err))
+			requestMetrics.Errored(err)
 			writer.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
-		review := v1beta1.AdmissionReview{}
-		if _, _, err := Deserializer.Decode(data, nil, &review); err != nil {
+		review, err := admission.DecodeReview(data)
+		if err != nil {
 			webhookHandler.Log("level", "error", "message", "unable to parse admission review request", "stack", microerror.JSON(err))
+			requestMetrics.Errored(err)
 			writer.WriteHeader(http.StatusBadRequest)
 			return
 		}
 
-		var patch []PatchOperation
-		if review.Request.DryRun != nil && *review.Request.DryRun {
-			webhookHandler.Log("level", "debug", "message", "Dry run is not supported. Request processing stopped.", "stack", microerror.JSON(err))
-		} else {
-			patch, err = mutateFunc(request.Context(), review)
-			if err != nil {
-				writeResponse(webhookHandler, writer, errorResponse(review.Request.UID, microerror.Mask(err)))
-				return
-			}
+		// Dry-run requests go through the whole mutation chain as well, so that
+		// `kubectl apply --dry-run=server` shows the object that would actually
+		// be persisted. This is safe because our webhooks have no side effects.
+		patch, err := mutateFunc(request.Context(), review.Request)
+		if err != nil {
+			requestMetrics.Denied(err)
+			writeResponse(webhookHandler, writer, review, errorResponse(review.Request.UID, microerror.Mask(err)))
+			return
 		}
 
 		resourceName := fmt.Sprintf("%s %s/%s", review.Request.Kind, review.Request.Namespace, extractName(review.Request))
+
+		// Check the patch applies to the object and the result can still be
+		// decoded, so that a broken patch fails the request here instead of
+		// being rejected by the API server without a useful message.
+		err = checkPatch(webhookHandler, review.Request.Object, patch)
+		if err != nil {
+			webhookHandler.Log("level", "error", "message", fmt.Sprintf("invalid patch for %s", resourceName), "stack", microerror.JSON(err))
+			requestMetrics.Errored(err)
+			writeResponse(webhookHandler, writer, review, errorResponse(review.Request.UID, microerror.Mask(err)))
+			return
+		}
+
 		patchData, err := json.Marshal(patch)
 		if err != nil {
 			webhookHandler.Log("level", "error", "message", fmt.Sprintf("unable to serialize patch for %s", resourceName), "stack", microerror.JSON(err))
-			writeResponse(webhookHandler, writer, errorResponse(review.Request.UID, InternalError))
+			requestMetrics.Errored(err)
+			writeResponse(webhookHandler, writer, review, errorResponse(review.Request.UID, InternalError))
 			return
 		}
 
 		webhookHandler.Log("level", "debug", "message", fmt.Sprintf("admitted %s (with %d patches)", resourceName, len(patch)))
+		requestMetrics.Allowed(len(patch))
 
-		pt := v1beta1.PatchTypeJSONPatch
-		writeResponse(webhookHandler, writer, &v1beta1.AdmissionResponse{
+		pt := admissionv1.PatchTypeJSONPatch
+		writeResponse(webhookHandler, writer, review, &admissionv1.AdmissionResponse{
 			Allowed:   true,
 			UID:       review.Request.UID,
 			Patch:     patchData,
@@ -194,3 +215,22 @@ func (h *HttpHandlerFactory) newHttpHandler(webhookHandler WebhookHandlerBase, m
 		})
 	}
 }
+
+// checkPatch applies the patch to the object and decodes the result.
+func checkPatch(decoder generic.Decoder, object runtime.RawExtension, patch []PatchOperation) error {
+	if len(patch) == 0 {
+		return nil
+	}
+
+	patched, err := ApplyPatch(object.Raw, patch)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	_, err = decoder.Decode(runtime.RawExtension{Raw: patched})
+	if err != nil {
+		return microerror.Maskf(invalidPatchError, "patched object can't be decoded: %v", err)
+	}
+
+	return nil
+}
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -16,13 +18,16 @@ import (
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	releasev1alpha1 "github.com/giantswarm/release-operator/v3/api/v1alpha1"
-	admission "k8s.io/api/admission/v1beta1"
+	"github.com/prometheus/client_golang/prometheus"
+	admission "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
 	builder "github.com/giantswarm/azure-admission-controller/internal/test/cluster"
+	pkgadmission "github.com/giantswarm/azure-admission-controller/pkg/admission"
+	"github.com/giantswarm/azure-admission-controller/pkg/metrics"
 	"github.com/giantswarm/azure-admission-controller/pkg/release"
 	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
 )
@@ -49,7 +54,10 @@ type object interface {
 func TestHttpHandler(t *testing.T) {
 	type testCase struct {
 		name          string
+		apiVersion    string
+		dryRun        bool
 		object        object
+		patches       []PatchOperation
 		oldObject     object
 		operation     admission.Operation
 		expectedError *microerror.Error
@@ -84,6 +92,50 @@ func TestHttpHandler(t *testing.T) {
 			expectedError: release.ReleaseNotFoundError,
 			operation:     admission.Create,
 		},
+		{
+			name:       "Mutate Cluster creation for legacy releases with admission.k8s.io/v1 review",
+			apiVersion: pkgadmission.APIVersionV1,
+			object: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				})),
+			operation: admission.Create,
+		},
+		{
+			name:       "Mutate Cluster creation for legacy releases with admission.k8s.io/v1beta1 review",
+			apiVersion: pkgadmission.APIVersionV1beta1,
+			object: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				})),
+			operation: admission.Create,
+		},
+		{
+			name:       "Mutate Cluster creation for non-existing releases with admission.k8s.io/v1 review",
+			apiVersion: pkgadmission.APIVersionV1,
+			object: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: "0.0.0-NonExistentRelease",
+				})),
+			expectedError: release.ReleaseNotFoundError,
+			operation:     admission.Create,
+		},
+		{
+			name:   "Mutate Cluster creation for legacy releases on dry-run",
+			dryRun: true,
+			patches: []PatchOperation{
+				*PatchAdd("/metadata/labels/foo", "bar"),
+			},
+			object: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				})),
+			operation: admission.Create,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -120,6 +172,7 @@ func TestHttpHandler(t *testing.T) {
 				DecodeFunc: func(runtime.RawExtension) (metav1.ObjectMetaAccessor, error) {
 					return tc.object, nil
 				},
+				Patches: tc.patches,
 			}
 
 			var httpHandler http.HandlerFunc
@@ -138,7 +191,7 @@ func TestHttpHandler(t *testing.T) {
 			// That request will contain admission review for creating/updating an object. This is
 			// basically the request body that API server would send to the webhook.
 			//
-			admissionReviewJson := getAdmissionReview(t, tc.operation, tc.object, tc.oldObject)
+			admissionReviewJson := getAdmissionReview(t, tc.apiVersion, tc.dryRun, tc.operation, tc.object, tc.oldObject)
 			request := getHttpRequest(t, admissionReviewJson)
 
 			//
@@ -156,9 +209,32 @@ func TestHttpHandler(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			// The response must be sent in the same version as the request. Reviews without
+			// apiVersion are treated as admission.k8s.io/v1beta1.
+			expectedAPIVersion := tc.apiVersion
+			if expectedAPIVersion == "" {
+				expectedAPIVersion = pkgadmission.APIVersionV1beta1
+			}
+			if admissionReview.APIVersion != expectedAPIVersion {
+				t.Fatalf("expected response apiVersion %q, got %q", expectedAPIVersion, admissionReview.APIVersion)
+			}
+
 			//
 			// Now let's check the handler response.
 			//
+			if admissionReview.Response.Allowed {
+				// webhook handler returned the patches from the mutation chain, also for dry-run requests
+				var patches []PatchOperation
+				err = json.Unmarshal(admissionReview.Response.Patch, &patches)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(patches) != len(tc.patches) {
+					t.Fatalf("expected %d patches, got %d", len(tc.patches), len(patches))
+				}
+			}
+
 			if !admissionReview.Response.Allowed {
 				// webhook handler returned an error and it is rejecting the request
 
@@ -199,6 +275,120 @@ func TestHttpHandler(t *testing.T) {
 	}
 }
 
+func TestHttpHandlerInvalidPatch(t *testing.T) {
+	testCases := []struct {
+		name        string
+		patches     []PatchOperation
+		decodeError error
+	}{
+		{
+			name: "case 0: patch can't be applied",
+			patches: []PatchOperation{
+				*PatchAdd("/spec/missing/field", "value"),
+			},
+		},
+		{
+			name: "case 1: patched object can't be decoded",
+			patches: []PatchOperation{
+				*PatchAdd("/metadata/labels/foo", "bar"),
+			},
+			decodeError: errors.New("patched object is broken"),
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			logger, _ := micrologger.New(micrologger.Config{})
+			ctrlClient := unittest.FakeK8sClient().CtrlClient()
+			loadReleases(t, context.Background(), ctrlClient)
+
+			httpHandlerFactory, err := NewHttpHandlerFactory(HttpHandlerFactoryConfig{
+				CtrlReader: ctrlClient,
+				CtrlClient: ctrlClient,
+				Logger:     logger,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			object := builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				}))
+			objectJson, err := json.Marshal(object)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			webhookHandlerMock := WebhookHandlerMock{
+				DecodeFunc: func(raw runtime.RawExtension) (metav1.ObjectMetaAccessor, error) {
+					// Only the patched object fails to decode, the object of
+					// the request has to be decoded to mutate it.
+					if tc.decodeError != nil && !bytes.Equal(raw.Raw, objectJson) {
+						return nil, tc.decodeError
+					}
+					return object, nil
+				},
+				Patches: tc.patches,
+			}
+
+			errored := erroredResponses(t)
+
+			admissionReviewJson := getAdmissionReview(t, "", false, admission.Create, object, nil)
+			httpRecorder := httptest.NewRecorder()
+			httpHandlerFactory.NewCreateHandler(&webhookHandlerMock).ServeHTTP(httpRecorder, getHttpRequest(t, admissionReviewJson))
+
+			var admissionReview admission.AdmissionReview
+			err = json.Unmarshal(httpRecorder.Body.Bytes(), &admissionReview)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if admissionReview.Response.Allowed {
+				t.Fatalf("expected request to be rejected, it was allowed with patch %s", admissionReview.Response.Patch)
+			}
+			expectedErrorMessage := microerror.Mask(invalidPatchError).Error()
+			if admissionReview.Response.Result == nil || !strings.Contains(admissionReview.Response.Result.Message, expectedErrorMessage) {
+				t.Fatalf("expected error message '%s', got %#v", expectedErrorMessage, admissionReview.Response.Result)
+			}
+
+			if erroredResponses(t) != errored+1 {
+				t.Fatalf("expected request to be counted as errored")
+			}
+		})
+	}
+}
+
+// erroredResponses returns the number of errored responses of the mock
+// webhook handler recorded by the webhook metrics.
+func erroredResponses(t *testing.T) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var count float64
+	for _, family := range families {
+		if family.GetName() != "azure_admission_controller_webhook_responses_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range metric.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["resource"] == "mock_type" && labels["result"] == metrics.ResultErrored {
+				count += metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return count
+}
+
 func getHttpRequest(t *testing.T, admissionReview []byte) *http.Request {
 	requestBody := bytes.NewBuffer(admissionReview)
 	request, err := http.NewRequest("POST", "", requestBody)
@@ -210,7 +400,7 @@ func getHttpRequest(t *testing.T, admissionReview []byte) *http.Request {
 	return request
 }
 
-func getAdmissionReview(t *testing.T, operation admission.Operation, object runtime.Object, oldObject runtime.Object) []byte {
+func getAdmissionReview(t *testing.T, apiVersion string, dryRun bool, operation admission.Operation, object runtime.Object, oldObject runtime.Object) []byte {
 	objectJson, err := json.Marshal(object)
 	if err != nil {
 		t.Fatal(err)
@@ -222,6 +412,7 @@ func getAdmissionReview(t *testing.T, operation admission.Operation, object runt
 			Resource: object.GetObjectKind().GroupVersionKind().Kind,
 		},
 		Operation: operation,
+		DryRun:    &dryRun,
 		Object: runtime.RawExtension{
 			Raw:    objectJson,
 			Object: nil,
@@ -240,9 +431,13 @@ func getAdmissionReview(t *testing.T, operation admission.Operation, object runt
 		}
 	}
 
+	// admission.k8s.io/v1 and admission.k8s.io/v1beta1 reviews have the same
+	// structure, so only the apiVersion needs to be set here.
 	admissionReview := admission.AdmissionReview{
-		TypeMeta: metav1.TypeMeta{},
-		Request:  admissionRequest,
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiVersion,
+		},
+		Request: admissionRequest,
 	}
 
 	admissionReviewJson, err := json.Marshal(admissionReview)
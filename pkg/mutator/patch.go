@@ -1,17 +1,68 @@
 package mutator
 
+import (
+	"encoding/json"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	OperationAdd     = "add"
+	OperationMove    = "move"
+	OperationRemove  = "remove"
+	OperationReplace = "replace"
+	OperationTest    = "test"
+)
+
 // PatchOperation specifies one JSONPatch operation.
 // See [RFC6902](https://tools.ietf.org/html/rfc6902) for details.
 type PatchOperation struct {
 	Operation string      `json:"op"`
 	Path      string      `json:"path"`
 	Value     interface{} `json:"value"`
+	From      string      `json:"from,omitempty"`
+}
+
+// MarshalJSON only sets the members of the operation it has according to
+// RFC6902, e.g. "remove" has no value, and "move" has a "from" member.
+func (p PatchOperation) MarshalJSON() ([]byte, error) {
+	switch p.Operation {
+	case OperationRemove:
+		return json.Marshal(struct {
+			Operation string `json:"op"`
+			Path      string `json:"path"`
+		}{p.Operation, p.Path})
+	case OperationMove:
+		return json.Marshal(struct {
+			Operation string `json:"op"`
+			From      string `json:"from"`
+			Path      string `json:"path"`
+		}{p.Operation, p.From, p.Path})
+	default:
+		return json.Marshal(struct {
+			Operation string      `json:"op"`
+			Path      string      `json:"path"`
+			Value     interface{} `json:"value"`
+		}{p.Operation, p.Path, p.Value})
+	}
+}
+
+// EscapeJSONPatchString escapes a JSON pointer reference token, e.g. a label
+// or annotation name, to use it in the path of a patch operation. See
+// https://tools.ietf.org/html/rfc6901#section-3 .
+func EscapeJSONPatchString(input string) string {
+	input = strings.ReplaceAll(input, "~", "~0")
+	input = strings.ReplaceAll(input, "/", "~1")
+
+	return input
 }
 
 // PatchReplace creates a patch operation of type "replace".
 func PatchReplace(path string, value interface{}) PatchOperation {
 	return PatchOperation{
-		Operation: "replace",
+		Operation: OperationReplace,
 		Path:      path,
 		Value:     value,
 	}
@@ -31,8 +82,62 @@ func PatchReplace(path string, value interface{}) PatchOperation {
 //
 func PatchAdd(path string, value interface{}) *PatchOperation {
 	return &PatchOperation{
-		Operation: "add",
+		Operation: OperationAdd,
 		Path:      path,
 		Value:     value,
 	}
 }
+
+// PatchRemove creates a patch operation of type "remove". The target location
+// must exist.
+func PatchRemove(path string) PatchOperation {
+	return PatchOperation{
+		Operation: OperationRemove,
+		Path:      path,
+	}
+}
+
+// PatchTest creates a patch operation of type "test". The whole patch is not
+// applied when the value at the target location is not equal to the given
+// value.
+func PatchTest(path string, value interface{}) PatchOperation {
+	return PatchOperation{
+		Operation: OperationTest,
+		Path:      path,
+		Value:     value,
+	}
+}
+
+// PatchMove creates a patch operation of type "move", which removes the value
+// at the from location and adds it to the target location.
+func PatchMove(from string, path string) PatchOperation {
+	return PatchOperation{
+		Operation: OperationMove,
+		Path:      path,
+		From:      from,
+	}
+}
+
+// ApplyPatch applies the patch operations to the given JSON document.
+func ApplyPatch(original []byte, patch []PatchOperation) ([]byte, error) {
+	if len(patch) == 0 {
+		return original, nil
+	}
+
+	patchData, err := json.Marshal(patch)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	decoded, err := jsonpatch.DecodePatch(patchData)
+	if err != nil {
+		return nil, microerror.Maskf(invalidPatchError, "%s", err)
+	}
+
+	patched, err := decoded.Apply(original)
+	if err != nil {
+		return nil, microerror.Maskf(invalidPatchError, "%s", err)
+	}
+
+	return patched, nil
+}
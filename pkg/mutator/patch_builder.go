@@ -0,0 +1,171 @@
+package mutator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+)
+
+// PatchBuilder collects the patch operations of a mutation and builds the
+// final JSON patch out of them. The zero value is ready to use.
+//
+// Patches set by our mutations are added with Add, while the patches
+// generated from the diff of the CAPI Default() func are added with
+// AddDefaults. Both are computed against the original object, so they can
+// write to the same path. In that case our patch takes precedence, as the
+// CAPI defaulting webhook would not change a field which is already set, and
+// the default patch is dropped and reported by Conflicts.
+type PatchBuilder struct {
+	patches   []PatchOperation
+	defaults  []PatchOperation
+	conflicts []string
+}
+
+// Add adds the given patch operation. It does nothing when the patch is nil,
+// so that the result of the ensure funcs can be added as is.
+func (b *PatchBuilder) Add(patch *PatchOperation) {
+	if patch == nil {
+		return
+	}
+
+	b.patches = append(b.patches, *patch)
+}
+
+// AddAll adds all given patch operations.
+func (b *PatchBuilder) AddAll(patches []PatchOperation) {
+	b.patches = append(b.patches, patches...)
+}
+
+// AddDefaults adds the patch operations generated from the diff of the CAPI
+// Default() func.
+func (b *PatchBuilder) AddDefaults(patches []PatchOperation) {
+	b.defaults = append(b.defaults, patches...)
+}
+
+// Conflicts returns the default patch operations dropped by the last Build,
+// as they were writing to a path our patch operations write to as well.
+func (b *PatchBuilder) Conflicts() []string {
+	return b.conflicts
+}
+
+// Build returns the final JSON patch. Duplicated operations are removed, and
+// the operations are ordered so that they can be applied one after the
+// other: our patch operations in the order they were added, so that a "test"
+// operation checks the object as changed by the operations added before it,
+// and then the default patch operations.
+// It returns an error when two of our patch operations write different
+// values to the same path, unless both append to the same array.
+func (b *PatchBuilder) Build() ([]PatchOperation, error) {
+	b.conflicts = nil
+
+	var result []PatchOperation
+	for _, patch := range b.patches {
+		duplicate := false
+		for _, other := range result {
+			if equal(patch, other) {
+				duplicate = true
+				break
+			}
+			if patch.Operation != OperationTest && other.Operation != OperationTest && patch.Path == other.Path && !isArrayAppend(patch.Path) {
+				return nil, microerror.Maskf(conflictingPatchError, "patch operations %s and %s write to the same path", describe(other), describe(patch))
+			}
+		}
+		if !duplicate {
+			result = append(result, patch)
+		}
+	}
+
+	// Default patch operations go after ours, unless they write to a parent
+	// of a path our patch operations write to. Those go first, so that they
+	// don't overwrite our changes below the defaulted parent.
+	var parentDefaults, defaults []PatchOperation
+	for _, patch := range b.defaults {
+		keep := true
+		parent := false
+		for _, other := range result {
+			if equal(patch, other) {
+				// Our patch does the same already.
+				keep = false
+				break
+			}
+			if other.Operation != OperationTest && overrides(other, patch) {
+				b.conflicts = append(b.conflicts, fmt.Sprintf("default patch operation %s was dropped, as it conflicts with %s", describe(patch), describe(other)))
+				keep = false
+				break
+			}
+			if other.Operation != OperationTest && isParent(patch.Path, other.Path) {
+				parent = true
+			}
+		}
+		if !keep {
+			continue
+		}
+		if parent {
+			parentDefaults = append(parentDefaults, patch)
+		} else {
+			defaults = append(defaults, patch)
+		}
+	}
+
+	var ordered []PatchOperation
+	ordered = append(ordered, parentDefaults...)
+	ordered = append(ordered, result...)
+	ordered = append(ordered, defaults...)
+
+	return ordered, nil
+}
+
+// overrides returns whether the patch writes to the path, or a parent path,
+// the default patch operates on.
+func overrides(patch PatchOperation, defaultPatch PatchOperation) bool {
+	written := []string{patch.Path}
+	if patch.Operation == OperationMove {
+		written = append(written, patch.From)
+	}
+	operated := []string{defaultPatch.Path}
+	if defaultPatch.Operation == OperationMove {
+		operated = append(operated, defaultPatch.From)
+	}
+
+	for _, w := range written {
+		for _, o := range operated {
+			if o == w || isParent(w, o) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// equal returns whether both patch operations are the same, comparing their
+// values by their JSON representation, as the values of generated patches are
+// decoded from JSON.
+func equal(a PatchOperation, b PatchOperation) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
+
+// isParent returns whether the path is a parent of the child path.
+func isParent(path string, child string) bool {
+	return strings.HasPrefix(child, path+"/")
+}
+
+// isArrayAppend returns whether the path appends to an array, which several
+// patch operations can do without overwriting each other.
+func isArrayAppend(path string) bool {
+	return strings.HasSuffix(path, "/-")
+}
+
+func describe(patch PatchOperation) string {
+	if patch.Operation == OperationMove {
+		return fmt.Sprintf("%q from %#q to %#q", patch.Operation, patch.From, patch.Path)
+	}
+
+	return fmt.Sprintf("%q on %#q", patch.Operation, patch.Path)
+}
@@ -0,0 +1,240 @@
+package mutator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func Test_PatchBuilder(t *testing.T) {
+	testCases := []struct {
+		name         string
+		patches      []PatchOperation
+		defaults     []PatchOperation
+		expected     []PatchOperation
+		conflicts    int
+		errorMatcher func(error) bool
+	}{
+		{
+			name: "case 0: duplicated patch operations are removed",
+			patches: []PatchOperation{
+				PatchReplace("/spec/replicas", 3),
+				PatchReplace("/spec/replicas", 3),
+			},
+			defaults: []PatchOperation{
+				PatchReplace("/spec/replicas", 3),
+				PatchReplace("/spec/minReadySeconds", 0),
+			},
+			expected: []PatchOperation{
+				PatchReplace("/spec/replicas", 3),
+				PatchReplace("/spec/minReadySeconds", 0),
+			},
+		},
+		{
+			name: "case 1: two of our patch operations write to the same path",
+			patches: []PatchOperation{
+				PatchReplace("/spec/replicas", 3),
+				PatchReplace("/spec/replicas", 5),
+			},
+			errorMatcher: IsConflictingPatch,
+		},
+		{
+			name: "case 2: conflicting default patch operations are dropped",
+			patches: []PatchOperation{
+				*PatchAdd("/spec/template/osDisk", map[string]interface{}{"diskSizeGB": 50}),
+			},
+			defaults: []PatchOperation{
+				PatchReplace("/spec/template/osDisk", map[string]interface{}{"diskSizeGB": 30}),
+				*PatchAdd("/spec/template/osDisk/cachingType", "None"),
+				*PatchAdd("/spec/template/vmSize", "Standard_D4s_v3"),
+			},
+			expected: []PatchOperation{
+				*PatchAdd("/spec/template/osDisk", map[string]interface{}{"diskSizeGB": 50}),
+				*PatchAdd("/spec/template/vmSize", "Standard_D4s_v3"),
+			},
+			conflicts: 2,
+		},
+		{
+			name: "case 3: default patch operations on a parent path go first",
+			patches: []PatchOperation{
+				*PatchAdd("/spec/template/osDisk/cachingType", "ReadOnly"),
+			},
+			defaults: []PatchOperation{
+				*PatchAdd("/spec/replicas", 1),
+				*PatchAdd("/spec/template", map[string]interface{}{"osDisk": map[string]interface{}{}}),
+			},
+			expected: []PatchOperation{
+				*PatchAdd("/spec/template", map[string]interface{}{"osDisk": map[string]interface{}{}}),
+				*PatchAdd("/spec/template/osDisk/cachingType", "ReadOnly"),
+				*PatchAdd("/spec/replicas", 1),
+			},
+		},
+		{
+			name: "case 4: test patch operations keep their order",
+			patches: []PatchOperation{
+				PatchTest("/metadata/annotations/a", "b"),
+				PatchRemove("/metadata/annotations/a"),
+				*PatchAdd("/metadata/annotations/c", "f"),
+				PatchTest("/metadata/annotations/c", "f"),
+				PatchMove("/metadata/annotations/c", "/metadata/annotations/d"),
+			},
+			defaults: []PatchOperation{
+				*PatchAdd("/metadata/annotations/d", "e"),
+			},
+			expected: []PatchOperation{
+				PatchTest("/metadata/annotations/a", "b"),
+				PatchRemove("/metadata/annotations/a"),
+				*PatchAdd("/metadata/annotations/c", "f"),
+				PatchTest("/metadata/annotations/c", "f"),
+				PatchMove("/metadata/annotations/c", "/metadata/annotations/d"),
+			},
+			conflicts: 1,
+		},
+		{
+			name: "case 5: two of our patch operations append to the same array",
+			patches: []PatchOperation{
+				*PatchAdd("/spec/template/taints/-", map[string]interface{}{"key": "a"}),
+				*PatchAdd("/spec/template/taints/-", map[string]interface{}{"key": "b"}),
+			},
+			expected: []PatchOperation{
+				*PatchAdd("/spec/template/taints/-", map[string]interface{}{"key": "a"}),
+				*PatchAdd("/spec/template/taints/-", map[string]interface{}{"key": "b"}),
+			},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			var builder PatchBuilder
+			builder.Add(nil)
+			builder.AddAll(tc.patches)
+			builder.AddDefaults(tc.defaults)
+
+			result, err := builder.Build()
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Fatalf("Patches mismatch: expected %v, got %v", tc.expected, result)
+			}
+
+			if len(builder.Conflicts()) != tc.conflicts {
+				t.Fatalf("expected %d conflicts, got %v", tc.conflicts, builder.Conflicts())
+			}
+		})
+	}
+}
+
+func Test_PatchOperationMarshalJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		patch    PatchOperation
+		expected string
+	}{
+		{
+			name:     "case 0: add has a value",
+			patch:    *PatchAdd("/spec/replicas", 1),
+			expected: `{"op":"add","path":"/spec/replicas","value":1}`,
+		},
+		{
+			name:     "case 1: remove has no value",
+			patch:    PatchRemove("/spec/replicas"),
+			expected: `{"op":"remove","path":"/spec/replicas"}`,
+		},
+		{
+			name:     "case 2: move has a from member and no value",
+			patch:    PatchMove("/metadata/annotations/a", "/metadata/annotations/b"),
+			expected: `{"op":"move","from":"/metadata/annotations/a","path":"/metadata/annotations/b"}`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			data, err := json.Marshal(tc.patch)
+			if err != nil {
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			if string(data) != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, data)
+			}
+		})
+	}
+}
+
+func Test_ApplyPatch(t *testing.T) {
+	testCases := []struct {
+		name         string
+		original     string
+		patch        []PatchOperation
+		expected     string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:     "case 0: patch is applied",
+			original: `{"metadata":{"annotations":{"a":"b"}}}`,
+			patch: []PatchOperation{
+				PatchTest("/metadata/annotations/a", "b"),
+				PatchMove("/metadata/annotations/a", "/metadata/annotations/c"),
+			},
+			expected: `{"metadata":{"annotations":{"c":"b"}}}`,
+		},
+		{
+			name:     "case 1: test patch operation fails",
+			original: `{"metadata":{"annotations":{"a":"b"}}}`,
+			patch: []PatchOperation{
+				PatchTest("/metadata/annotations/a", "c"),
+			},
+			errorMatcher: IsInvalidPatch,
+		},
+		{
+			name:     "case 2: removed path does not exist",
+			original: `{"metadata":{}}`,
+			patch: []PatchOperation{
+				PatchRemove("/metadata/annotations/a"),
+			},
+			errorMatcher: IsInvalidPatch,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			result, err := ApplyPatch([]byte(tc.original), tc.patch)
+
+			switch {
+			case err == nil && tc.errorMatcher == nil:
+				// correct; carry on
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("unexpected error: %#v", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("expected error, got nil")
+			case !tc.errorMatcher(err):
+				t.Fatalf("unexpected error: %#v", err)
+			}
+
+			if tc.errorMatcher == nil && string(result) != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, result)
+			}
+		})
+	}
+}
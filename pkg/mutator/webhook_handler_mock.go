@@ -9,6 +9,7 @@ import (
 
 type WebhookHandlerMock struct {
 	DecodeFunc func(runtime.RawExtension) (metav1.ObjectMetaAccessor, error)
+	Patches    []PatchOperation
 }
 
 func (h *WebhookHandlerMock) Log(_ ...interface{}) {}
@@ -22,9 +23,9 @@ func (h *WebhookHandlerMock) Decode(object runtime.RawExtension) (metav1.ObjectM
 }
 
 func (h *WebhookHandlerMock) OnCreateMutate(_ context.Context, _ interface{}) ([]PatchOperation, error) {
-	return nil, nil
+	return h.Patches, nil
 }
 
 func (h *WebhookHandlerMock) OnUpdateMutate(_ context.Context, _ interface{}, _ interface{}) ([]PatchOperation, error) {
-	return nil, nil
+	return h.Patches, nil
 }
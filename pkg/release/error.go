@@ -2,6 +2,8 @@ package release
 
 import (
 	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/azure-admission-controller/internal/errors"
 )
 
 var ReleaseNotFoundError = &microerror.Error{
@@ -12,3 +14,9 @@ var ReleaseNotFoundError = &microerror.Error{
 func IsReleaseNotFoundError(err error) bool {
 	return microerror.Cause(err) == ReleaseNotFoundError
 }
+
+func init() {
+	errors.RegisterValidationErrors(
+		ReleaseNotFoundError,
+	)
+}
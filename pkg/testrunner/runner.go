@@ -9,7 +9,7 @@ import (
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/ghodss/yaml"
 	"github.com/stretchr/testify/require"
-	"k8s.io/api/admission/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -24,12 +24,12 @@ type Runner struct {
 }
 
 type testcaseDefinition struct {
-	Config    interface{}       `json:"config"`
-	Object    interface{}       `json:"object"`
-	Expected  interface{}       `json:"expected"`
-	Operation v1beta1.Operation `json:"operation"`
-	Error     string            `json:"error"`
-	Namespace string            `json:"namespace"`
+	Config    interface{}           `json:"config"`
+	Object    interface{}           `json:"object"`
+	Expected  interface{}           `json:"expected"`
+	Operation admissionv1.Operation `json:"operation"`
+	Error     string                `json:"error"`
+	Namespace string                `json:"namespace"`
 }
 
 func (runner *Runner) parseObject(t *testing.T, from string) runtime.Object {
@@ -39,7 +39,7 @@ func (runner *Runner) parseObject(t *testing.T, from string) runtime.Object {
 	return object
 }
 
-func (runner *Runner) objectToRequest(t *testing.T, object runtime.Object, namespace string, operation v1beta1.Operation) *v1beta1.AdmissionRequest {
+func (runner *Runner) objectToRequest(t *testing.T, object runtime.Object, namespace string, operation admissionv1.Operation) *admissionv1.AdmissionRequest {
 	serialized, err := json.Marshal(object)
 	require.NoError(t, err)
 	gvk := object.GetObjectKind().GroupVersionKind()
@@ -49,7 +49,7 @@ func (runner *Runner) objectToRequest(t *testing.T, object runtime.Object, names
 	name, err := accessor.Name(object)
 	require.NoError(t, err)
 
-	return &v1beta1.AdmissionRequest{
+	return &admissionv1.AdmissionRequest{
 		UID:       "example",
 		Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
 		Resource:  runner.Resource,
@@ -0,0 +1,34 @@
+package unittest
+
+import (
+	"context"
+
+	"github.com/giantswarm/micrologger"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/giantswarm/azure-admission-controller/internal/azurequota"
+)
+
+type AzureQuotaStubFactory struct {
+	logger        micrologger.Logger
+	stubbedUsages []azurequota.Usage
+}
+
+func NewAzureQuotaStubFactory(stubbedUsages []azurequota.Usage, logger micrologger.Logger) azurequota.Factory {
+	return &AzureQuotaStubFactory{
+		logger:        logger,
+		stubbedUsages: stubbedUsages,
+	}
+}
+
+func (s *AzureQuotaStubFactory) GetClient(_ context.Context, _ client.Client, _ v1.ObjectMeta) (*azurequota.VCPUQuota, error) {
+	q, err := azurequota.New(azurequota.Config{
+		Azure:  NewUsageStubAPI(s.stubbedUsages),
+		Logger: s.logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
@@ -1,6 +1,7 @@
 package unittest
 
 import (
+	expcapz "github.com/giantswarm/apiextensions/v6/pkg/apis/capzexp/v1alpha3"
 	corev1alpha1 "github.com/giantswarm/apiextensions/v6/pkg/apis/core/v1alpha1"
 	providerv1alpha1 "github.com/giantswarm/apiextensions/v6/pkg/apis/provider/v1alpha1"
 	"github.com/giantswarm/k8sclient/v7/pkg/k8sclient"
@@ -52,6 +53,10 @@ func FakeK8sClient() k8sclient.Interface {
 		if err != nil {
 			panic(err)
 		}
+		err = expcapz.AddToScheme(scheme)
+		if err != nil {
+			panic(err)
+		}
 		err = providerv1alpha1.AddToScheme(scheme)
 		if err != nil {
 			panic(err)
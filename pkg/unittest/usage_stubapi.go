@@ -0,0 +1,19 @@
+package unittest
+
+import (
+	"context"
+
+	"github.com/giantswarm/azure-admission-controller/internal/azurequota"
+)
+
+type UsageStubAPI struct {
+	stubbedUsages []azurequota.Usage
+}
+
+func NewUsageStubAPI(stubbedUsages []azurequota.Usage) azurequota.API {
+	return &UsageStubAPI{stubbedUsages: stubbedUsages}
+}
+
+func (s *UsageStubAPI) List(_ context.Context, _ string) ([]azurequota.Usage, error) {
+	return s.stubbedUsages, nil
+}
@@ -2,23 +2,25 @@ package validator
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 
 	"github.com/giantswarm/microerror"
-	"k8s.io/api/admission/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
 
+	internalerrors "github.com/giantswarm/azure-admission-controller/internal/errors"
+	"github.com/giantswarm/azure-admission-controller/pkg/admission"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
 )
 
 type Validator interface {
-	Validate(ctx context.Context, request *v1beta1.AdmissionRequest) error
+	Validate(ctx context.Context, request *admissionv1.AdmissionRequest) error
 	Log(keyVals ...interface{})
 }
 
@@ -43,34 +45,28 @@ func Handler(validator Validator) http.HandlerFunc {
 			return
 		}
 
-		review := v1beta1.AdmissionReview{}
-		if _, _, err := Deserializer.Decode(data, nil, &review); err != nil {
-			validator.Log("level", "error", "message", "unable to parse admission review request")
+		review, err := admission.DecodeReview(data)
+		if err != nil {
+			validator.Log("level", "error", "message", "unable to parse admission review request", "stack", microerror.JSON(err))
 			writer.WriteHeader(http.StatusBadRequest)
 			return
 		}
 
 		err = validator.Validate(request.Context(), review.Request)
 		if err != nil {
-			writeResponse(validator, writer, errorResponse(review.Request.UID, microerror.Mask(err)))
+			writeResponse(validator, writer, review, errorResponse(review.Request.UID, microerror.Mask(err)))
 			return
 		}
 
-		writeResponse(validator, writer, &v1beta1.AdmissionResponse{
+		writeResponse(validator, writer, review, &admissionv1.AdmissionResponse{
 			Allowed: true,
 			UID:     review.Request.UID,
 		})
 	}
 }
 
-func writeResponse(logger generic.Logger, writer http.ResponseWriter, response *v1beta1.AdmissionResponse) {
-	resp, err := json.Marshal(v1beta1.AdmissionReview{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "AdmissionReview",
-			APIVersion: "admission.k8s.io/v1",
-		},
-		Response: response,
-	})
+func writeResponse(logger generic.Logger, writer http.ResponseWriter, review *admission.Review, response *admissionv1.AdmissionResponse) {
+	resp, err := review.EncodeResponse(response)
 	if err != nil {
 		logger.Log("level", "error", "message", "unable to serialize response", "stack", microerror.JSON(err))
 		writer.WriteHeader(http.StatusInternalServerError)
@@ -83,8 +79,21 @@ func writeResponse(logger generic.Logger, writer http.ResponseWriter, response *
 	logger.Log("level", "info", "message", fmt.Sprintf("Validated request responded with result: %t", response.Allowed))
 }
 
-func errorResponse(uid types.UID, err error) *v1beta1.AdmissionResponse {
-	return &v1beta1.AdmissionResponse{
+func errorResponse(uid types.UID, err error) *admissionv1.AdmissionResponse {
+	// When all validation failures were collected, return each of them as a
+	// separate cause, so that the user can fix all of them at once.
+	var validationErrors *internalerrors.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		status := validationErrors.Status()
+
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			UID:     uid,
+			Result:  &status,
+		}
+	}
+
+	return &admissionv1.AdmissionResponse{
 		Allowed: false,
 		UID:     uid,
 		Result: &metav1.Status{
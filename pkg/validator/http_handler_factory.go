@@ -8,26 +8,31 @@ import (
 
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
-	"k8s.io/api/admission/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	capi "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
+	"github.com/giantswarm/azure-admission-controller/pkg/admission"
 	"github.com/giantswarm/azure-admission-controller/pkg/filter"
 	"github.com/giantswarm/azure-admission-controller/pkg/generic"
+	"github.com/giantswarm/azure-admission-controller/pkg/metrics"
 )
 
 type HttpHandlerFactoryConfig struct {
-	CtrlReader client.Reader
-	CtrlClient client.Client
-	Logger     micrologger.Logger
+	CtrlReader  client.Reader
+	CtrlClient  client.Client
+	Enforcement *enforcement.Registry
+	Logger      micrologger.Logger
 }
 
-// HttpHandlerFactory creates HTTP handlers for validating create and update requests.
+// HttpHandlerFactory creates HTTP handlers for validating create, update and delete requests.
 type HttpHandlerFactory struct {
-	ctrlReader client.Reader
-	ctrlClient client.Client
-	logger     micrologger.Logger
+	ctrlReader  client.Reader
+	ctrlClient  client.Client
+	enforcement *enforcement.Registry
+	logger      micrologger.Logger
 }
 
 func NewHttpHandlerFactory(config HttpHandlerFactoryConfig) (*HttpHandlerFactory, error) {
@@ -37,14 +42,18 @@ func NewHttpHandlerFactory(config HttpHandlerFactoryConfig) (*HttpHandlerFactory
 	if config.CtrlClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.CtrlClient must not be empty", config)
 	}
+	if config.Enforcement == nil {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Enforcement must not be empty", config)
+	}
 	if config.Logger == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Logger must not be empty", config)
 	}
 
 	h := &HttpHandlerFactory{
-		ctrlReader: config.CtrlReader,
-		ctrlClient: config.CtrlClient,
-		logger:     config.Logger,
+		ctrlReader:  config.CtrlReader,
+		ctrlClient:  config.CtrlClient,
+		enforcement: config.Enforcement,
+		logger:      config.Logger,
 	}
 
 	return h, nil
@@ -52,84 +61,178 @@ func NewHttpHandlerFactory(config HttpHandlerFactoryConfig) (*HttpHandlerFactory
 
 // NewCreateHandler returns a HTTP handler for validating create requests.
 func (h *HttpHandlerFactory) NewCreateHandler(webhookCreateHandler WebhookCreateHandler) http.HandlerFunc {
-	validateFunc := func(ctx context.Context, review v1beta1.AdmissionReview) error {
+	validateFunc := func(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) ([]string, error) {
 		// Decode the new CR from the request.
-		object, err := webhookCreateHandler.Decode(review.Request.Object)
+		object, err := webhookCreateHandler.Decode(admissionRequest.Object)
 		if err != nil {
-			return microerror.Mask(err)
-		}
-
-		ownerClusterGetter := func(objectMeta metav1.ObjectMetaAccessor) (capi.Cluster, bool, error) {
-			ownerCluster, ok, err := generic.TryGetOwnerCluster(ctx, h.ctrlClient, object)
-			if err != nil {
-				return capi.Cluster{}, false, microerror.Mask(err)
-			}
-
-			return ownerCluster, ok, nil
+			return nil, microerror.Mask(err)
 		}
 
 		// Check if the CR should be validated by the azure-admission-controller.
-		ok, err := filter.IsObjectReconciledByLegacyRelease(ctx, h.logger, h.ctrlReader, object, ownerClusterGetter)
+		ok, err := h.isObjectValidated(ctx, webhookCreateHandler, object)
 		if err != nil {
-			return microerror.Mask(err)
+			return nil, microerror.Mask(err)
 		}
 
+		var warnings []string
 		if ok {
 			// Validate the CR.
 			err = webhookCreateHandler.OnCreateValidate(ctx, object)
 			if err != nil {
-				return microerror.Mask(err)
+				return nil, microerror.Mask(err)
+			}
+
+			// Check for soft policy violations, if the handler supports it.
+			if webhookCreateWarner, ok := webhookCreateHandler.(WebhookCreateWarner); ok {
+				warnings, err = webhookCreateWarner.OnCreateWarn(ctx, object)
+				if err != nil {
+					// Warnings must never deny the request, so we just log the error.
+					h.logger.LogCtx(ctx, "level", "warning", "message", "unable to check for warnings", "stack", microerror.JSON(err))
+				}
 			}
 		}
 
-		return nil
+		return warnings, nil
 	}
 
-	return h.newHttpHandler(webhookCreateHandler, validateFunc)
+	return h.newHttpHandler(webhookCreateHandler, metrics.OperationCreate, validateFunc)
 }
 
 // NewUpdateHandler returns a HTTP handler for validating update requests.
 func (h *HttpHandlerFactory) NewUpdateHandler(webhookUpdateHandler WebhookUpdateHandler) http.HandlerFunc {
-	validateFunc := func(ctx context.Context, review v1beta1.AdmissionReview) error {
+	validateFunc := func(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) ([]string, error) {
 		// Decode the new updated CR from the request.
-		object, err := webhookUpdateHandler.Decode(review.Request.Object)
+		object, err := webhookUpdateHandler.Decode(admissionRequest.Object)
 		if err != nil {
-			return microerror.Mask(err)
+			return nil, microerror.Mask(err)
 		}
 
-		ownerClusterGetter := func(objectMeta metav1.ObjectMetaAccessor) (capi.Cluster, bool, error) {
-			ownerCluster, ok, err := generic.TryGetOwnerCluster(ctx, h.ctrlClient, object)
+		// Check if the CR should be validated by the azure-admission-controller.
+		ok, err := h.isObjectValidated(ctx, webhookUpdateHandler, object)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		var warnings []string
+		if ok {
+			// Decode the old CR from the request (before the update).
+			oldObject, err := webhookUpdateHandler.Decode(admissionRequest.OldObject)
 			if err != nil {
-				return capi.Cluster{}, false, microerror.Mask(err)
+				return nil, microerror.Mask(err)
 			}
 
-			return ownerCluster, ok, nil
+			// Validate the CR.
+			err = webhookUpdateHandler.OnUpdateValidate(ctx, oldObject, object)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			// Check for soft policy violations, if the handler supports it.
+			if webhookUpdateWarner, ok := webhookUpdateHandler.(WebhookUpdateWarner); ok {
+				warnings, err = webhookUpdateWarner.OnUpdateWarn(ctx, oldObject, object)
+				if err != nil {
+					// Warnings must never deny the request, so we just log the error.
+					h.logger.LogCtx(ctx, "level", "warning", "message", "unable to check for warnings", "stack", microerror.JSON(err))
+				}
+			}
+		}
+
+		return warnings, nil
+	}
+
+	return h.newHttpHandler(webhookUpdateHandler, metrics.OperationUpdate, validateFunc)
+}
+
+// NewStatusUpdateHandler returns a HTTP handler for validating updates of the
+// status subresource.
+func (h *HttpHandlerFactory) NewStatusUpdateHandler(webhookStatusUpdateHandler WebhookStatusUpdateHandler) http.HandlerFunc {
+	validateFunc := func(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) ([]string, error) {
+		// Decode the new updated CR from the request.
+		object, err := webhookStatusUpdateHandler.Decode(admissionRequest.Object)
+		if err != nil {
+			return nil, microerror.Mask(err)
 		}
 
 		// Check if the CR should be validated by the azure-admission-controller.
-		ok, err := filter.IsObjectReconciledByLegacyRelease(ctx, h.logger, h.ctrlReader, object, ownerClusterGetter)
+		ok, err := h.isObjectValidated(ctx, webhookStatusUpdateHandler, object)
 		if err != nil {
-			return microerror.Mask(err)
+			return nil, microerror.Mask(err)
 		}
 
 		if ok {
 			// Decode the old CR from the request (before the update).
-			oldObject, err := webhookUpdateHandler.Decode(review.Request.OldObject)
+			oldObject, err := webhookStatusUpdateHandler.Decode(admissionRequest.OldObject)
 			if err != nil {
-				return microerror.Mask(err)
+				return nil, microerror.Mask(err)
 			}
 
 			// Validate the CR.
-			err = webhookUpdateHandler.OnUpdateValidate(ctx, oldObject, object)
+			err = webhookStatusUpdateHandler.OnStatusUpdateValidate(ctx, oldObject, object)
 			if err != nil {
-				return microerror.Mask(err)
+				return nil, microerror.Mask(err)
 			}
 		}
 
-		return nil
+		return nil, nil
 	}
 
-	return h.newHttpHandler(webhookUpdateHandler, validateFunc)
+	return h.newHttpHandler(webhookStatusUpdateHandler, metrics.OperationUpdateStatus, validateFunc)
+}
+
+// NewDeleteHandler returns a HTTP handler for validating delete requests.
+func (h *HttpHandlerFactory) NewDeleteHandler(webhookDeleteHandler WebhookDeleteHandler) http.HandlerFunc {
+	validateFunc := func(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) ([]string, error) {
+		// Decode the CR that is being deleted from the request. Delete requests
+		// only contain the old object.
+		object, err := webhookDeleteHandler.Decode(admissionRequest.OldObject)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		// Check if the CR should be validated by the azure-admission-controller.
+		ok, err := h.isObjectValidated(ctx, webhookDeleteHandler, object)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if ok {
+			// Validate the CR.
+			err = webhookDeleteHandler.OnDeleteValidate(ctx, object)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+		}
+
+		return nil, nil
+	}
+
+	return h.newHttpHandler(webhookDeleteHandler, metrics.OperationDelete, validateFunc)
+}
+
+// isObjectValidated tells whether the given object has to be validated by the
+// handler: when the handler validates all objects (see
+// WebhookAllObjectsValidator), or when the object is reconciled by a legacy
+// release.
+func (h *HttpHandlerFactory) isObjectValidated(ctx context.Context, webhookHandler WebhookHandlerBase, object metav1.ObjectMetaAccessor) (bool, error) {
+	if allObjectsValidator, ok := webhookHandler.(WebhookAllObjectsValidator); ok && allObjectsValidator.ValidateAllObjects() {
+		return true, nil
+	}
+
+	ownerClusterGetter := func(objectMeta metav1.ObjectMetaAccessor) (capi.Cluster, bool, error) {
+		ownerCluster, ok, err := generic.TryGetOwnerCluster(ctx, h.ctrlClient, object)
+		if err != nil {
+			return capi.Cluster{}, false, microerror.Mask(err)
+		}
+
+		return ownerCluster, ok, nil
+	}
+
+	ok, err := filter.IsObjectReconciledByLegacyRelease(ctx, h.logger, h.ctrlReader, object, ownerClusterGetter)
+	if err != nil {
+		return false, microerror.Mask(err)
+	}
+
+	return ok, nil
 }
 
 // newHttpHandler returns a HTTP handler for validating a request with the specified validation
@@ -137,10 +240,13 @@ func (h *HttpHandlerFactory) NewUpdateHandler(webhookUpdateHandler WebhookUpdate
 // This function is basically the same as the existing Handler func, with the only difference that
 // it is now wrapped into the New...Handler funcs above in order to first decode the CR and check
 // if it should be validated by azure-admission-controller.
-func (h *HttpHandlerFactory) newHttpHandler(webhookHandler WebhookHandlerBase, validateFunc func(ctx context.Context, review v1beta1.AdmissionReview) error) http.HandlerFunc {
+func (h *HttpHandlerFactory) newHttpHandler(webhookHandler WebhookHandlerBase, operation string, validateFunc func(ctx context.Context, admissionRequest *admissionv1.AdmissionRequest) ([]string, error)) http.HandlerFunc {
 	return func(writer http.ResponseWriter, request *http.Request) {
+		requestMetrics := metrics.NewWebhookRequest(metrics.WebhookValidate, webhookHandler.Resource(), operation)
+
 		if request.Header.Get("Content-Type") != "application/json" {
 			webhookHandler.Log("level", "error", "message", fmt.Sprintf("invalid content-type: %s", request.Header.Get("Content-Type")))
+			requestMetrics.Errored(nil)
 			writer.WriteHeader(http.StatusBadRequest)
 			return
 		}
@@ -148,26 +254,37 @@ func (h *HttpHandlerFactory) newHttpHandler(webhookHandler WebhookHandlerBase, v
 		data, err := ioutil.ReadAll(request.Body)
 		if err != nil {
 			webhookHandler.Log("level", "error", "message", "unable to read request")
+			requestMetrics.Errored(err)
 			writer.WriteHeader(http.StatusInternalServerError)
 			return
 		}
 
-		review := v1beta1.AdmissionReview{}
-		if _, _, err := Deserializer.Decode(data, nil, &review); err != nil {
-			webhookHandler.Log("level", "error", "message", "unable to parse admission review request")
+		review, err := admission.DecodeReview(data)
+		if err != nil {
+			webhookHandler.Log("level", "error", "message", "unable to parse admission review request", "stack", microerror.JSON(err))
+			requestMetrics.Errored(err)
 			writer.WriteHeader(http.StatusBadRequest)
 			return
 		}
 
-		err = validateFunc(request.Context(), review)
+		// Checks in warn and audit mode don't deny the request, see
+		// enforcement.Apply.
+		ctx := enforcement.NewContext(request.Context(), h.enforcement, h.logger)
+
+		warnings, err := validateFunc(ctx, review.Request)
 		if err != nil {
-			writeResponse(webhookHandler, writer, errorResponse(review.Request.UID, microerror.Mask(err)))
+			requestMetrics.Denied(err)
+			writeResponse(webhookHandler, writer, review, errorResponse(review.Request.UID, microerror.Mask(err)))
 			return
 		}
 
-		writeResponse(webhookHandler, writer, &v1beta1.AdmissionResponse{
-			Allowed: true,
-			UID:     review.Request.UID,
+		warnings = append(enforcement.Warnings(ctx), warnings...)
+
+		requestMetrics.Allowed(0)
+		writeResponse(webhookHandler, writer, review, &admissionv1.AdmissionResponse{
+			Allowed:  true,
+			UID:      review.Request.UID,
+			Warnings: warnings,
 		})
 	}
 }
@@ -16,13 +16,15 @@ import (
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
 	releasev1alpha1 "github.com/giantswarm/release-operator/v3/api/v1alpha1"
-	admission "k8s.io/api/admission/v1beta1"
+	admission "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
+	"github.com/giantswarm/azure-admission-controller/internal/enforcement"
 	builder "github.com/giantswarm/azure-admission-controller/internal/test/cluster"
+	pkgadmission "github.com/giantswarm/azure-admission-controller/pkg/admission"
 	"github.com/giantswarm/azure-admission-controller/pkg/release"
 	"github.com/giantswarm/azure-admission-controller/pkg/unittest"
 )
@@ -41,6 +43,10 @@ const (
 	capiRelease = "20.0.0-v1alpha3"
 )
 
+var testValidationError = &microerror.Error{
+	Kind: "testValidationError",
+}
+
 type object interface {
 	runtime.Object
 	metav1.ObjectMetaAccessor
@@ -48,11 +54,15 @@ type object interface {
 
 func TestHttpHandler(t *testing.T) {
 	type testCase struct {
-		name          string
-		object        object
-		oldObject     object
-		operation     admission.Operation
-		expectedError *microerror.Error
+		name            string
+		apiVersion      string
+		dryRun          bool
+		object          object
+		oldObject       object
+		operation       admission.Operation
+		validationError error
+		warnings        []string
+		expectedError   *microerror.Error
 	}
 
 	testCases := []testCase{
@@ -84,6 +94,90 @@ func TestHttpHandler(t *testing.T) {
 			expectedError: release.ReleaseNotFoundError,
 			operation:     admission.Create,
 		},
+		{
+			name:       "Validate Cluster creation for legacy releases with admission.k8s.io/v1 review",
+			apiVersion: pkgadmission.APIVersionV1,
+			object: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				})),
+			operation: admission.Create,
+		},
+		{
+			name:       "Validate Cluster creation for legacy releases with admission.k8s.io/v1beta1 review",
+			apiVersion: pkgadmission.APIVersionV1beta1,
+			object: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				})),
+			operation: admission.Create,
+		},
+		{
+			name:       "Validate Cluster creation for non-existing releases with admission.k8s.io/v1 review",
+			apiVersion: pkgadmission.APIVersionV1,
+			object: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: "0.0.0-NonExistentRelease",
+				})),
+			expectedError: release.ReleaseNotFoundError,
+			operation:     admission.Create,
+		},
+		{
+			name: "Validate Cluster creation for legacy releases with warnings",
+			object: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				})),
+			warnings:  []string{"Release is deprecated."},
+			operation: admission.Create,
+		},
+		{
+			name:       "Validate Cluster creation for legacy releases with warnings and admission.k8s.io/v1beta1 review",
+			apiVersion: pkgadmission.APIVersionV1beta1,
+			object: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				})),
+			warnings:  []string{"Release is deprecated."},
+			operation: admission.Create,
+		},
+		{
+			name:   "Validate Cluster creation for legacy releases on dry-run",
+			dryRun: true,
+			object: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				})),
+			validationError: microerror.Mask(testValidationError),
+			expectedError:   testValidationError,
+			operation:       admission.Create,
+		},
+		{
+			name: "Validate Cluster deletion for legacy releases",
+			oldObject: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				})),
+			operation: admission.Delete,
+		},
+		{
+			name: "Validate protected Cluster deletion for legacy releases",
+			oldObject: builder.BuildCluster(
+				builder.Name("ab123"),
+				builder.Labels(map[string]string{
+					label.ReleaseVersion: legacyRelease,
+				})),
+			validationError: microerror.Mask(testValidationError),
+			expectedError:   testValidationError,
+			operation:       admission.Delete,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -103,12 +197,20 @@ func TestHttpHandler(t *testing.T) {
 			// Validation logic itself here does not matter, so we are using a generic
 			// WebhookHandlerMock as a WebhookCreateHandler/WebhookUpdateHandler interface implementation.
 			//
+			enforcementRegistry, err := enforcement.NewRegistry(enforcement.RegistryConfig{
+				Logger: logger,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
 			var httpHandlerFactory *HttpHandlerFactory
 			{
 				c := HttpHandlerFactoryConfig{
-					CtrlReader: ctrlClient, // Passing client here, for the sake of simpler test code
-					CtrlClient: ctrlClient,
-					Logger:     logger,
+					CtrlReader:  ctrlClient, // Passing client here, for the sake of simpler test code
+					CtrlClient:  ctrlClient,
+					Enforcement: enforcementRegistry,
+					Logger:      logger,
 				}
 				httpHandlerFactory, err = NewHttpHandlerFactory(c)
 				if err != nil {
@@ -118,8 +220,14 @@ func TestHttpHandler(t *testing.T) {
 
 			webhookHandlerMock := WebhookHandlerMock{
 				DecodeFunc: func(runtime.RawExtension) (metav1.ObjectMetaAccessor, error) {
+					// Delete requests only contain the old object.
+					if tc.operation == admission.Delete {
+						return tc.oldObject, nil
+					}
 					return tc.object, nil
 				},
+				Err:      tc.validationError,
+				Warnings: tc.warnings,
 			}
 
 			var httpHandler http.HandlerFunc
@@ -128,6 +236,8 @@ func TestHttpHandler(t *testing.T) {
 				httpHandler = httpHandlerFactory.NewCreateHandler(&webhookHandlerMock)
 			case admission.Update:
 				httpHandler = httpHandlerFactory.NewUpdateHandler(&webhookHandlerMock)
+			case admission.Delete:
+				httpHandler = httpHandlerFactory.NewDeleteHandler(&webhookHandlerMock)
 			default:
 				t.Fatal("Unsupported operation")
 			}
@@ -138,7 +248,7 @@ func TestHttpHandler(t *testing.T) {
 			// That request will contain admission review for creating/updating an object. This is
 			// basically the request body that API server would send to the webhook.
 			//
-			admissionReviewJson := getAdmissionReview(t, tc.operation, tc.object, tc.oldObject)
+			admissionReviewJson := getAdmissionReview(t, tc.apiVersion, tc.dryRun, tc.operation, tc.object, tc.oldObject)
 			request := getHttpRequest(t, admissionReviewJson)
 
 			//
@@ -156,9 +266,27 @@ func TestHttpHandler(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			// The response must be sent in the same version as the request. Reviews without
+			// apiVersion are treated as admission.k8s.io/v1beta1.
+			expectedAPIVersion := tc.apiVersion
+			if expectedAPIVersion == "" {
+				expectedAPIVersion = pkgadmission.APIVersionV1beta1
+			}
+			if admissionReview.APIVersion != expectedAPIVersion {
+				t.Fatalf("expected response apiVersion %q, got %q", expectedAPIVersion, admissionReview.APIVersion)
+			}
+
 			//
 			// Now let's check the handler response.
 			//
+			if admissionReview.Response.Allowed && len(admissionReview.Response.Warnings) != len(tc.warnings) {
+				t.Fatalf("expected %d warnings, got %d", len(tc.warnings), len(admissionReview.Response.Warnings))
+			}
+
+			if admissionReview.Response.Allowed && tc.validationError != nil {
+				t.Fatalf("Request is allowed. Expected validation error '%s'.", tc.validationError)
+			}
+
 			if !admissionReview.Response.Allowed {
 				// webhook handler returned an error and it is rejecting the request
 
@@ -210,22 +338,32 @@ func getHttpRequest(t *testing.T, admissionReview []byte) *http.Request {
 	return request
 }
 
-func getAdmissionReview(t *testing.T, operation admission.Operation, object runtime.Object, oldObject runtime.Object) []byte {
-	objectJson, err := json.Marshal(object)
-	if err != nil {
-		t.Fatal(err)
+func getAdmissionReview(t *testing.T, apiVersion string, dryRun bool, operation admission.Operation, object object, oldObject object) []byte {
+	// Delete requests only contain the old object.
+	resourceObject := object
+	if resourceObject == nil {
+		resourceObject = oldObject
 	}
 
 	admissionRequest := &admission.AdmissionRequest{
 		Resource: metav1.GroupVersionResource{
-			Version:  object.GetObjectKind().GroupVersionKind().GroupVersion().String(),
-			Resource: object.GetObjectKind().GroupVersionKind().Kind,
+			Version:  resourceObject.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Resource: resourceObject.GetObjectKind().GroupVersionKind().Kind,
 		},
 		Operation: operation,
-		Object: runtime.RawExtension{
+		DryRun:    &dryRun,
+	}
+
+	if object != nil {
+		objectJson, err := json.Marshal(object)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		admissionRequest.Object = runtime.RawExtension{
 			Raw:    objectJson,
 			Object: nil,
-		},
+		}
 	}
 
 	if oldObject != nil {
@@ -240,9 +378,13 @@ func getAdmissionReview(t *testing.T, operation admission.Operation, object runt
 		}
 	}
 
+	// admission.k8s.io/v1 and admission.k8s.io/v1beta1 reviews have the same
+	// structure, so only the apiVersion needs to be set here.
 	admissionReview := admission.AdmissionReview{
-		TypeMeta: metav1.TypeMeta{},
-		Request:  admissionRequest,
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiVersion,
+		},
+		Request: admissionRequest,
 	}
 
 	admissionReviewJson, err := json.Marshal(admissionReview)
@@ -21,3 +21,39 @@ type WebhookUpdateHandler interface {
 	WebhookHandlerBase
 	OnUpdateValidate(ctx context.Context, oldObject interface{}, object interface{}) error
 }
+
+// WebhookStatusUpdateHandler validates updates of the status subresource. The
+// API server ignores status changes in updates of the object itself, and spec
+// changes in updates of the status subresource, so checks of the status only
+// make sense here.
+type WebhookStatusUpdateHandler interface {
+	WebhookHandlerBase
+	OnStatusUpdateValidate(ctx context.Context, oldObject interface{}, object interface{}) error
+}
+
+type WebhookDeleteHandler interface {
+	WebhookHandlerBase
+	OnDeleteValidate(ctx context.Context, object interface{}) error
+}
+
+// WebhookCreateWarner can be implemented by a WebhookCreateHandler to return
+// warnings for soft policy violations. Warnings are shown to the user (e.g. by
+// kubectl), but they never deny the request.
+type WebhookCreateWarner interface {
+	OnCreateWarn(ctx context.Context, object interface{}) ([]string, error)
+}
+
+// WebhookUpdateWarner can be implemented by a WebhookUpdateHandler to return
+// warnings for soft policy violations. Warnings are shown to the user (e.g. by
+// kubectl), but they never deny the request.
+type WebhookUpdateWarner interface {
+	OnUpdateWarn(ctx context.Context, oldObject interface{}, object interface{}) ([]string, error)
+}
+
+// WebhookAllObjectsValidator can be implemented by a handler to validate all
+// objects of its resource. By default only objects reconciled by a legacy
+// release are validated, see filter.IsObjectReconciledByLegacyRelease, which
+// skips objects that neither have a release label nor belong to a cluster.
+type WebhookAllObjectsValidator interface {
+	ValidateAllObjects() bool
+}
@@ -9,6 +9,8 @@ import (
 
 type WebhookHandlerMock struct {
 	DecodeFunc func(runtime.RawExtension) (metav1.ObjectMetaAccessor, error)
+	Err        error
+	Warnings   []string
 }
 
 func (h *WebhookHandlerMock) Log(_ ...interface{}) {}
@@ -22,9 +24,21 @@ func (h *WebhookHandlerMock) Decode(object runtime.RawExtension) (metav1.ObjectM
 }
 
 func (h *WebhookHandlerMock) OnCreateValidate(_ context.Context, _ interface{}) error {
-	return nil
+	return h.Err
 }
 
 func (h *WebhookHandlerMock) OnUpdateValidate(_ context.Context, _ interface{}, _ interface{}) error {
-	return nil
+	return h.Err
+}
+
+func (h *WebhookHandlerMock) OnDeleteValidate(_ context.Context, _ interface{}) error {
+	return h.Err
+}
+
+func (h *WebhookHandlerMock) OnCreateWarn(_ context.Context, _ interface{}) ([]string, error) {
+	return h.Warnings, nil
+}
+
+func (h *WebhookHandlerMock) OnUpdateWarn(_ context.Context, _ interface{}, _ interface{}) ([]string, error) {
+	return h.Warnings, nil
 }